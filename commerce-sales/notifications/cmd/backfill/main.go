@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"notifications/config"
+	"notifications/internal/core/port/driven"
+	"notifications/pkg/logger"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/adapter/blockchain"
+	"notifications/internal/adapter/notification"
+	"notifications/internal/adapter/output"
+	"notifications/internal/core/application"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run backfills the requested block range and returns the exit code. Errors are returned rather
+// than fatal, so the listener and the notifiers are closed before the process exits.
+func run() int {
+	fromBlock := flag.Uint64("from", 0, "first block to process (required)")
+	toBlock := flag.Uint64("to", 0, "last block to process (default: current block at confirmation depth)")
+	chunkSize := flag.Uint64("chunk-size", 0, "maximum blocks per eth_getLogs request (default: LOG_CHUNK_SIZE)")
+	dryRun := flag.Bool("dry-run", false, "print decoded events as JSON lines instead of sending notifications")
+	flag.Parse()
+
+	// -from 0 is the genesis block, so whether the flag was given is checked instead of its value
+	fromSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "from" {
+			fromSet = true
+		}
+	})
+	if !fromSet {
+		fmt.Fprintln(os.Stderr, "-from is required")
+		flag.Usage()
+		return 2
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	if *chunkSize == 0 {
+		*chunkSize = cfg.LogChunkSize
+	}
+
+	// Initialize logger (writes to stderr, so dry-run output on stdout stays clean)
+	log, err := logger.New(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		return 1
+	}
+	defer log.Sync()
+
+	log.Info("Starting Blockchain Event Backfill",
+		zap.String("rpcURL", cfg.BlockchainRPCURL),
+		zap.String("contractAddress", cfg.ContractAddress),
+		zap.Uint64("fromBlock", *fromBlock),
+		zap.Uint64("toBlock", *toBlock),
+		zap.Uint64("chunkSize", *chunkSize),
+		zap.Bool("dryRun", *dryRun),
+	)
+
+	// Stop cleanly on interrupt
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Select the processors: print events on dry runs, notify otherwise
+	var processors []driven.EventProcessor
+	if *dryRun {
+		processors = append(processors, output.NewEventPrinter(os.Stdout))
+	} else {
+		notifier, closeNotifier, err := notification.NewFromConfig(ctx, cfg, nil, log)
+		if err != nil {
+			log.Error("Failed to create notifier", zap.Error(err))
+			return 1
+		}
+		defer closeNotifier()
+
+		processors = append(processors,
+			// Customers aren't alerted again about violations replayed by a backfill
+			application.NewSLAEventProcessor(log, notifier),
+			application.NewContractEventProcessor(log, notifier),
+		)
+	}
+
+	// The backfill doesn't use a checkpoint store so it never moves the listener's checkpoint,
+	// nor an idempotency store, since replaying already notified events is its purpose
+	ethListener, err := blockchain.NewEthereumListener(blockchain.ListenerConfig{
+		RPCURL:            cfg.BlockchainRPCURL,
+		ContractAddress:   cfg.ContractAddress,
+		StartBlock:        *fromBlock,
+		ConfirmationDepth: cfg.ConfirmationDepth,
+		ChunkSize:         *chunkSize,
+		ReconnectInterval: time.Duration(cfg.ReconnectInterval) * time.Second,
+		Dispatch: blockchain.DispatchConfig{
+			Workers:      cfg.DispatchWorkers,
+			QueueSize:    cfg.DispatchQueueSize,
+			DrainTimeout: time.Duration(cfg.DispatchDrainTimeout) * time.Second,
+		},
+	}, log, processors...)
+	if err != nil {
+		log.Error("Failed to create Ethereum listener", zap.Error(err))
+		return 1
+	}
+	defer ethListener.Stop()
+
+	if err := ethListener.Backfill(ctx, *fromBlock, *toBlock); err != nil {
+		log.Error("Backfill failed", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
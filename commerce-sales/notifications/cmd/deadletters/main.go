@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"notifications/config"
+	"notifications/internal/core/port/driven"
+	"notifications/pkg/logger"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"notifications/internal/adapter/blockchain"
+	"notifications/internal/adapter/customer"
+	"notifications/internal/adapter/deadletter"
+	"notifications/internal/adapter/notification"
+	"notifications/internal/core/application"
+)
+
+const usage = `Usage:
+  deadletters list [-json]        list dead-lettered events
+  deadletters replay -id <id>     replay one dead letter through its processor
+  deadletters replay -all         replay every dead letter
+
+Replayed dead letters are deleted; failed replays stay with their error updated.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger (writes to stderr, so the listing on stdout stays clean)
+	log, err := logger.New(cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	// Stop cleanly on interrupt
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	store, closeStore, err := deadletter.NewFromConfig(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to create dead-letter store", zap.Error(err))
+	}
+	defer closeStore()
+
+	if store == nil {
+		log.Fatal("DEAD_LETTER_STORE is none, there are no dead letters to manage")
+	}
+
+	switch os.Args[1] {
+	case "list":
+		flags := flag.NewFlagSet("list", flag.ExitOnError)
+		asJSON := flags.Bool("json", false, "print dead letters as JSON lines")
+		flags.Parse(os.Args[2:])
+
+		if err := list(ctx, store, *asJSON); err != nil {
+			log.Fatal("Failed to list dead letters", zap.Error(err))
+		}
+	case "replay":
+		flags := flag.NewFlagSet("replay", flag.ExitOnError)
+		id := flags.String("id", "", "ID of the dead letter to replay")
+		all := flags.Bool("all", false, "replay every dead letter")
+		flags.Parse(os.Args[2:])
+
+		if (*id == "") == !*all {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+
+		service, closeService, err := newDeadLetterService(ctx, cfg, store, log)
+		if err != nil {
+			log.Fatal("Failed to create processors", zap.Error(err))
+		}
+		defer closeService()
+
+		if *all {
+			replayed, err := service.ReplayAll(ctx)
+			if err != nil {
+				log.Fatal("Failed to replay dead letters", zap.Error(err))
+			}
+			log.Info("Dead letters replayed", zap.Int("replayed", replayed))
+			return
+		}
+
+		if err := service.Replay(ctx, *id); err != nil {
+			log.Fatal("Failed to replay dead letter", zap.Error(err))
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// list prints the dead letters as a table, or as JSON lines
+func list(ctx context.Context, store driven.DeadLetterStore, asJSON bool) error {
+	deadLetters, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, deadLetter := range deadLetters {
+			if err := encoder.Encode(deadLetter); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFAILED AT\tPROCESSOR\tEVENT\tKEY\tATTEMPTS\tERROR")
+	for _, d := range deadLetters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			d.ID, d.FailedAt.Format(time.RFC3339), d.Processor, d.EventType, d.IdempotencyKey, d.Attempts, d.Error)
+	}
+	return w.Flush()
+}
+
+// newDeadLetterService creates the service with the same processors as the listener, without retries.
+// The returned function releases their resources.
+func newDeadLetterService(ctx context.Context, cfg *config.Config, store driven.DeadLetterStore, log *zap.Logger) (*application.DeadLetterService, func(), error) {
+	notifier, closeNotifier, err := notification.NewFromConfig(ctx, cfg, nil, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeAll := closeNotifier
+
+	processors := []driven.EventProcessor{
+		application.NewSLAEventProcessor(log, notifier),
+		application.NewContractEventProcessor(log, notifier),
+	}
+
+	if cfg.CustomerAlertsEnabled {
+		registry, err := blockchain.NewContractRegistry(cfg.BlockchainRPCURL, cfg.ContractAddress, log)
+		if err != nil {
+			closeNotifier()
+			return nil, nil, err
+		}
+		closeAll = func() {
+			registry.Close()
+			closeNotifier()
+		}
+
+		channels, ops, err := notification.NewAlertChannelsFromConfig(ctx, cfg, log)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+
+		directory := customer.NewHTTPDirectory(cfg.ContractsAPIURL, time.Duration(cfg.ContractsAPITimeout)*time.Second)
+		alerter := application.NewCustomerAlerter(log, registry, directory, channels, ops)
+		processors = append(processors, application.NewSLAViolationAlertProcessor(log, alerter))
+	}
+
+	service := application.NewDeadLetterService(log, store, processors...)
+
+	return service, closeAll, nil
+}
@@ -2,16 +2,26 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"notifications/config"
+	"notifications/internal/core/port/driven"
 	"notifications/pkg/logger"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"notifications/internal/adapter/blockchain"
+	"notifications/internal/adapter/checkpoint"
+	"notifications/internal/adapter/customer"
+	"notifications/internal/adapter/deadletter"
+	"notifications/internal/adapter/health"
+	"notifications/internal/adapter/idempotency"
+	"notifications/internal/adapter/metrics"
 	"notifications/internal/adapter/notification"
 	"notifications/internal/core/application"
 )
@@ -36,6 +46,7 @@ func main() {
 		zap.String("rpcURL", cfg.BlockchainRPCURL),
 		zap.String("contractAddress", cfg.ContractAddress),
 		zap.Uint64("startBlock", cfg.StartBlock),
+		zap.Uint64("confirmationDepth", cfg.ConfirmationDepth),
 		zap.String("logLevel", cfg.LogLevel),
 	)
 
@@ -43,34 +54,102 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize SNS notifier if enabled
-	log.Info("Initializing SNS notifier",
-		zap.String("topicARN", cfg.SNSTopicARN),
-		zap.String("region", cfg.AWSRegion),
-	)
+	// Initialize the Prometheus metrics, served with the health endpoints
+	var prometheusMetrics *metrics.PrometheusMetrics
+	var recorder driven.Metrics
+	if cfg.HealthAddr != "" {
+		prometheusMetrics = metrics.NewPrometheusMetrics()
+		recorder = prometheusMetrics
+	}
 
-	snsNotifier, err := notification.NewSNSNotifier(ctx, cfg.AWSRegion, cfg.SNSTopicARN, log)
+	// Initialize the notifier backends selected by NOTIFIERS
+	notifier, closeNotifier, err := notification.NewFromConfig(ctx, cfg, recorder, log)
 	if err != nil {
-		log.Fatal("Failed to create SNS notifier", zap.Error(err))
+		log.Fatal("Failed to create notifier", zap.Error(err))
 	}
+	defer closeNotifier()
 
-	log.Info("SNS notifier initialized successfully")
+	log.Info("Notifier initialized successfully", zap.Strings("backends", cfg.Notifiers))
 
-	// Initialize specific event processors
-	contractEventProcessor := application.NewContractEventProcessor(log, snsNotifier)
-	slaEventProcessor := application.NewSLAEventProcessor(log, snsNotifier)
+	// Initialize the customer alerter for SLA violations if enabled
+	var alerter *application.CustomerAlerter
+	if cfg.CustomerAlertsEnabled {
+		var closeAlerter func()
+		alerter, closeAlerter, err = newCustomerAlerter(ctx, cfg, log)
+		if err != nil {
+			log.Fatal("Failed to create customer alerter", zap.Error(err))
+		}
+		defer closeAlerter()
 
-	// Initialize Ethereum listener (inbound adapter)
-	// Method 1: Pass the first processor in the constructor
-	ethListener, err := blockchain.NewEthereumListener(
-		cfg.BlockchainRPCURL,
-		cfg.ContractAddress,
-		cfg.StartBlock,
-		cfg.ReconnectInterval,
-		log,
-		slaEventProcessor, // First processor passed in constructor
-		contractEventProcessor,
+		log.Info("Customer alerts enabled", zap.String("contractsAPI", cfg.ContractsAPIURL))
+	}
+
+	// Initialize the dead-letter store for events that still fail after all retries
+	deadLetters, closeDeadLetters, err := deadletter.NewFromConfig(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to create dead-letter store", zap.Error(err))
+	}
+	defer closeDeadLetters()
+
+	log.Info("Dead-letter store initialized", zap.String("store", cfg.DeadLetterStore))
+
+	retryPolicy := application.RetryPolicy{
+		MaxAttempts:    cfg.RetryMaxAttempts,
+		InitialBackoff: time.Duration(cfg.RetryInitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.RetryMaxBackoffMs) * time.Millisecond,
+	}
+
+	// Initialize specific event processors, each retried on its own
+	contractEventProcessor := application.NewRetryingProcessor(log,
+		instrument(application.NewContractEventProcessor(log, notifier), recorder), retryPolicy, deadLetters)
+	slaEventProcessor := application.NewRetryingProcessor(log,
+		instrument(application.NewSLAEventProcessor(log, notifier), recorder), retryPolicy, deadLetters)
+	processors := []driven.EventProcessor{slaEventProcessor, contractEventProcessor}
+
+	// Alert customers apart from the SLAViolated notification, so neither is sent again when the other fails
+	if alerter != nil {
+		processors = append(processors, application.NewRetryingProcessor(log,
+			instrument(application.NewSLAViolationAlertProcessor(log, alerter), recorder), retryPolicy, deadLetters))
+	}
+
+	// Initialize the checkpoint store so the listener resumes where it stopped
+	checkpoints, closeCheckpoints, err := newCheckpointStore(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to create checkpoint store", zap.Error(err))
+	}
+	defer closeCheckpoints()
+
+	log.Info("Checkpoint store initialized", zap.String("store", cfg.CheckpointStore))
+
+	// Initialize the idempotency store so a log delivered twice is only notified once
+	idempotencyStore, closeIdempotency, err := newIdempotencyStore(ctx, cfg)
+	if err != nil {
+		log.Fatal("Failed to create idempotency store", zap.Error(err))
+	}
+	defer closeIdempotency()
+
+	log.Info("Idempotency store initialized",
+		zap.String("store", cfg.IdempotencyStore),
+		zap.Int("ttlSeconds", cfg.IdempotencyTTL),
 	)
+
+	// Initialize Ethereum listener (inbound adapter)
+	ethListener, err := blockchain.NewEthereumListener(blockchain.ListenerConfig{
+		RPCURL:            cfg.BlockchainRPCURL,
+		ContractAddress:   cfg.ContractAddress,
+		StartBlock:        cfg.StartBlock,
+		ConfirmationDepth: cfg.ConfirmationDepth,
+		ChunkSize:         cfg.LogChunkSize,
+		ReconnectInterval: time.Duration(cfg.ReconnectInterval) * time.Second,
+		Dispatch: blockchain.DispatchConfig{
+			Workers:      cfg.DispatchWorkers,
+			QueueSize:    cfg.DispatchQueueSize,
+			DrainTimeout: time.Duration(cfg.DispatchDrainTimeout) * time.Second,
+		},
+		Checkpoints: checkpoints,
+		Idempotency: idempotencyStore,
+		Metrics:     recorder,
+	}, log, processors...)
 	if err != nil {
 		log.Fatal("Failed to create Ethereum listener", zap.Error(err))
 	}
@@ -82,6 +161,15 @@ func main() {
 		log.Fatal("Failed to start Ethereum listener", zap.Error(err))
 	}
 
+	// Serve the health, readiness and metrics endpoints
+	var healthServer *health.Server
+	if cfg.HealthAddr != "" {
+		healthServer = health.NewServer(cfg.HealthAddr, ethListener, cfg.ReadyMaxBlockLag, prometheusMetrics.Handler(), log)
+		if err := healthServer.Start(); err != nil {
+			log.Fatal("Failed to start health server", zap.Error(err))
+		}
+	}
+
 	log.Info("Blockchain Event Listener is running. Press Ctrl+C to stop.")
 
 	// Wait for the interrupt signal for graceful shutdown
@@ -100,5 +188,106 @@ func main() {
 		log.Error("Error stopping Ethereum listener", zap.Error(err))
 	}
 
+	// Stop the health server
+	if healthServer != nil {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := healthServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("Error stopping health server", zap.Error(err))
+		}
+		cancelShutdown()
+	}
+
 	log.Info("Blockchain Event Listener stopped successfully")
 }
+
+// instrument wraps the processor so its latency and failures are recorded, when metrics are enabled
+func instrument(processor driven.EventProcessor, metrics driven.Metrics) driven.EventProcessor {
+	if metrics == nil {
+		return processor
+	}
+	return application.NewInstrumentedProcessor(processor, metrics)
+}
+
+// newCustomerAlerter creates the customer alerter from the on-chain contract registry, the contracts
+// service customer API and the configured alert channels. The returned function releases its resources.
+func newCustomerAlerter(ctx context.Context, cfg *config.Config, log *zap.Logger) (*application.CustomerAlerter, func(), error) {
+	registry, err := blockchain.NewContractRegistry(cfg.BlockchainRPCURL, cfg.ContractAddress, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	directory := customer.NewHTTPDirectory(cfg.ContractsAPIURL, time.Duration(cfg.ContractsAPITimeout)*time.Second)
+
+	channels, ops, err := notification.NewAlertChannelsFromConfig(ctx, cfg, log)
+	if err != nil {
+		registry.Close()
+		return nil, nil, err
+	}
+
+	return application.NewCustomerAlerter(log, registry, directory, channels, ops), registry.Close, nil
+}
+
+// newCheckpointStore creates the checkpoint store selected by CHECKPOINT_STORE.
+// The returned function releases its resources.
+func newCheckpointStore(ctx context.Context, cfg *config.Config) (driven.CheckpointStore, func(), error) {
+	switch cfg.CheckpointStore {
+	case "file":
+		store, err := checkpoint.NewFileStore(cfg.CheckpointFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, func() {}, nil
+	case "postgres":
+		db, err := openDatabase(ctx, cfg.CheckpointDatabaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		store, err := checkpoint.NewSQLStore(ctx, db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return store, func() { db.Close() }, nil
+	default:
+		return nil, func() {}, nil
+	}
+}
+
+// newIdempotencyStore creates the idempotency store selected by IDEMPOTENCY_STORE.
+// The returned function releases its resources.
+func newIdempotencyStore(ctx context.Context, cfg *config.Config) (driven.IdempotencyStore, func(), error) {
+	ttl := time.Duration(cfg.IdempotencyTTL) * time.Second
+
+	switch cfg.IdempotencyStore {
+	case "memory":
+		return idempotency.NewMemoryStore(ttl, cfg.IdempotencyMaxEntries), func() {}, nil
+	case "postgres":
+		db, err := openDatabase(ctx, cfg.IdempotencyDatabaseURL)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		store, err := idempotency.NewSQLStore(ctx, db, ttl)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return store, func() { db.Close() }, nil
+	default:
+		return nil, func() {}, nil
+	}
+}
+
+// openDatabase opens a PostgreSQL connection pool and verifies the connection
+func openDatabase(ctx context.Context, url string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 // Config holds all application configuration
@@ -12,13 +13,72 @@ type Config struct {
 	BlockchainRPCURL  string
 	ContractAddress   string
 	StartBlock        uint64
-	ReconnectInterval int // seconds
+	ConfirmationDepth uint64 // blocks a log must be buried under before it is dispatched
+	LogChunkSize      uint64 // maximum blocks per eth_getLogs request
+	ReconnectInterval int    // seconds
+
+	// Dispatch configuration
+	DispatchWorkers      int // events of different contracts processed in parallel
+	DispatchQueueSize    int // events buffered per worker before the listener waits
+	DispatchDrainTimeout int // seconds
+
+	// Checkpoint configuration
+	CheckpointStore       string // none, file or postgres
+	CheckpointFile        string
+	CheckpointDatabaseURL string
+
+	// Idempotency configuration
+	IdempotencyStore       string // none, memory or postgres
+	IdempotencyTTL         int    // seconds
+	IdempotencyMaxEntries  int    // memory store only
+	IdempotencyDatabaseURL string // defaults to CHECKPOINT_DATABASE_URL
+
+	// Retry and dead-letter configuration
+	RetryMaxAttempts      int // total attempts per processor, including the first one
+	RetryInitialBackoffMs int
+	RetryMaxBackoffMs     int
+	DeadLetterStore       string // none, file or postgres
+	DeadLetterFile        string
+	DeadLetterDatabaseURL string // defaults to CHECKPOINT_DATABASE_URL
+
+	// Notifier backends (sns, webhook, email, rabbitmq); defaults to sns when SNS_ENABLED is true
+	Notifiers []string
 
 	// AWS SNS configuration
 	SNSEnabled  bool
 	SNSTopicARN string
 	AWSRegion   string
 
+	// Webhook configuration
+	WebhookURL     string
+	WebhookSecret  string // HMAC-SHA256 signing key, payloads are unsigned when empty
+	WebhookTimeout int    // seconds
+
+	// SMTP email configuration
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUsername     string
+	SMTPPassword     string
+	SMTPTimeout      int // seconds
+	EmailFrom        string
+	EmailTo          []string
+	EmailTemplateDir string // optional directory with <EventType>.tmpl overrides
+
+	// RabbitMQ configuration
+	RabbitMQURL      string
+	RabbitMQExchange string
+
+	// Customer alert configuration
+	CustomerAlertsEnabled bool
+	ContractsAPIURL       string // base URL of the contracts service, e.g. http://contracts:8080
+	ContractsAPITimeout   int    // seconds
+	AlertSMSEnabled       bool   // send SMS alerts through SNS
+	OpsSNSTopicARN        string // fallback topic when a customer can't be alerted
+
+	// Health and metrics endpoint configuration
+	HealthAddr       string // listen address of /healthz, /readyz and /metrics; empty disables them
+	ReadyMaxBlockLag uint64 // confirmed blocks the listener may be behind and still be ready
+
 	// Logging configuration
 	LogLevel  string
 	LogFormat string // json or console
@@ -27,15 +87,59 @@ type Config struct {
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	cfg := &Config{
-		BlockchainRPCURL:  getEnv("BLOCKCHAIN_RPC_URL", ""),
-		ContractAddress:   getEnv("CONTRACT_ADDRESS", ""),
-		StartBlock:        getEnvAsUint64("START_BLOCK", 0),
-		ReconnectInterval: getEnvAsInt("RECONNECT_INTERVAL", 5),
-		SNSEnabled:        getEnvAsBool("SNS_ENABLED", false),
-		SNSTopicARN:       getEnv("SNS_TOPIC_ARN", ""),
-		AWSRegion:         getEnv("AWS_REGION", "us-east-1"),
-		LogLevel:          getEnv("LOG_LEVEL", "info"),
-		LogFormat:         getEnv("LOG_FORMAT", "console"),
+		BlockchainRPCURL:      getEnv("BLOCKCHAIN_RPC_URL", ""),
+		ContractAddress:       getEnv("CONTRACT_ADDRESS", ""),
+		StartBlock:            getEnvAsUint64("START_BLOCK", 0),
+		ConfirmationDepth:     getEnvAsUint64("CONFIRMATION_DEPTH", 0),
+		LogChunkSize:          getEnvAsUint64("LOG_CHUNK_SIZE", 2000),
+		ReconnectInterval:     getEnvAsInt("RECONNECT_INTERVAL", 5),
+		DispatchWorkers:       getEnvAsInt("DISPATCH_WORKERS", 4),
+		DispatchQueueSize:     getEnvAsInt("DISPATCH_QUEUE_SIZE", 100),
+		DispatchDrainTimeout:  getEnvAsInt("DISPATCH_DRAIN_TIMEOUT", 30),
+		CheckpointStore:       getEnv("CHECKPOINT_STORE", "none"),
+		CheckpointFile:        getEnv("CHECKPOINT_FILE", "data/checkpoint.json"),
+		CheckpointDatabaseURL: getEnv("CHECKPOINT_DATABASE_URL", ""),
+		IdempotencyStore:      getEnv("IDEMPOTENCY_STORE", "memory"),
+		IdempotencyTTL:        getEnvAsInt("IDEMPOTENCY_TTL", 86400),
+		IdempotencyMaxEntries: getEnvAsInt("IDEMPOTENCY_MAX_ENTRIES", 100000),
+		RetryMaxAttempts:      getEnvAsInt("RETRY_MAX_ATTEMPTS", 5),
+		RetryInitialBackoffMs: getEnvAsInt("RETRY_INITIAL_BACKOFF_MS", 500),
+		RetryMaxBackoffMs:     getEnvAsInt("RETRY_MAX_BACKOFF_MS", 30000),
+		DeadLetterStore:       getEnv("DEAD_LETTER_STORE", "file"),
+		DeadLetterFile:        getEnv("DEAD_LETTER_FILE", "data/dead_letters.json"),
+		SNSEnabled:            getEnvAsBool("SNS_ENABLED", false),
+		SNSTopicARN:           getEnv("SNS_TOPIC_ARN", ""),
+		AWSRegion:             getEnv("AWS_REGION", "us-east-1"),
+		WebhookURL:            getEnv("WEBHOOK_URL", ""),
+		WebhookSecret:         getEnv("WEBHOOK_SECRET", ""),
+		WebhookTimeout:        getEnvAsInt("WEBHOOK_TIMEOUT", 10),
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPPort:              getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		SMTPTimeout:           getEnvAsInt("SMTP_TIMEOUT", 10),
+		EmailFrom:             getEnv("EMAIL_FROM", ""),
+		EmailTo:               getEnvAsList("EMAIL_TO"),
+		EmailTemplateDir:      getEnv("EMAIL_TEMPLATE_DIR", ""),
+		RabbitMQURL:           getEnv("RABBITMQ_URL", ""),
+		RabbitMQExchange:      getEnv("RABBITMQ_EXCHANGE", "blockchain.events"),
+		CustomerAlertsEnabled: getEnvAsBool("CUSTOMER_ALERTS_ENABLED", false),
+		ContractsAPIURL:       getEnv("CONTRACTS_API_URL", ""),
+		ContractsAPITimeout:   getEnvAsInt("CONTRACTS_API_TIMEOUT", 10),
+		AlertSMSEnabled:       getEnvAsBool("ALERT_SMS_ENABLED", false),
+		OpsSNSTopicARN:        getEnv("OPS_SNS_TOPIC_ARN", ""),
+		HealthAddr:            getEnv("HEALTH_ADDR", ":8080"),
+		ReadyMaxBlockLag:      getEnvAsUint64("READY_MAX_BLOCK_LAG", 100),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		LogFormat:             getEnv("LOG_FORMAT", "console"),
+	}
+
+	cfg.IdempotencyDatabaseURL = getEnv("IDEMPOTENCY_DATABASE_URL", cfg.CheckpointDatabaseURL)
+	cfg.DeadLetterDatabaseURL = getEnv("DEAD_LETTER_DATABASE_URL", cfg.CheckpointDatabaseURL)
+
+	cfg.Notifiers = getEnvAsList("NOTIFIERS")
+	if cfg.Notifiers == nil && cfg.SNSEnabled {
+		cfg.Notifiers = []string{"sns"}
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -55,11 +159,89 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("CONTRACT_ADDRESS is required")
 	}
 
+	// Validate checkpoint configuration
+	switch c.CheckpointStore {
+	case "none", "file":
+	case "postgres":
+		if c.CheckpointDatabaseURL == "" {
+			return fmt.Errorf("CHECKPOINT_DATABASE_URL is required when CHECKPOINT_STORE is postgres")
+		}
+	default:
+		return fmt.Errorf("invalid CHECKPOINT_STORE: %s (valid values: none, file, postgres)", c.CheckpointStore)
+	}
+
+	// Validate idempotency configuration
+	switch c.IdempotencyStore {
+	case "none", "memory":
+	case "postgres":
+		if c.IdempotencyDatabaseURL == "" {
+			return fmt.Errorf("IDEMPOTENCY_DATABASE_URL or CHECKPOINT_DATABASE_URL is required when IDEMPOTENCY_STORE is postgres")
+		}
+	default:
+		return fmt.Errorf("invalid IDEMPOTENCY_STORE: %s (valid values: none, memory, postgres)", c.IdempotencyStore)
+	}
+
+	if c.IdempotencyTTL <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL must be positive")
+	}
+
+	// Validate dispatch configuration
+	if c.DispatchWorkers < 1 {
+		return fmt.Errorf("DISPATCH_WORKERS must be at least 1")
+	}
+	if c.DispatchQueueSize < 0 {
+		return fmt.Errorf("DISPATCH_QUEUE_SIZE must not be negative")
+	}
+
+	// Validate retry and dead-letter configuration
+	if c.RetryMaxAttempts < 1 {
+		return fmt.Errorf("RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+
+	switch c.DeadLetterStore {
+	case "none", "file":
+	case "postgres":
+		if c.DeadLetterDatabaseURL == "" {
+			return fmt.Errorf("DEAD_LETTER_DATABASE_URL or CHECKPOINT_DATABASE_URL is required when DEAD_LETTER_STORE is postgres")
+		}
+	default:
+		return fmt.Errorf("invalid DEAD_LETTER_STORE: %s (valid values: none, file, postgres)", c.DeadLetterStore)
+	}
+
 	// Validate SNS configuration if enabled
 	if c.SNSEnabled && c.SNSTopicARN == "" {
 		return fmt.Errorf("SNS_TOPIC_ARN is required when SNS_ENABLED is true")
 	}
 
+	// Validate notifier backends
+	for _, notifier := range c.Notifiers {
+		switch notifier {
+		case "sns":
+			if c.SNSTopicARN == "" {
+				return fmt.Errorf("SNS_TOPIC_ARN is required when NOTIFIERS includes sns")
+			}
+		case "webhook":
+			if c.WebhookURL == "" {
+				return fmt.Errorf("WEBHOOK_URL is required when NOTIFIERS includes webhook")
+			}
+		case "email":
+			if c.SMTPHost == "" || c.EmailFrom == "" || len(c.EmailTo) == 0 {
+				return fmt.Errorf("SMTP_HOST, EMAIL_FROM and EMAIL_TO are required when NOTIFIERS includes email")
+			}
+		case "rabbitmq":
+			if c.RabbitMQURL == "" {
+				return fmt.Errorf("RABBITMQ_URL is required when NOTIFIERS includes rabbitmq")
+			}
+		default:
+			return fmt.Errorf("invalid notifier in NOTIFIERS: %s (valid values: sns, webhook, email, rabbitmq)", notifier)
+		}
+	}
+
+	// Validate customer alert configuration if enabled
+	if c.CustomerAlertsEnabled && c.ContractsAPIURL == "" {
+		return fmt.Errorf("CONTRACTS_API_URL is required when CUSTOMER_ALERTS_ENABLED is true")
+	}
+
 	// Validate log level
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -138,3 +320,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 
 	return value
 }
+
+// getEnvAsList retrieves a comma-separated environment variable as a list, or nil when it is empty
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+
+	"notifications/internal/adapter/blockchain/binding"
+	"notifications/internal/core/port/driven"
+)
+
+// ContractRegistry is an adapter that reads contracts from the SLAEnforcer contract
+type ContractRegistry struct {
+	client *ethclient.Client
+	caller *binding.SLAEnforcerCaller
+	logger *zap.Logger
+}
+
+// Ensure ContractRegistry implements the driven.ContractRegistry interface
+var _ driven.ContractRegistry = (*ContractRegistry)(nil)
+
+// NewContractRegistry connects to the Ethereum node and binds the contract at contractAddress
+func NewContractRegistry(rpcURL, contractAddress string, logger *zap.Logger) (*ContractRegistry, error) {
+	if !common.IsHexAddress(contractAddress) {
+		return nil, fmt.Errorf("invalid contract address: %s", contractAddress)
+	}
+
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Ethereum node: %w", err)
+	}
+
+	caller, err := binding.NewSLAEnforcerCaller(common.HexToAddress(contractAddress), client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to bind contract: %w", err)
+	}
+
+	logger.Info("Contract registry initialized",
+		zap.String("contractAddress", contractAddress),
+	)
+
+	return &ContractRegistry{
+		client: client,
+		caller: caller,
+		logger: logger,
+	}, nil
+}
+
+// CustomerID returns the customer ID stored on chain for the contract
+func (r *ContractRegistry) CustomerID(ctx context.Context, contractID string) (string, error) {
+	contract, err := r.caller.GetContract(&bind.CallOpts{Context: ctx}, contractID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get contract %s: %w", contractID, err)
+	}
+
+	if contract.CustomerId == "" {
+		return "", fmt.Errorf("contract %s has no customer: %w", contractID, driven.ErrCustomerNotFound)
+	}
+
+	return contract.CustomerId, nil
+}
+
+// Close closes the connection to the Ethereum node
+func (r *ContractRegistry) Close() {
+	r.client.Close()
+}
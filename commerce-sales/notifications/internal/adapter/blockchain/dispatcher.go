@@ -0,0 +1,245 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errDispatcherClosed is returned when a job is submitted after the dispatcher was closed
+var errDispatcherClosed = errors.New("dispatcher is closed")
+
+// DispatchConfig controls the worker pool that hands decoded events to the processors
+type DispatchConfig struct {
+	Workers      int           // events of different contracts processed in parallel (minimum 1)
+	QueueSize    int           // events buffered per worker before submitting blocks
+	DrainTimeout time.Duration // how long Stop waits for queued events before cancelling them
+}
+
+// dispatchJob is a single event waiting for a worker
+type dispatchJob struct {
+	run   func(ctx context.Context) error
+	batch *dispatchBatch
+}
+
+// dispatcher runs jobs on a fixed pool of workers, each with its own bounded queue. Jobs with
+// the same partition key always land on the same worker, so they run in submission order, while
+// jobs of other partitions run in parallel. Submitting to a full queue blocks the caller.
+type dispatcher struct {
+	queues []chan dispatchJob
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	closed    bool
+	stopping  chan struct{}
+	closeOnce sync.Once
+	workers   sync.WaitGroup
+}
+
+// newDispatcher creates a dispatcher and starts its workers
+func newDispatcher(cfg DispatchConfig, logger *zap.Logger) *dispatcher {
+	workers := max(cfg.Workers, 1)
+	queueSize := max(cfg.QueueSize, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &dispatcher{
+		queues:   make([]chan dispatchJob, workers),
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger,
+		stopping: make(chan struct{}),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan dispatchJob, queueSize)
+		d.workers.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	return d
+}
+
+// submit queues a job on the worker owning the partition key, blocking while its queue is full.
+// The batch, when not nil, is told when the job completes. Jobs run with the dispatcher's own
+// context, so they outlive the caller's context until the dispatcher is closed.
+func (d *dispatcher) submit(ctx context.Context, partition string, batch *dispatchBatch, run func(ctx context.Context) error) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return errDispatcherClosed
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(partition))
+	queue := d.queues[hash.Sum32()%uint32(len(d.queues))]
+
+	if batch != nil {
+		batch.add()
+	}
+
+	select {
+	case queue <- dispatchJob{run: run, batch: batch}:
+		return nil
+	case <-ctx.Done():
+		batch.complete(ctx.Err())
+		return ctx.Err()
+	case <-d.stopping:
+		batch.complete(errDispatcherClosed)
+		return errDispatcherClosed
+	}
+}
+
+// work runs the jobs of a single queue until it is closed
+func (d *dispatcher) work(queue <-chan dispatchJob) {
+	defer d.workers.Done()
+
+	for job := range queue {
+		err := job.run(d.ctx)
+		if err != nil {
+			d.logger.Error("Failed to process log", zap.Error(err))
+		}
+		job.batch.complete(err)
+	}
+}
+
+// close stops accepting jobs and waits for the queued ones to finish. Jobs still running after
+// the timeout have their context cancelled. It reports whether the queues drained in time.
+func (d *dispatcher) close(timeout time.Duration) bool {
+	drained := true
+
+	d.closeOnce.Do(func() {
+		// Unblock submitters waiting on a full queue before taking the write lock
+		close(d.stopping)
+
+		d.mu.Lock()
+		d.closed = true
+		for _, queue := range d.queues {
+			close(queue)
+		}
+		d.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			d.workers.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			drained = false
+			d.cancel()
+			<-done
+		}
+
+		d.cancel()
+	})
+
+	return drained
+}
+
+// dispatchBatch tracks a group of submitted jobs, such as the logs of a block range, so the
+// checkpoint only moves past them once all of them completed
+type dispatchBatch struct {
+	mu       sync.Mutex
+	pending  int
+	total    int
+	failed   int
+	err      error
+	sealed   bool
+	finished chan struct{}
+}
+
+// newDispatchBatch creates an empty, open batch
+func newDispatchBatch() *dispatchBatch {
+	return &dispatchBatch{
+		finished: make(chan struct{}),
+	}
+}
+
+// add registers a submitted job
+func (b *dispatchBatch) add() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending++
+	b.total++
+}
+
+// complete records the outcome of a job. It is a no-op on a nil batch.
+func (b *dispatchBatch) complete(err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending--
+	if err != nil {
+		b.failed++
+		if b.err == nil {
+			b.err = err
+		}
+	}
+	b.finishIfDone()
+}
+
+// seal marks that no more jobs will be added, so the batch finishes once its jobs completed
+func (b *dispatchBatch) seal() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sealed = true
+	b.finishIfDone()
+}
+
+// finishIfDone closes the finished channel of a sealed batch without pending jobs.
+// Must be called with the mutex held.
+func (b *dispatchBatch) finishIfDone() {
+	if b.sealed && b.pending == 0 {
+		select {
+		case <-b.finished:
+		default:
+			close(b.finished)
+		}
+	}
+}
+
+// done returns a channel closed once the batch is sealed and all its jobs completed
+func (b *dispatchBatch) done() <-chan struct{} {
+	return b.finished
+}
+
+// result returns how many jobs the batch had and an error if any of them failed.
+// Only meaningful once done is closed.
+func (b *dispatchBatch) result() (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed > 0 {
+		return b.total, fmt.Errorf("%d of %d log(s) failed to process: %w", b.failed, b.total, b.err)
+	}
+	return b.total, nil
+}
+
+// wait seals the batch and blocks until all its jobs completed or the context is done
+func (b *dispatchBatch) wait(ctx context.Context) (int, error) {
+	b.seal()
+
+	select {
+	case <-b.done():
+		return b.result()
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
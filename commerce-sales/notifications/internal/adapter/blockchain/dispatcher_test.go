@@ -0,0 +1,161 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestDispatcherKeepsTheOrderOfEachPartition(t *testing.T) {
+	d := newDispatcher(DispatchConfig{Workers: 3, QueueSize: 1}, zap.NewNop())
+
+	var mu sync.Mutex
+	ran := make(map[string][]int)
+
+	partitions := []string{"contract-1", "contract-2", "contract-3", "contract-4"}
+	batch := newDispatchBatch()
+	for i := range 20 {
+		for _, partition := range partitions {
+			err := d.submit(context.Background(), partition, batch, func(context.Context) error {
+				// Later jobs finish faster, so only the queue keeps them in order
+				time.Sleep(time.Duration(20-i) * 50 * time.Microsecond)
+
+				mu.Lock()
+				ran[partition] = append(ran[partition], i)
+				mu.Unlock()
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("submit() error = %v", err)
+			}
+		}
+	}
+
+	total, err := batch.wait(context.Background())
+	if total != 80 || err != nil {
+		t.Fatalf("wait() = %d, %v, want 80 jobs without error", total, err)
+	}
+	if !d.close(time.Second) {
+		t.Error("close() did not drain an idle dispatcher")
+	}
+
+	for _, partition := range partitions {
+		if !slices.IsSorted(ran[partition]) || len(ran[partition]) != 20 {
+			t.Errorf("jobs of %s ran as %v, want 0 to 19 in order", partition, ran[partition])
+		}
+	}
+}
+
+func TestDispatcherBatchReportsFailures(t *testing.T) {
+	d := newDispatcher(DispatchConfig{Workers: 2}, zap.NewNop())
+	defer d.close(time.Second)
+
+	failure := errors.New("notifier unavailable")
+	batch := newDispatchBatch()
+	for i := range 3 {
+		err := d.submit(context.Background(), fmt.Sprintf("contract-%d", i), batch, func(context.Context) error {
+			if i == 1 {
+				return failure
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("submit() error = %v", err)
+		}
+	}
+
+	total, err := batch.wait(context.Background())
+	if total != 3 || !errors.Is(err, failure) {
+		t.Errorf("wait() = %d, %v, want 3 jobs failing with %v", total, err, failure)
+	}
+}
+
+func TestDispatcherDrainsQueuedJobsOnClose(t *testing.T) {
+	d := newDispatcher(DispatchConfig{Workers: 1, QueueSize: 10}, zap.NewNop())
+
+	var mu sync.Mutex
+	ran := 0
+	for range 5 {
+		err := d.submit(context.Background(), "contract-1", nil, func(ctx context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			mu.Lock()
+			ran++
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("submit() error = %v", err)
+		}
+	}
+
+	if !d.close(5 * time.Second) {
+		t.Fatal("close() = false, want the queue drained within the timeout")
+	}
+	if ran != 5 {
+		t.Errorf("ran %d jobs before close returned, want all 5", ran)
+	}
+
+	if err := d.submit(context.Background(), "contract-1", nil, func(context.Context) error { return nil }); !errors.Is(err, errDispatcherClosed) {
+		t.Errorf("submit() after close error = %v, want %v", err, errDispatcherClosed)
+	}
+}
+
+func TestDispatcherCancelsJobsPastTheDrainTimeout(t *testing.T) {
+	d := newDispatcher(DispatchConfig{Workers: 1}, zap.NewNop())
+
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	batch := newDispatchBatch()
+	err := d.submit(context.Background(), "contract-1", batch, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("submit() error = %v", err)
+	}
+	<-started
+
+	// The worker is busy and the queue unbuffered, so this submitter blocks until close
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- d.submit(context.Background(), "contract-1", batch, func(context.Context) error { return nil })
+	}()
+	for pending(batch) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if d.close(20 * time.Millisecond) {
+		t.Error("close() = true, want false for a job still running past the timeout")
+	}
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("running job context error = %v, want %v", err, context.Canceled)
+	}
+	if err := <-blocked; !errors.Is(err, errDispatcherClosed) {
+		t.Errorf("blocked submit() error = %v, want %v", err, errDispatcherClosed)
+	}
+
+	// Both jobs count as failed, so the checkpoint doesn't move past them
+	total, err := batch.wait(context.Background())
+	if total != 2 || err == nil || !strings.Contains(err.Error(), "2 of 2") {
+		t.Errorf("wait() = %d, %v, want 2 failed jobs", total, err)
+	}
+}
+
+// pending returns how many jobs of the batch have not completed yet
+func pending(batch *dispatchBatch) int {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+	return batch.pending
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"math/big"
 	"notifications/internal/core/port/driven"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -20,36 +22,72 @@ import (
 	"notifications/internal/core/domain"
 )
 
+// logSource is the part of the Ethereum client the listener reads blocks and logs from,
+// implemented by *ethclient.Client
+type logSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
 // EthereumListener is an adapter that listens to Ethereum blockchain events
 type EthereumListener struct {
 	client            *ethclient.Client
+	source            logSource // the client, or a stand-in in tests
 	contractAddress   common.Address
 	contractABI       abi.ABI
 	rpcURL            string
 	reconnectInterval time.Duration
 	startBlock        uint64
+	confirmationDepth uint64
+	chunkSize         uint64
+	checkpoints       driven.CheckpointStore
+	idempotency       driven.IdempotencyStore
+	metrics           driven.Metrics
+	resolver          *idResolver
+	dispatcher        *dispatcher
+	drainTimeout      time.Duration
 	logger            *zap.Logger
 	processors        []driven.EventProcessor
 
+	// Only used by the listen goroutine, kept across reconnects
+	lastProcessedBlock uint64
+	hasProcessed       bool
+
+	// Confirmed blocks not processed yet, read by BlockLag
+	blockLag atomic.Uint64
+	lagKnown atomic.Bool
+
 	mu           sync.RWMutex
 	processorsMu sync.RWMutex
 	connected    bool
 	stopCh       chan struct{}
 }
 
+// ListenerConfig configures an EthereumListener
+type ListenerConfig struct {
+	RPCURL            string
+	ContractAddress   string
+	StartBlock        uint64        // first block to process without a checkpoint (0 = the current confirmed block)
+	ConfirmationDepth uint64        // blocks a log must be buried under before it is dispatched (0 = as soon as it is seen)
+	ChunkSize         uint64        // most blocks fetched per log request (0 = a single request)
+	ReconnectInterval time.Duration // wait between attempts to reconnect to the node
+	Dispatch          DispatchConfig
+
+	Checkpoints driven.CheckpointStore  // optional, without it the listener restarts from StartBlock or the chain head
+	Idempotency driven.IdempotencyStore // optional, without it a log delivered twice is dispatched twice
+	Metrics     driven.Metrics          // optional
+}
+
 // NewEthereumListener creates a new EthereumListener instance
 // The parameter "processors" is optional and can be nil. Additional processors can be added via Subscribe().
-func NewEthereumListener(
-	rpcURL string,
-	contractAddress string,
-	startBlock uint64,
-	reconnectInterval int,
-	logger *zap.Logger,
-	processors ...driven.EventProcessor,
-) (*EthereumListener, error) {
+// Events are handed to the processors by a pool of cfg.Dispatch.Workers workers: events of the same
+// contract are processed in order, events of different contracts in parallel.
+func NewEthereumListener(cfg ListenerConfig, logger *zap.Logger, processors ...driven.EventProcessor) (*EthereumListener, error) {
 	// Parse contract address
-	if !common.IsHexAddress(contractAddress) {
-		return nil, fmt.Errorf("invalid contract address: %s", contractAddress)
+	if !common.IsHexAddress(cfg.ContractAddress) {
+		return nil, fmt.Errorf("invalid contract address: %s", cfg.ContractAddress)
 	}
 
 	// Parse contract ABI
@@ -59,11 +97,19 @@ func NewEthereumListener(
 	}
 
 	return &EthereumListener{
-		contractAddress:   common.HexToAddress(contractAddress),
+		contractAddress:   common.HexToAddress(cfg.ContractAddress),
 		contractABI:       contractABI,
-		rpcURL:            rpcURL,
-		reconnectInterval: time.Duration(reconnectInterval) * time.Second,
-		startBlock:        startBlock,
+		rpcURL:            cfg.RPCURL,
+		reconnectInterval: cfg.ReconnectInterval,
+		startBlock:        cfg.StartBlock,
+		confirmationDepth: cfg.ConfirmationDepth,
+		chunkSize:         cfg.ChunkSize,
+		checkpoints:       cfg.Checkpoints,
+		idempotency:       cfg.Idempotency,
+		metrics:           cfg.Metrics,
+		resolver:          newIDResolver(logger),
+		dispatcher:        newDispatcher(cfg.Dispatch, logger),
+		drainTimeout:      cfg.Dispatch.DrainTimeout,
 		logger:            logger,
 		processors:        processors,
 		stopCh:            make(chan struct{}),
@@ -76,6 +122,8 @@ func (el *EthereumListener) Start(ctx context.Context) error {
 		zap.String("rpcURL", el.rpcURL),
 		zap.String("contractAddress", el.contractAddress.Hex()),
 		zap.Uint64("startBlock", el.startBlock),
+		zap.Uint64("confirmationDepth", el.confirmationDepth),
+		zap.Int("dispatchWorkers", len(el.dispatcher.queues)),
 	)
 
 	// Initial connection
@@ -89,11 +137,18 @@ func (el *EthereumListener) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop stops the blockchain listener
+// Stop stops the blockchain listener. Events already queued for the processors are still
+// processed, for up to the drain timeout, before the connection is closed.
 func (el *EthereumListener) Stop() error {
 	el.logger.Info("Stopping Ethereum listener")
 	close(el.stopCh)
 
+	if !el.dispatcher.close(el.drainTimeout) {
+		el.logger.Warn("Dispatch queues not drained in time, remaining events were cancelled",
+			zap.Duration("drainTimeout", el.drainTimeout),
+		)
+	}
+
 	el.mu.Lock()
 	defer el.mu.Unlock()
 
@@ -130,18 +185,41 @@ func (el *EthereumListener) IsConnected() bool {
 	return el.connected
 }
 
-// notifyProcessors sends an event to all registered processors
+// BlockLag returns how many confirmed blocks are not processed yet. It is false until the
+// listener has compared its progress with the chain head.
+func (el *EthereumListener) BlockLag() (uint64, bool) {
+	return el.blockLag.Load(), el.lagKnown.Load()
+}
+
+// observeLag records the lag between the confirmed block at the given head and the last processed block
+func (el *EthereumListener) observeLag(head, lastProcessedBlock uint64) {
+	var lag uint64
+	if confirmed := el.confirmedBlock(head); confirmed > lastProcessedBlock {
+		lag = confirmed - lastProcessedBlock
+	}
+
+	el.blockLag.Store(lag)
+	el.lagKnown.Store(true)
+
+	if el.metrics != nil {
+		el.metrics.BlockLag(lag)
+	}
+}
+
+// notifyProcessors sends an event to all registered processors. The processors are copied
+// first, so a slow processor doesn't block Subscribe.
 func (el *EthereumListener) notifyProcessors(ctx context.Context, event interface{}) error {
 	el.processorsMu.RLock()
-	defer el.processorsMu.RUnlock()
+	processors := append([]driven.EventProcessor(nil), el.processors...)
+	el.processorsMu.RUnlock()
 
-	if len(el.processors) == 0 {
+	if len(processors) == 0 {
 		el.logger.Warn("No processors registered to handle event")
 		return nil
 	}
 
 	var errors []error
-	for i, processor := range el.processors {
+	for i, processor := range processors {
 		if err := processor.Process(ctx, event); err != nil {
 			el.logger.Error("Processor failed to handle event",
 				zap.Error(err),
@@ -174,8 +252,17 @@ func (el *EthereumListener) connect() error {
 		return fmt.Errorf("failed to get chain ID: %w", err)
 	}
 
+	// Bind the contract for the on-chain lookups that resolve indexed IDs
+	caller, err := binding.NewSLAEnforcerCaller(el.contractAddress, client)
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to bind contract: %w", err)
+	}
+	el.resolver.setCaller(caller)
+
 	el.mu.Lock()
 	el.client = client
+	el.source = client
 	el.connected = true
 	el.mu.Unlock()
 
@@ -206,6 +293,9 @@ func (el *EthereumListener) listen(ctx context.Context) {
 					time.Sleep(el.reconnectInterval)
 					continue
 				}
+				if el.metrics != nil {
+					el.metrics.Reconnected()
+				}
 			}
 
 			if err := el.subscribeToEvents(ctx); err != nil {
@@ -235,26 +325,95 @@ func (el *EthereumListener) subscribeToEvents(ctx context.Context) error {
 	return el.pollHTTP(ctx)
 }
 
-// subscribeWebSocket subscribes to events via WebSocket
+// subscribeWebSocket subscribes to events via WebSocket.
+// Blocks missed since the last checkpoint are caught up with FilterLogs before live logs are handled.
+// With a confirmation depth, logs are buffered until enough blocks are built on top of them.
+// Logs flagged as removed by a reorganisation are dropped from the buffer, or retracted
+// if they were already dispatched.
+// Dispatched logs are grouped into a batch per head; the checkpoint only moves past a head
+// once its batch and every earlier one completed. While the dispatch queues are full the
+// subscription is not read, and a subscription that overflows is caught up after reconnecting.
 func (el *EthereumListener) subscribeWebSocket(ctx context.Context) error {
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{el.contractAddress},
 	}
 
-	if el.startBlock > 0 {
-		query.FromBlock = big.NewInt(int64(el.startBlock))
-	}
-
 	logs := make(chan types.Log)
-	sub, err := el.client.SubscribeFilterLogs(ctx, query, logs)
+	sub, err := el.source.SubscribeFilterLogs(ctx, query, logs)
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to logs: %w", err)
 	}
 	defer sub.Unsubscribe()
 
-	el.logger.Info("Successfully subscribed to events, waiting for logs...")
+	// New heads release buffered logs and advance the checkpoint
+	heads := make(chan *types.Header)
+	headSub, err := el.source.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+	defer headSub.Unsubscribe()
+
+	// Catch up on the blocks missed while the listener was down
+	currentBlock, err := el.source.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current block number: %w", err)
+	}
+
+	lastProcessedBlock, err := el.resumeFrom(ctx, currentBlock)
+	if err != nil {
+		return err
+	}
+
+	if safeBlock := el.confirmedBlock(currentBlock); safeBlock > lastProcessedBlock {
+		processed, err := el.processRange(ctx, lastProcessedBlock+1, safeBlock)
+		if processed > lastProcessedBlock {
+			lastProcessedBlock = processed
+			el.checkpoint(ctx, lastProcessedBlock)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	el.logger.Info("Successfully subscribed to events, waiting for logs...",
+		zap.Uint64("lastProcessedBlock", lastProcessedBlock),
+	)
+
+	latestBlock := currentBlock
+	el.observeLag(latestBlock, lastProcessedBlock)
+
+	pending := make(map[logKey]types.Log)
+	batch := newDispatchBatch()
+	var inFlight []checkpointBatch
+
+	// settle checkpoints the completed batches at the front of inFlight, in order
+	settle := func() error {
+		for len(inFlight) > 0 {
+			select {
+			case <-inFlight[0].batch.done():
+			default:
+				return nil
+			}
+
+			if _, err := inFlight[0].batch.result(); err != nil {
+				return fmt.Errorf("failed to process logs up to block %d: %w", inFlight[0].block, err)
+			}
+			if inFlight[0].block > lastProcessedBlock {
+				lastProcessedBlock = inFlight[0].block
+				el.checkpoint(ctx, lastProcessedBlock)
+			}
+			inFlight = inFlight[1:]
+		}
+		return nil
+	}
 
 	for {
+		// A nil channel never fires, so there is nothing to settle without batches in flight
+		var settled <-chan struct{}
+		if len(inFlight) > 0 {
+			settled = inFlight[0].batch.done()
+		}
+
 		select {
 		case <-el.stopCh:
 			return nil
@@ -262,42 +421,146 @@ func (el *EthereumListener) subscribeWebSocket(ctx context.Context) error {
 			return nil
 		case err := <-sub.Err():
 			return fmt.Errorf("subscription error: %w", err)
+		case err := <-headSub.Err():
+			return fmt.Errorf("head subscription error: %w", err)
+		case <-settled:
+			if err := settle(); err != nil {
+				return err
+			}
+			el.observeLag(latestBlock, lastProcessedBlock)
+		case head := <-heads:
+			latestBlock = head.Number.Uint64()
+
+			if err := el.dispatchConfirmed(ctx, pending, batch, head.Number.Uint64()); err != nil {
+				return err
+			}
+
+			// Logs for the newest block may still be in flight when there is no
+			// confirmation depth, so the checkpoint stays one block behind
+			safeBlock := el.confirmedBlock(head.Number.Uint64())
+			if el.confirmationDepth == 0 && safeBlock > 0 {
+				safeBlock--
+			}
+
+			batch.seal()
+			inFlight = append(inFlight, checkpointBatch{batch: batch, block: safeBlock})
+			batch = newDispatchBatch()
+
+			if err := settle(); err != nil {
+				return err
+			}
+			el.observeLag(latestBlock, lastProcessedBlock)
 		case vLog := <-logs:
-			if err := el.processLog(ctx, vLog); err != nil {
-				el.logger.Error("Failed to process log", zap.Error(err))
+			// Already handled by the catch-up
+			if !vLog.Removed && vLog.BlockNumber <= lastProcessedBlock {
+				continue
+			}
+			if err := el.handleSubscribedLog(ctx, pending, batch, vLog); err != nil {
+				return err
 			}
 		}
 	}
 }
 
-// pollHTTP polls for events via HTTP
-func (el *EthereumListener) pollHTTP(ctx context.Context) error {
-	query := ethereum.FilterQuery{
-		Addresses: []common.Address{el.contractAddress},
+// checkpointBatch is a batch of dispatched logs and the block the checkpoint may move to once it completed
+type checkpointBatch struct {
+	batch *dispatchBatch
+	block uint64
+}
+
+// logKey identifies a log within a specific block, so the same transaction
+// re-included in another block after a reorganisation is a different log
+type logKey struct {
+	blockHash common.Hash
+	index     uint
+}
+
+// handleSubscribedLog dispatches, buffers or retracts a log received from the subscription.
+// Dispatched logs are added to the batch of the next head.
+func (el *EthereumListener) handleSubscribedLog(ctx context.Context, pending map[logKey]types.Log, batch *dispatchBatch, vLog types.Log) error {
+	key := logKey{blockHash: vLog.BlockHash, index: vLog.Index}
+
+	if vLog.Removed {
+		if _, buffered := pending[key]; buffered {
+			delete(pending, key)
+			el.logger.Info("Discarded log removed by reorg before confirmation",
+				zap.Uint64("blockNumber", vLog.BlockNumber),
+				zap.String("txHash", vLog.TxHash.Hex()),
+			)
+			return nil
+		}
+
+		el.logger.Warn("Dispatched log removed by reorg, retracting event",
+			zap.Uint64("blockNumber", vLog.BlockNumber),
+			zap.String("txHash", vLog.TxHash.Hex()),
+		)
+
+		// Retractions are best effort: the checkpoint has already moved past their block,
+		// so they don't belong to a batch
+		if err := el.dispatchLog(ctx, nil, vLog); err != nil {
+			el.logger.Error("Failed to dispatch retracted log", zap.Error(err))
+		}
+		return nil
 	}
 
+	if el.confirmationDepth > 0 {
+		pending[key] = vLog
+		return nil
+	}
+
+	return el.dispatchLog(ctx, batch, vLog)
+}
+
+// dispatchConfirmed dispatches, in chain order, the buffered logs that are at least
+// confirmationDepth blocks below the given head
+func (el *EthereumListener) dispatchConfirmed(ctx context.Context, pending map[logKey]types.Log, batch *dispatchBatch, head uint64) error {
+	confirmed := make([]types.Log, 0, len(pending))
+	for key, vLog := range pending {
+		if vLog.BlockNumber+el.confirmationDepth <= head {
+			confirmed = append(confirmed, vLog)
+			delete(pending, key)
+		}
+	}
+
+	sort.Slice(confirmed, func(i, j int) bool {
+		if confirmed[i].BlockNumber != confirmed[j].BlockNumber {
+			return confirmed[i].BlockNumber < confirmed[j].BlockNumber
+		}
+		return confirmed[i].Index < confirmed[j].Index
+	})
+
+	for _, vLog := range confirmed {
+		if err := el.dispatchLog(ctx, batch, vLog); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pollHTTP polls for events via HTTP, only up to the block that is confirmationDepth deep.
+// A batch is retried on the next tick until every log in it was processed successfully.
+func (el *EthereumListener) pollHTTP(ctx context.Context) error {
 	// Get the current block number
-	currentBlock, err := el.client.BlockNumber(ctx)
+	currentBlock, err := el.source.BlockNumber(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get current block number: %w", err)
 	}
 
-	// Start from a configured block or current block
-	fromBlock := el.startBlock
-	if fromBlock == 0 {
-		fromBlock = currentBlock
+	// Resume from the checkpoint, a configured block or the current confirmed block
+	lastProcessedBlock, err := el.resumeFrom(ctx, currentBlock)
+	if err != nil {
+		return err
 	}
 
 	el.logger.Info("Starting HTTP polling for events",
-		zap.Uint64("fromBlock", fromBlock),
+		zap.Uint64("fromBlock", lastProcessedBlock+1),
 		zap.Uint64("currentBlock", currentBlock),
 	)
 
 	ticker := time.NewTicker(5 * time.Second) // Poll every 5 seconds
 	defer ticker.Stop()
 
-	lastProcessedBlock := fromBlock
-
 	for {
 		select {
 		case <-el.stopCh:
@@ -306,146 +569,483 @@ func (el *EthereumListener) pollHTTP(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			// Get the latest block
-			latestBlock, err := el.client.BlockNumber(ctx)
+			latestBlock, err := el.source.BlockNumber(ctx)
 			if err != nil {
 				el.logger.Error("Failed to get latest block number", zap.Error(err))
 				continue
 			}
 
-			// If no new blocks, continue
-			if latestBlock <= lastProcessedBlock {
+			// Only consider blocks that are deep enough to be safe from reorgs
+			safeBlock := el.confirmedBlock(latestBlock)
+
+			// If no new confirmed blocks, continue
+			if safeBlock <= lastProcessedBlock {
+				el.observeLag(latestBlock, lastProcessedBlock)
 				continue
 			}
 
-			// Query logs from last processed block to latest
-			query.FromBlock = big.NewInt(int64(lastProcessedBlock + 1))
-			query.ToBlock = big.NewInt(int64(latestBlock))
-
-			logs, err := el.client.FilterLogs(ctx, query)
+			processed, err := el.processRange(ctx, lastProcessedBlock+1, safeBlock)
+			if processed > lastProcessedBlock {
+				lastProcessedBlock = processed
+				el.checkpoint(ctx, lastProcessedBlock)
+			}
+			el.observeLag(latestBlock, lastProcessedBlock)
 			if err != nil {
-				el.logger.Error("Failed to filter logs", zap.Error(err))
-				continue
+				el.logger.Error("Failed to process block range, retrying on next poll",
+					zap.Error(err),
+					zap.Uint64("fromBlock", lastProcessedBlock+1),
+					zap.Uint64("toBlock", safeBlock),
+				)
 			}
+		}
+	}
+}
 
-			// Process each log
-			for _, vLog := range logs {
-				if err := el.processLog(ctx, vLog); err != nil {
-					el.logger.Error("Failed to process log", zap.Error(err))
-				}
-			}
+// processRange fetches and processes the contract logs of an inclusive block range, walking
+// it in chunks of at most chunkSize blocks. The logs of a chunk are processed by the dispatch
+// workers, and the next chunk is only fetched once all of them completed. A chunk the provider
+// rejects for returning too many results is halved and retried; later chunks grow back towards
+// chunkSize. It returns the last block whose logs were all processed, which is fromBlock-1 when
+// none were.
+func (el *EthereumListener) processRange(ctx context.Context, fromBlock, toBlock uint64) (uint64, error) {
+	chunk := el.chunkSize
+	lastProcessedBlock := fromBlock - 1
+
+	for start := fromBlock; start <= toBlock; {
+		end := toBlock
+		if chunk > 0 && start+chunk-1 < toBlock {
+			end = start + chunk - 1
+		}
 
-			if len(logs) > 0 {
-				el.logger.Info("Processed logs",
-					zap.Int("count", len(logs)),
-					zap.Uint64("fromBlock", lastProcessedBlock+1),
-					zap.Uint64("toBlock", latestBlock),
+		query := ethereum.FilterQuery{
+			Addresses: []common.Address{el.contractAddress},
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+		}
+
+		logs, err := el.source.FilterLogs(ctx, query)
+		if err != nil {
+			if isTooManyResultsError(err) && end > start {
+				chunk = (end - start + 1) / 2
+				el.logger.Warn("Provider rejected log range, shrinking chunk",
+					zap.Error(err),
+					zap.Uint64("fromBlock", start),
+					zap.Uint64("toBlock", end),
+					zap.Uint64("chunkSize", chunk),
 				)
+				continue
+			}
+			return lastProcessedBlock, fmt.Errorf("failed to filter logs: %w", err)
+		}
+
+		// Dispatch each log and wait for the whole chunk
+		batch := newDispatchBatch()
+		for _, vLog := range logs {
+			if err := el.dispatchLog(ctx, batch, vLog); err != nil {
+				return lastProcessedBlock, err
 			}
+		}
+
+		dispatched, err := batch.wait(ctx)
+		if dispatched > 0 {
+			el.logger.Info("Processed logs",
+				zap.Int("count", dispatched),
+				zap.Bool("failed", err != nil),
+				zap.Uint64("fromBlock", start),
+				zap.Uint64("toBlock", end),
+			)
+		}
+
+		if err != nil {
+			return lastProcessedBlock, err
+		}
+
+		lastProcessedBlock = end
+		start = end + 1
+
+		if chunk > 0 && chunk < el.chunkSize {
+			chunk = min(chunk*2, el.chunkSize)
+		}
+	}
+
+	return lastProcessedBlock, nil
+}
+
+// Backfill processes the events of an inclusive block range once, in chunks, feeding the
+// registered processors. It doesn't read or write the checkpoint. A toBlock of 0 means the
+// current block at confirmation depth.
+func (el *EthereumListener) Backfill(ctx context.Context, fromBlock, toBlock uint64) error {
+	if !el.IsConnected() {
+		if err := el.connect(); err != nil {
+			return err
+		}
+	}
+
+	if toBlock == 0 {
+		currentBlock, err := el.source.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current block number: %w", err)
+		}
+		toBlock = el.confirmedBlock(currentBlock)
+	}
+
+	if fromBlock > toBlock {
+		return fmt.Errorf("invalid block range: from %d is after to %d", fromBlock, toBlock)
+	}
+
+	el.logger.Info("Starting backfill",
+		zap.Uint64("fromBlock", fromBlock),
+		zap.Uint64("toBlock", toBlock),
+		zap.Uint64("chunkSize", el.chunkSize),
+	)
+
+	lastProcessedBlock, err := el.processRange(ctx, fromBlock, toBlock)
+	if err != nil {
+		return fmt.Errorf("backfill stopped at block %d: %w", lastProcessedBlock+1, err)
+	}
+
+	el.logger.Info("Backfill completed",
+		zap.Uint64("fromBlock", fromBlock),
+		zap.Uint64("toBlock", toBlock),
+	)
+
+	return nil
+}
+
+// tooManyResultsMessages are the errors providers return when a log query spans too many blocks
+// or matches too many logs. Generic ones, such as "invalid block range" or "rate limit exceeded",
+// are left out: splitting the range does not help with them.
+var tooManyResultsMessages = []string{
+	"too many results",           // geth-based nodes
+	"query returned more than",   // Infura
+	"response size exceeded",     // Alchemy
+	"exceed maximum block range", // BSC, Polygon and other geth forks
+	"block range is too wide",    // Ankr
+	"block range too large",      // Cloudflare
+	"range too large",            // Erigon
+	"block range limit exceeded", // Chainstack
+}
+
+// isTooManyResultsError reports whether the provider rejected a log query because the range
+// or the result was too large. RPC errors lose their Go type, so the message is matched instead.
+func isTooManyResultsError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, known := range tooManyResultsMessages {
+		if strings.Contains(msg, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// resumeFrom returns the last block whose events were processed: the block reached before
+// a reconnect, the stored checkpoint, the block before START_BLOCK or the current confirmed block
+func (el *EthereumListener) resumeFrom(ctx context.Context, currentBlock uint64) (uint64, error) {
+	if el.hasProcessed {
+		return el.lastProcessedBlock, nil
+	}
 
-			lastProcessedBlock = latestBlock
+	if el.checkpoints != nil {
+		block, ok, err := el.checkpoints.Load(ctx, el.checkpointKey())
+		if err != nil {
+			return 0, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if ok {
+			el.logger.Info("Resuming from checkpoint", zap.Uint64("block", block))
+			return block, nil
 		}
 	}
+
+	if el.startBlock > 0 {
+		return el.startBlock - 1, nil
+	}
+
+	return el.confirmedBlock(currentBlock), nil
+}
+
+// checkpoint records that every event up to and including block was processed
+func (el *EthereumListener) checkpoint(ctx context.Context, block uint64) {
+	el.lastProcessedBlock = block
+	el.hasProcessed = true
+
+	if el.checkpoints == nil {
+		return
+	}
+
+	if err := el.checkpoints.Save(ctx, el.checkpointKey(), block); err != nil {
+		el.logger.Error("Failed to save checkpoint",
+			zap.Error(err),
+			zap.Uint64("block", block),
+		)
+	}
+}
+
+// checkpointKey identifies this listener's checkpoint in a shared store
+func (el *EthereumListener) checkpointKey() string {
+	return el.contractAddress.Hex()
+}
+
+// confirmedBlock returns the highest block that is at least confirmationDepth deep
+func (el *EthereumListener) confirmedBlock(latest uint64) uint64 {
+	if latest < el.confirmationDepth {
+		return 0
+	}
+	return latest - el.confirmationDepth
 }
 
-// processLog processes a single log entry
-func (el *EthereumListener) processLog(ctx context.Context, vLog types.Log) error {
+// dispatchLog decodes a single log entry and queues it for the processors, on the worker owning
+// its contract so events of a contract keep their order. Removed logs are dispatched as an
+// EventRetractedEvent wrapping the decoded event. Logs that can't be decoded are skipped, so
+// only a closed dispatcher or a cancelled context are returned; processor failures are
+// reported through the batch, which may be nil.
+func (el *EthereumListener) dispatchLog(ctx context.Context, batch *dispatchBatch, vLog types.Log) error {
+	event, err := el.decodeLog(ctx, vLog)
+	if err != nil {
+		el.logger.Error("Skipping log that could not be decoded",
+			zap.Error(err),
+			zap.Uint64("blockNumber", vLog.BlockNumber),
+			zap.String("txHash", vLog.TxHash.Hex()),
+		)
+		return nil
+	}
+	if event == nil {
+		return nil
+	}
+
+	// Decoded events are regular events, so this is also the key a retraction releases
+	originalKey := newBlockchainEvent("", vLog).IdempotencyKey()
+
+	key := originalKey
+	if vLog.Removed {
+		retracted := &domain.EventRetractedEvent{
+			BlockchainEvent: newBlockchainEvent(domain.EventTypeEventRetracted, vLog),
+			Retracted:       event,
+		}
+		event = retracted
+		key = retracted.IdempotencyKey()
+	}
+
+	if el.metrics != nil {
+		eventType, _ := domain.EventTypeOf(event)
+		el.metrics.EventReceived(string(eventType))
+	}
+
+	// Every known event has the contract ID as its first indexed topic
+	partition := ""
+	if len(vLog.Topics) > 1 {
+		partition = vLog.Topics[1].Hex()
+	}
+
+	return el.dispatcher.submit(ctx, partition, batch, func(ctx context.Context) error {
+		return el.processEvent(ctx, event, key, originalKey, vLog)
+	})
+}
+
+// processEvent hands a decoded event to the processors. Events whose idempotency key was already
+// recorded are skipped; the key is passed to the processors through the context and recorded
+// once they all succeeded. A retraction is only sent for an event that was notified, and forgets
+// its key.
+func (el *EthereumListener) processEvent(ctx context.Context, event interface{}, key, originalKey string, vLog types.Log) error {
+	// The event and its retraction run on the same worker, so the event is settled by now
+	if vLog.Removed && !el.wasNotified(ctx, originalKey) {
+		el.logger.Info("Skipping retraction of a log that was never notified",
+			zap.String("idempotencyKey", originalKey),
+			zap.Uint64("blockNumber", vLog.BlockNumber),
+		)
+		return nil
+	}
+
+	if el.alreadyNotified(ctx, key) {
+		el.logger.Info("Skipping already notified log",
+			zap.String("idempotencyKey", key),
+			zap.Uint64("blockNumber", vLog.BlockNumber),
+		)
+		return nil
+	}
+
+	if err := el.notifyProcessors(domain.WithIdempotencyKey(ctx, key), event); err != nil {
+		return err
+	}
+
+	if el.idempotency == nil {
+		return nil
+	}
+
+	if err := el.idempotency.Record(ctx, key); err != nil {
+		el.logger.Warn("Failed to record idempotency key", zap.Error(err), zap.String("idempotencyKey", key))
+	}
+
+	// A retracted event may be mined again in another block, it must then be notified again
+	if vLog.Removed {
+		if err := el.idempotency.Forget(ctx, originalKey); err != nil {
+			el.logger.Warn("Failed to forget idempotency key", zap.Error(err), zap.String("idempotencyKey", originalKey))
+		}
+	}
+
+	return nil
+}
+
+// alreadyNotified reports whether the key was recorded by the idempotency store. Store errors
+// are logged and treated as not notified, preferring a duplicate over a lost notification.
+func (el *EthereumListener) alreadyNotified(ctx context.Context, key string) bool {
+	if el.idempotency == nil {
+		return false
+	}
+
+	seen, err := el.idempotency.Seen(ctx, key)
+	if err != nil {
+		el.logger.Warn("Failed to check idempotency key", zap.Error(err), zap.String("idempotencyKey", key))
+		return false
+	}
+
+	return seen
+}
+
+// wasNotified reports whether the event with the key was notified, for its retraction. Without an
+// idempotency store, or when it fails, that can't be told and the event is assumed notified,
+// preferring a needless retraction over a notification left standing.
+func (el *EthereumListener) wasNotified(ctx context.Context, key string) bool {
+	if el.idempotency == nil {
+		return true
+	}
+
+	seen, err := el.idempotency.Seen(ctx, key)
+	if err != nil {
+		el.logger.Warn("Failed to check idempotency key", zap.Error(err), zap.String("idempotencyKey", key))
+		return true
+	}
+
+	return seen
+}
+
+// decodeLog converts a log entry into its domain event. Unknown events return nil.
+func (el *EthereumListener) decodeLog(ctx context.Context, vLog types.Log) (interface{}, error) {
+	if len(vLog.Topics) == 0 {
+		return nil, nil
+	}
+
 	eventSignature := vLog.Topics[0].Hex()
 
 	el.logger.Debug("Processing log",
 		zap.String("eventSignature", eventSignature),
 		zap.Uint64("blockNumber", vLog.BlockNumber),
+		zap.Bool("removed", vLog.Removed),
 	)
 
 	switch eventSignature {
 	case el.contractABI.Events["ContractAdded"].ID.Hex():
-		return el.handleContractAdded(ctx, vLog)
+		return el.decodeContractAdded(ctx, vLog)
 	case el.contractABI.Events["SLAAdded"].ID.Hex():
-		return el.handleSLAAdded(ctx, vLog)
+		return el.decodeSLAAdded(ctx, vLog)
 	case el.contractABI.Events["SLAStatusUpdated"].ID.Hex():
-		return el.handleSLAStatusUpdated(ctx, vLog)
+		return el.decodeSLAStatusUpdated(ctx, vLog)
 	default:
 		el.logger.Warn("Unknown event signature", zap.String("signature", eventSignature))
 	}
 
-	return nil
+	return nil, nil
 }
 
-// handleContractAdded handles ContractAdded events
-func (el *EthereumListener) handleContractAdded(ctx context.Context, vLog types.Log) error {
+// newBlockchainEvent fills the common event fields from a log entry
+func newBlockchainEvent(eventType domain.EventType, vLog types.Log) domain.BlockchainEvent {
+	return domain.BlockchainEvent{
+		EventType:   eventType,
+		BlockNumber: vLog.BlockNumber,
+		BlockHash:   vLog.BlockHash.Hex(),
+		TxHash:      vLog.TxHash.Hex(),
+		LogIndex:    vLog.Index,
+		Timestamp:   time.Now(),
+	}
+}
+
+// resolveContractID returns the readable contract ID for an indexed topic, or the topic
+// itself when it is unknown
+func (el *EthereumListener) resolveContractID(ctx context.Context, topic common.Hash) string {
+	if id, ok := el.resolver.resolveContract(ctx, topic); ok {
+		return id
+	}
+
+	el.logger.Warn("Could not resolve contract ID from topic hash", zap.String("hash", topic.Hex()))
+	return topic.Hex()
+}
+
+// decodeContractAdded decodes ContractAdded events
+func (el *EthereumListener) decodeContractAdded(ctx context.Context, vLog types.Log) (*domain.ContractAddedEvent, error) {
 	var event binding.SLAEnforcerContractAdded
 
 	err := el.contractABI.UnpackIntoInterface(&event, "ContractAdded", vLog.Data)
 	if err != nil {
-		return fmt.Errorf("failed to unpack ContractAdded event: %w", err)
+		return nil, fmt.Errorf("failed to unpack ContractAdded event: %w", err)
 	}
 
-	// Extract indexed contractId from topics
-	contractID := vLog.Topics[1].Hex()
-
-	domainEvent := &domain.ContractAddedEvent{
-		BlockchainEvent: domain.BlockchainEvent{
-			EventType:   domain.EventTypeContractAdded,
-			BlockNumber: vLog.BlockNumber,
-			TxHash:      vLog.TxHash.Hex(),
-			Timestamp:   time.Now(),
-		},
-		ContractID: contractID,
-		CustomerID: event.CustomerId,
-	}
+	// The indexed contractId topic only holds the hash of the ID
+	contractHash := vLog.Topics[1]
 
-	return el.notifyProcessors(ctx, domainEvent)
+	return &domain.ContractAddedEvent{
+		BlockchainEvent: newBlockchainEvent(domain.EventTypeContractAdded, vLog),
+		ContractID:      el.resolveContractID(ctx, contractHash),
+		ContractIDHash:  contractHash.Hex(),
+		CustomerID:      event.CustomerId,
+	}, nil
 }
 
-// handleSLAAdded handles SLAAdded events
-func (el *EthereumListener) handleSLAAdded(ctx context.Context, vLog types.Log) error {
+// decodeSLAAdded decodes SLAAdded events
+func (el *EthereumListener) decodeSLAAdded(ctx context.Context, vLog types.Log) (*domain.SLAAddedEvent, error) {
 	var event binding.SLAEnforcerSLAAdded
 
 	err := el.contractABI.UnpackIntoInterface(&event, "SLAAdded", vLog.Data)
 	if err != nil {
-		return fmt.Errorf("failed to unpack SLAAdded event: %w", err)
+		return nil, fmt.Errorf("failed to unpack SLAAdded event: %w", err)
 	}
 
-	// Extract indexed contractId from topics
-	contractID := vLog.Topics[1].Hex()
+	// The slaId is emitted in plain text, so later SLAStatusUpdated events can resolve it
+	el.resolver.remember(event.SlaId)
 
-	domainEvent := &domain.SLAAddedEvent{
-		BlockchainEvent: domain.BlockchainEvent{
-			EventType:   domain.EventTypeSLAAdded,
-			BlockNumber: vLog.BlockNumber,
-			TxHash:      vLog.TxHash.Hex(),
-			Timestamp:   time.Now(),
-		},
-		ContractID: contractID,
-		SLAID:      event.SlaId,
-	}
+	// The indexed contractId topic only holds the hash of the ID
+	contractHash := vLog.Topics[1]
 
-	return el.notifyProcessors(ctx, domainEvent)
+	return &domain.SLAAddedEvent{
+		BlockchainEvent: newBlockchainEvent(domain.EventTypeSLAAdded, vLog),
+		ContractID:      el.resolveContractID(ctx, contractHash),
+		ContractIDHash:  contractHash.Hex(),
+		SLAID:           event.SlaId,
+	}, nil
 }
 
-// handleSLAStatusUpdated handles SLAStatusUpdated events
-func (el *EthereumListener) handleSLAStatusUpdated(ctx context.Context, vLog types.Log) error {
+// decodeSLAStatusUpdated decodes SLAStatusUpdated events
+func (el *EthereumListener) decodeSLAStatusUpdated(ctx context.Context, vLog types.Log) (*domain.SLAStatusUpdatedEvent, error) {
 	var event binding.SLAEnforcerSLAStatusUpdated
 
 	err := el.contractABI.UnpackIntoInterface(&event, "SLAStatusUpdated", vLog.Data)
 	if err != nil {
-		return fmt.Errorf("failed to unpack SLAStatusUpdated event: %w", err)
+		return nil, fmt.Errorf("failed to unpack SLAStatusUpdated event: %w", err)
 	}
 
-	// Extract indexed contractId and slaId from topics
-	contractID := vLog.Topics[1].Hex()
-	slaID := vLog.Topics[2].Hex()
+	// Both contractId and slaId are indexed, so the topics only hold their hashes
+	contractHash := vLog.Topics[1]
+	slaHash := vLog.Topics[2]
 
-	domainEvent := &domain.SLAStatusUpdatedEvent{
-		BlockchainEvent: domain.BlockchainEvent{
-			EventType:   domain.EventTypeSLAStatusUpdated,
-			BlockNumber: vLog.BlockNumber,
-			TxHash:      vLog.TxHash.Hex(),
-			Timestamp:   time.Now(),
-		},
-		ContractID: contractID,
-		SLAID:      slaID,
-		NewStatus:  event.NewStatus,
+	contractID, contractResolved := el.resolver.resolveContract(ctx, contractHash)
+	if !contractResolved {
+		el.logger.Warn("Could not resolve contract ID from topic hash", zap.String("hash", contractHash.Hex()))
+		contractID = contractHash.Hex()
 	}
 
-	return el.notifyProcessors(ctx, domainEvent)
+	slaID, slaResolved := "", false
+	if contractResolved {
+		slaID, slaResolved = el.resolver.resolveSLA(ctx, contractID, slaHash)
+	}
+	if !slaResolved {
+		el.logger.Warn("Could not resolve SLA ID from topic hash", zap.String("hash", slaHash.Hex()))
+		slaID = slaHash.Hex()
+	}
+
+	return &domain.SLAStatusUpdatedEvent{
+		BlockchainEvent: newBlockchainEvent(domain.EventTypeSLAStatusUpdated, vLog),
+		ContractID:      contractID,
+		ContractIDHash:  contractHash.Hex(),
+		SLAID:           slaID,
+		SLAIDHash:       slaHash.Hex(),
+		NewStatus:       event.NewStatus,
+	}, nil
 }
@@ -0,0 +1,486 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+const testContractAddress = "0x00000000000000000000000000000000000000aa"
+
+// fakeSubscription is a subscription that never fails
+type fakeSubscription struct {
+	err chan error
+}
+
+func (s *fakeSubscription) Unsubscribe() {}
+
+func (s *fakeSubscription) Err() <-chan error {
+	return s.err
+}
+
+// fakeLogSource is a chain whose head and logs are set by the test. FilterLogs answers from the
+// logs in the queried range, unless reject returns an error for it. The channels of the last
+// subscriptions are kept so the test can feed them.
+type fakeLogSource struct {
+	mu      sync.Mutex
+	head    uint64
+	logs    []types.Log
+	reject  func(from, to uint64) error
+	queries [][2]uint64
+
+	logCh      chan<- types.Log
+	headCh     chan<- *types.Header
+	subscribed chan struct{}
+}
+
+func newFakeLogSource(head uint64, logs ...types.Log) *fakeLogSource {
+	return &fakeLogSource{
+		head:       head,
+		logs:       logs,
+		subscribed: make(chan struct{}, 1),
+	}
+}
+
+func (s *fakeLogSource) BlockNumber(context.Context) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.head, nil
+}
+
+func (s *fakeLogSource) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+	s.queries = append(s.queries, [2]uint64{from, to})
+
+	if s.reject != nil {
+		if err := s.reject(from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	var logs []types.Log
+	for _, vLog := range s.logs {
+		if vLog.BlockNumber >= from && vLog.BlockNumber <= to {
+			logs = append(logs, vLog)
+		}
+	}
+	return logs, nil
+}
+
+func (s *fakeLogSource) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logCh = ch
+	return &fakeSubscription{err: make(chan error)}, nil
+}
+
+func (s *fakeLogSource) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	s.mu.Lock()
+	s.headCh = ch
+	s.mu.Unlock()
+
+	s.subscribed <- struct{}{}
+	return &fakeSubscription{err: make(chan error)}, nil
+}
+
+func (s *fakeLogSource) filtered() [][2]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][2]uint64(nil), s.queries...)
+}
+
+// sendLog delivers a log through the log subscription
+func (s *fakeLogSource) sendLog(vLog types.Log) {
+	s.mu.Lock()
+	ch := s.logCh
+	s.mu.Unlock()
+	ch <- vLog
+}
+
+// sendHead announces a new head through the head subscription
+func (s *fakeLogSource) sendHead(number uint64) {
+	s.mu.Lock()
+	s.head = number
+	ch := s.headCh
+	s.mu.Unlock()
+	ch <- &types.Header{Number: new(big.Int).SetUint64(number)}
+}
+
+// recordingProcessor hands the events it is given to the test
+type recordingProcessor chan interface{}
+
+func (p recordingProcessor) Process(_ context.Context, event interface{}) error {
+	p <- event
+	return nil
+}
+
+// next returns the next processed event
+func (p recordingProcessor) next(t *testing.T) interface{} {
+	t.Helper()
+
+	select {
+	case event := <-p:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event processed")
+		return nil
+	}
+}
+
+// fakeCheckpointStore keeps checkpoints in memory and reports each save
+type fakeCheckpointStore struct {
+	mu     sync.Mutex
+	blocks map[string]uint64
+	saves  chan uint64
+}
+
+func newFakeCheckpointStore() *fakeCheckpointStore {
+	return &fakeCheckpointStore{
+		blocks: make(map[string]uint64),
+		saves:  make(chan uint64, 16),
+	}
+}
+
+func (s *fakeCheckpointStore) Load(_ context.Context, key string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	block, ok := s.blocks[key]
+	return block, ok, nil
+}
+
+func (s *fakeCheckpointStore) Save(_ context.Context, key string, block uint64) error {
+	s.mu.Lock()
+	s.blocks[key] = block
+	s.mu.Unlock()
+	s.saves <- block
+	return nil
+}
+
+// nextSave returns the next saved checkpoint
+func (s *fakeCheckpointStore) nextSave(t *testing.T) uint64 {
+	t.Helper()
+
+	select {
+	case block := <-s.saves:
+		return block
+	case <-time.After(5 * time.Second):
+		t.Fatal("no checkpoint saved")
+		return 0
+	}
+}
+
+// fakeIdempotencyStore remembers keys in memory, without expiry
+type fakeIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (s *fakeIdempotencyStore) Seen(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys[key], nil
+}
+
+func (s *fakeIdempotencyStore) Record(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = true
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Forget(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+// newTestListener creates a listener reading from the fake source, whose contract IDs resolve
+// without an on-chain lookup
+func newTestListener(t *testing.T, source *fakeLogSource, confirmationDepth, chunkSize uint64, checkpoints driven.CheckpointStore) (*EthereumListener, recordingProcessor) {
+	t.Helper()
+
+	processor := make(recordingProcessor, 16)
+	el, err := NewEthereumListener(ListenerConfig{
+		RPCURL:            "ws://localhost:8546",
+		ContractAddress:   testContractAddress,
+		ConfirmationDepth: confirmationDepth,
+		ChunkSize:         chunkSize,
+		ReconnectInterval: time.Second,
+		Dispatch:          DispatchConfig{Workers: 2, QueueSize: 4, DrainTimeout: time.Second},
+		Checkpoints:       checkpoints,
+		Idempotency:       &fakeIdempotencyStore{keys: make(map[string]bool)},
+	}, zap.NewNop(), processor)
+	if err != nil {
+		t.Fatalf("NewEthereumListener() error = %v", err)
+	}
+	el.source = source
+	el.resolver.remember("contract-1")
+	t.Cleanup(func() { el.dispatcher.close(time.Second) })
+
+	return el, processor
+}
+
+// slaAddedLog builds an SLAAdded log of contract-1, in its own transaction
+func slaAddedLog(t *testing.T, el *EthereumListener, block uint64, slaID string) types.Log {
+	t.Helper()
+
+	event := el.contractABI.Events["SLAAdded"]
+	data, err := event.Inputs.NonIndexed().Pack(slaID)
+	if err != nil {
+		t.Fatalf("failed to pack SLAAdded: %v", err)
+	}
+
+	return types.Log{
+		Address:     common.HexToAddress(testContractAddress),
+		Topics:      []common.Hash{event.ID, topicHash("contract-1")},
+		Data:        data,
+		BlockNumber: block,
+		BlockHash:   common.BigToHash(new(big.Int).SetUint64(block)),
+		TxHash:      topicHash(slaID),
+	}
+}
+
+// assertSLAAdded checks that the event is the SLAAdded event of the SLA
+func assertSLAAdded(t *testing.T, event interface{}, slaID string) {
+	t.Helper()
+
+	added, ok := event.(*domain.SLAAddedEvent)
+	if !ok || added.SLAID != slaID || added.ContractID != "contract-1" {
+		t.Errorf("event = %#v, want SLAAdded %s of contract-1", event, slaID)
+	}
+}
+
+// runSubscription runs the WebSocket subscription loop until the test ends
+func runSubscription(t *testing.T, el *EthereumListener, source *fakeLogSource) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- el.subscribeWebSocket(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("subscribeWebSocket() error = %v", err)
+		}
+	})
+
+	<-source.subscribed
+}
+
+func TestProcessRangeHalvesAndRegrowsChunks(t *testing.T) {
+	source := newFakeLogSource(40)
+	el, processor := newTestListener(t, source, 0, 16, nil)
+	source.logs = []types.Log{slaAddedLog(t, el, 3, "sla-1"), slaAddedLog(t, el, 7, "sla-2"), slaAddedLog(t, el, 20, "sla-3")}
+
+	// The first blocks are crowded: only ranges of up to 4 of them are accepted
+	source.reject = func(from, to uint64) error {
+		if from <= 8 && to-from+1 > 4 {
+			return errors.New("query returned more than 10000 results")
+		}
+		return nil
+	}
+
+	processed, err := el.processRange(context.Background(), 1, 40)
+	if processed != 40 || err != nil {
+		t.Fatalf("processRange() = %d, %v, want 40", processed, err)
+	}
+
+	want := [][2]uint64{{1, 16}, {1, 8}, {1, 4}, {5, 12}, {5, 8}, {9, 16}, {17, 32}, {33, 40}}
+	if got := source.filtered(); !slices.Equal(got, want) {
+		t.Errorf("queried ranges %v, want %v", got, want)
+	}
+
+	for _, slaID := range []string{"sla-1", "sla-2", "sla-3"} {
+		assertSLAAdded(t, processor.next(t), slaID)
+	}
+}
+
+func TestProcessRangeStopsAtAFailedChunk(t *testing.T) {
+	tests := []struct {
+		name   string
+		reject func(from, to uint64) error
+		want   uint64
+	}{
+		{
+			name: "provider error",
+			reject: func(from, _ uint64) error {
+				if from > 8 {
+					return errors.New("rate limit exceeded")
+				}
+				return nil
+			},
+			want: 8,
+		},
+		{
+			name: "single block with too many results",
+			reject: func(from, _ uint64) error {
+				if from > 8 {
+					return errors.New("query returned more than 10000 results")
+				}
+				return nil
+			},
+			want: 8,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newFakeLogSource(40)
+			source.reject = tt.reject
+			el, _ := newTestListener(t, source, 0, 8, nil)
+
+			processed, err := el.processRange(context.Background(), 1, 40)
+			if processed != tt.want || err == nil {
+				t.Errorf("processRange() = %d, %v, want %d and an error", processed, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkpoint *uint64
+		startBlock uint64
+		processed  *uint64
+		want       uint64
+	}{
+		{name: "confirmed head", want: 97},
+		{name: "start block", startBlock: 50, want: 49},
+		{name: "checkpoint", checkpoint: ptr(uint64(60)), startBlock: 50, want: 60},
+		{name: "checkpoint at genesis", checkpoint: ptr(uint64(0)), want: 0},
+		{name: "after a reconnect", checkpoint: ptr(uint64(60)), processed: ptr(uint64(80)), want: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoints := newFakeCheckpointStore()
+			el, _ := newTestListener(t, newFakeLogSource(100), 3, 0, checkpoints)
+			el.startBlock = tt.startBlock
+			if tt.checkpoint != nil {
+				checkpoints.blocks[el.checkpointKey()] = *tt.checkpoint
+			}
+			if tt.processed != nil {
+				el.lastProcessedBlock, el.hasProcessed = *tt.processed, true
+			}
+
+			got, err := el.resumeFrom(context.Background(), 100)
+			if got != tt.want || err != nil {
+				t.Errorf("resumeFrom() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSubscribeWebSocketResumesFromCheckpoint(t *testing.T) {
+	checkpoints := newFakeCheckpointStore()
+	checkpoints.blocks[common.HexToAddress(testContractAddress).Hex()] = 10
+
+	source := newFakeLogSource(15)
+	el, processor := newTestListener(t, source, 0, 0, checkpoints)
+	source.logs = []types.Log{slaAddedLog(t, el, 9, "sla-before"), slaAddedLog(t, el, 12, "sla-missed")}
+
+	runSubscription(t, el, source)
+
+	// The blocks after the checkpoint are caught up first
+	if got := checkpoints.nextSave(t); got != 15 {
+		t.Fatalf("checkpoint after catch-up = %d, want 15", got)
+	}
+	if got := source.filtered(); !slices.Equal(got, [][2]uint64{{11, 15}}) {
+		t.Errorf("caught up %v, want blocks 11 to 15", got)
+	}
+	assertSLAAdded(t, processor.next(t), "sla-missed")
+
+	// Without a confirmation depth, the checkpoint stays one block behind the head
+	source.sendLog(slaAddedLog(t, el, 16, "sla-live"))
+	source.sendHead(16)
+	assertSLAAdded(t, processor.next(t), "sla-live")
+	source.sendHead(17)
+	if got := checkpoints.nextSave(t); got != 16 {
+		t.Errorf("checkpoint after head 17 = %d, want 16", got)
+	}
+}
+
+func TestSubscribeWebSocketRetractsRemovedLogs(t *testing.T) {
+	source := newFakeLogSource(5)
+	el, processor := newTestListener(t, source, 0, 0, nil)
+	runSubscription(t, el, source)
+
+	notified := slaAddedLog(t, el, 6, "sla-1")
+	source.sendLog(notified)
+	assertSLAAdded(t, processor.next(t), "sla-1")
+
+	notified.Removed = true
+	source.sendLog(notified)
+	retracted, ok := processor.next(t).(*domain.EventRetractedEvent)
+	if !ok {
+		t.Fatalf("event = %T, want a retraction", retracted)
+	}
+	assertSLAAdded(t, retracted.Retracted, "sla-1")
+
+	// A removed log that was never notified is not retracted: the next event of the contract,
+	// processed by the same worker, comes first
+	neverSeen := slaAddedLog(t, el, 6, "sla-2")
+	neverSeen.Removed = true
+	source.sendLog(neverSeen)
+	source.sendLog(slaAddedLog(t, el, 7, "sla-3"))
+	assertSLAAdded(t, processor.next(t), "sla-3")
+}
+
+func TestSubscribeWebSocketDiscardsRemovedLogsBeforeConfirmation(t *testing.T) {
+	source := newFakeLogSource(5)
+	el, processor := newTestListener(t, source, 2, 0, nil)
+	runSubscription(t, el, source)
+
+	reorged := slaAddedLog(t, el, 6, "sla-1")
+	source.sendLog(reorged)
+	reorged.Removed = true
+	source.sendLog(reorged)
+	source.sendLog(slaAddedLog(t, el, 7, "sla-2"))
+
+	for head := uint64(6); head <= 9; head++ {
+		source.sendHead(head)
+	}
+	assertSLAAdded(t, processor.next(t), "sla-2")
+
+	select {
+	case event := <-processor:
+		t.Errorf("unexpected event %#v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestIsTooManyResultsError(t *testing.T) {
+	for msg, want := range map[string]bool{
+		"query returned more than 10000 results":         true,
+		"exceed maximum block range: 5000":               true,
+		"Log response size exceeded. You can make...":    true,
+		"invalid block range params":                     false,
+		"rate limit exceeded":                            false,
+		fmt.Sprintf("%d: too many results", -32005):      true,
+		"eth_getLogs is limited to a 10,000 block range": false,
+	} {
+		if got := isTooManyResultsError(errors.New(msg)); got != want {
+			t.Errorf("isTooManyResultsError(%q) = %t, want %t", msg, got, want)
+		}
+	}
+}
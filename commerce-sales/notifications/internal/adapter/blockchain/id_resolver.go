@@ -0,0 +1,139 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+
+	"notifications/internal/adapter/blockchain/binding"
+)
+
+// idResolver turns the topics of indexed string parameters, which only hold keccak256(id),
+// back into readable contract and SLA IDs. It keeps a local hash→ID index that is fed with
+// the plain IDs found in event data and filled on demand from the contract's storage.
+type idResolver struct {
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	caller    *binding.SLAEnforcerCaller
+	ids       map[common.Hash]string
+	contracts uint64 // number of on-chain contracts already indexed
+}
+
+// newIDResolver creates a new resolver with an empty index
+func newIDResolver(logger *zap.Logger) *idResolver {
+	return &idResolver{
+		logger: logger,
+		ids:    make(map[common.Hash]string),
+	}
+}
+
+// setCaller sets the contract binding used for on-chain lookups, e.g. after a reconnect
+func (r *idResolver) setCaller(caller *binding.SLAEnforcerCaller) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.caller = caller
+}
+
+// remember adds a plain ID to the index
+func (r *idResolver) remember(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rememberLocked(id)
+}
+
+// resolveContract returns the contract ID whose hash is the given topic. Unknown hashes
+// trigger an index of the contracts added on chain since the last lookup.
+func (r *idResolver) resolveContract(ctx context.Context, topic common.Hash) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[topic]; ok {
+		return id, true
+	}
+
+	if err := r.indexContractsLocked(ctx); err != nil {
+		r.logger.Warn("Failed to index on-chain contracts", zap.Error(err))
+		return "", false
+	}
+
+	id, ok := r.ids[topic]
+	return id, ok
+}
+
+// resolveSLA returns the SLA ID whose hash is the given topic. Unknown hashes trigger
+// an index of the SLAs of the given contract.
+func (r *idResolver) resolveSLA(ctx context.Context, contractID string, topic common.Hash) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[topic]; ok {
+		return id, true
+	}
+
+	if contractID == "" || r.caller == nil {
+		return "", false
+	}
+
+	slas, err := r.caller.GetSLAs(&bind.CallOpts{Context: ctx}, contractID)
+	if err != nil {
+		r.logger.Warn("Failed to index contract SLAs",
+			zap.Error(err),
+			zap.String("contractId", contractID),
+		)
+		return "", false
+	}
+
+	for _, sla := range slas {
+		r.rememberLocked(sla.Id)
+	}
+
+	id, ok := r.ids[topic]
+	return id, ok
+}
+
+// indexContractsLocked adds the IDs of the contracts created since the last call. The caller must hold r.mu.
+func (r *idResolver) indexContractsLocked(ctx context.Context) error {
+	if r.caller == nil {
+		return fmt.Errorf("no contract binding available")
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+
+	count, err := r.caller.GetContractCount(opts)
+	if err != nil {
+		return fmt.Errorf("failed to get contract count: %w", err)
+	}
+
+	for i := r.contracts; i < count.Uint64(); i++ {
+		contract, err := r.caller.Contracts(opts, new(big.Int).SetUint64(i))
+		if err != nil {
+			return fmt.Errorf("failed to get contract %d: %w", i, err)
+		}
+		r.rememberLocked(contract.Id)
+		r.contracts = i + 1
+	}
+
+	return nil
+}
+
+// rememberLocked adds a plain ID to the index. The caller must hold r.mu.
+func (r *idResolver) rememberLocked(id string) {
+	if id == "" {
+		return
+	}
+	r.ids[topicHash(id)] = id
+}
+
+// topicHash returns the topic Solidity emits for an indexed string parameter
+func topicHash(id string) common.Hash {
+	return crypto.Keccak256Hash([]byte(id))
+}
@@ -0,0 +1,107 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notifications/internal/core/port/driven"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is a CheckpointStore backed by a JSON file mapping each key to its last processed block.
+// Writes go to a temporary file that is renamed over the original, so a crash never leaves a partial file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Ensure FileStore implements the driven.CheckpointStore interface
+var _ driven.CheckpointStore = (*FileStore)(nil)
+
+// NewFileStore creates a new file-backed checkpoint store, creating the parent directory if needed
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	return &FileStore{
+		path: path,
+	}, nil
+}
+
+// Load returns the last processed block for the given key
+func (s *FileStore) Load(_ context.Context, key string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return 0, false, err
+	}
+
+	block, ok := checkpoints[key]
+	return block, ok, nil
+}
+
+// Save records the last processed block for the given key
+func (s *FileStore) Save(_ context.Context, key string, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = block
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync checkpoint file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint file: %w", err)
+	}
+
+	return nil
+}
+
+// read loads all checkpoints from disk. A missing file means no checkpoints yet.
+func (s *FileStore) read() (map[string]uint64, error) {
+	checkpoints := make(map[string]uint64)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint file: %w", err)
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,66 @@
+package checkpoint
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"notifications/internal/core/port/driven"
+)
+
+// SQLStore is a CheckpointStore backed by a PostgreSQL table
+type SQLStore struct {
+	db *sql.DB
+}
+
+// Ensure SQLStore implements the driven.CheckpointStore interface
+var _ driven.CheckpointStore = (*SQLStore)(nil)
+
+// NewSQLStore creates a new SQL checkpoint store and makes sure its table exists
+func NewSQLStore(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS listener_checkpoints (
+			key          TEXT PRIMARY KEY,
+			block_number BIGINT      NOT NULL,
+			updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint table: %w", err)
+	}
+
+	return &SQLStore{
+		db: db,
+	}, nil
+}
+
+// Load returns the last processed block for the given key
+func (s *SQLStore) Load(ctx context.Context, key string) (uint64, bool, error) {
+	var block int64
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT block_number FROM listener_checkpoints WHERE key = $1`, key,
+	).Scan(&block)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	return uint64(block), true, nil
+}
+
+// Save records the last processed block for the given key
+func (s *SQLStore) Save(ctx context.Context, key string, block uint64) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO listener_checkpoints (key, block_number, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (key) DO UPDATE SET block_number = EXCLUDED.block_number, updated_at = now()`,
+		key, int64(block),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package customer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+// HTTPDirectory is an adapter that looks customers up through the contracts service API (GET /customers/:id)
+type HTTPDirectory struct {
+	baseURL string
+	client  *http.Client
+}
+
+// Ensure HTTPDirectory implements the driven.CustomerDirectory interface
+var _ driven.CustomerDirectory = (*HTTPDirectory)(nil)
+
+// NewHTTPDirectory creates a new directory for the contracts service at baseURL, e.g. http://contracts:8080
+func NewHTTPDirectory(baseURL string, timeout time.Duration) *HTTPDirectory {
+	return &HTTPDirectory{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// FindCustomer fetches the customer. A 404 response is reported as driven.ErrCustomerNotFound.
+func (d *HTTPDirectory) FindCustomer(ctx context.Context, customerID string) (*domain.Customer, error) {
+	endpoint := d.baseURL + "/customers/" + url.PathEscape(customerID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call contracts service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("customer %s: %w", customerID, driven.ErrCustomerNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("contracts service responded with status %d", resp.StatusCode)
+	}
+
+	var customer domain.Customer
+	if err := json.NewDecoder(resp.Body).Decode(&customer); err != nil {
+		return nil, fmt.Errorf("failed to decode customer: %w", err)
+	}
+
+	return &customer, nil
+}
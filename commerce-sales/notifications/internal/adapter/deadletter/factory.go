@@ -0,0 +1,41 @@
+package deadletter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"notifications/config"
+	"notifications/internal/core/port/driven"
+)
+
+// NewFromConfig creates the dead-letter store selected by DEAD_LETTER_STORE, or nil for none.
+// The returned function releases its resources. The postgres store needs a registered "postgres" driver.
+func NewFromConfig(ctx context.Context, cfg *config.Config) (driven.DeadLetterStore, func(), error) {
+	switch cfg.DeadLetterStore {
+	case "file":
+		store, err := NewFileStore(cfg.DeadLetterFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, func() {}, nil
+	case "postgres":
+		db, err := sql.Open("postgres", cfg.DeadLetterDatabaseURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		if err := db.PingContext(ctx); err != nil {
+			db.Close()
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		store, err := NewSQLStore(ctx, db)
+		if err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		return store, func() { db.Close() }, nil
+	default:
+		return nil, func() {}, nil
+	}
+}
@@ -0,0 +1,150 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// FileStore is a DeadLetterStore backed by a JSON file holding the list of dead letters.
+// Writes go to a temporary file that is renamed over the original, so a crash never leaves a partial file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Ensure FileStore implements the driven.DeadLetterStore interface
+var _ driven.DeadLetterStore = (*FileStore)(nil)
+
+// NewFileStore creates a new file-backed dead-letter store, creating the parent directory if needed
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+
+	return &FileStore{
+		path: path,
+	}, nil
+}
+
+// Save adds the dead letter, or replaces the one with the same ID
+func (s *FileStore) Save(_ context.Context, deadLetter domain.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetters, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(deadLetters, func(d domain.DeadLetter) bool { return d.ID == deadLetter.ID })
+	if i >= 0 {
+		deadLetters[i] = deadLetter
+	} else {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return s.write(deadLetters)
+}
+
+// Get returns the dead letter with the given ID
+func (s *FileStore) Get(_ context.Context, id string) (*domain.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetters, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(deadLetters, func(d domain.DeadLetter) bool { return d.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", driven.ErrDeadLetterNotFound, id)
+	}
+
+	return &deadLetters[i], nil
+}
+
+// List returns all dead letters, oldest first
+func (s *FileStore) List(_ context.Context) ([]domain.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+// Delete removes the dead letter with the given ID
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetters, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(deadLetters, func(d domain.DeadLetter) bool { return d.ID == id })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", driven.ErrDeadLetterNotFound, id)
+	}
+
+	return s.write(slices.Delete(deadLetters, i, i+1))
+}
+
+// read loads all dead letters from disk. A missing file means no dead letters yet.
+func (s *FileStore) read() ([]domain.DeadLetter, error) {
+	deadLetters := make([]domain.DeadLetter, 0)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return deadLetters, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead-letter file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &deadLetters); err != nil {
+		return nil, fmt.Errorf("failed to decode dead-letter file: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// write replaces the file with the given dead letters
+func (s *FileStore) write(deadLetters []domain.DeadLetter) error {
+	data, err := json.MarshalIndent(deadLetters, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letters: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create dead-letter file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write dead-letter file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync dead-letter file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close dead-letter file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace dead-letter file: %w", err)
+	}
+
+	return nil
+}
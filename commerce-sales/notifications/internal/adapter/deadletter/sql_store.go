@@ -0,0 +1,148 @@
+package deadletter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+// SQLStore is a DeadLetterStore backed by a PostgreSQL table
+type SQLStore struct {
+	db *sql.DB
+}
+
+// Ensure SQLStore implements the driven.DeadLetterStore interface
+var _ driven.DeadLetterStore = (*SQLStore)(nil)
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// NewSQLStore creates a new SQL dead-letter store and makes sure its table exists
+func NewSQLStore(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS dead_letters (
+			id              TEXT PRIMARY KEY,
+			processor       TEXT        NOT NULL,
+			event_type      TEXT        NOT NULL,
+			event           JSONB       NOT NULL,
+			idempotency_key TEXT        NOT NULL DEFAULT '',
+			error           TEXT        NOT NULL,
+			attempts        INTEGER     NOT NULL,
+			failed_at       TIMESTAMPTZ NOT NULL
+		)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter table: %w", err)
+	}
+
+	return &SQLStore{
+		db: db,
+	}, nil
+}
+
+// Save adds the dead letter, or replaces the one with the same ID
+func (s *SQLStore) Save(ctx context.Context, deadLetter domain.DeadLetter) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO dead_letters (id, processor, event_type, event, idempotency_key, error, attempts, failed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			error = EXCLUDED.error,
+			attempts = EXCLUDED.attempts,
+			failed_at = EXCLUDED.failed_at`,
+		deadLetter.ID, deadLetter.Processor, string(deadLetter.EventType), []byte(deadLetter.Event),
+		deadLetter.IdempotencyKey, deadLetter.Error, deadLetter.Attempts, deadLetter.FailedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the dead letter with the given ID
+func (s *SQLStore) Get(ctx context.Context, id string) (*domain.DeadLetter, error) {
+	deadLetter, err := scanDeadLetter(s.db.QueryRowContext(ctx, `
+		SELECT id, processor, event_type, event, idempotency_key, error, attempts, failed_at
+		FROM dead_letters
+		WHERE id = $1`, id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", driven.ErrDeadLetterNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letter: %w", err)
+	}
+
+	return deadLetter, nil
+}
+
+// List returns all dead letters, oldest first
+func (s *SQLStore) List(ctx context.Context) ([]domain.DeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, processor, event_type, event, idempotency_key, error, attempts, failed_at
+		FROM dead_letters
+		ORDER BY failed_at, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := make([]domain.DeadLetter, 0)
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// Delete removes the dead letter with the given ID
+func (s *SQLStore) Delete(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM dead_letters WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", driven.ErrDeadLetterNotFound, id)
+	}
+
+	return nil
+}
+
+// scanDeadLetter reads a dead letter from a row
+func scanDeadLetter(row rowScanner) (*domain.DeadLetter, error) {
+	var deadLetter domain.DeadLetter
+	var eventType string
+	var event []byte
+
+	if err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.Processor,
+		&eventType,
+		&event,
+		&deadLetter.IdempotencyKey,
+		&deadLetter.Error,
+		&deadLetter.Attempts,
+		&deadLetter.FailedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	deadLetter.EventType = domain.EventType(eventType)
+	deadLetter.Event = event
+
+	return &deadLetter, nil
+}
@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/port/driver"
+)
+
+// Server is a small HTTP server exposing the liveness, readiness and metrics endpoints
+type Server struct {
+	server      *http.Server
+	listener    driver.BlockchainListener
+	maxBlockLag uint64
+	logger      *zap.Logger
+}
+
+// NewServer creates a new Server on addr. The listener is ready while it is connected and at most
+// maxBlockLag confirmed blocks behind. The metrics handler is optional; without it /metrics is not served.
+func NewServer(addr string, listener driver.BlockchainListener, maxBlockLag uint64, metrics http.Handler, logger *zap.Logger) *Server {
+	s := &Server{
+		listener:    listener,
+		maxBlockLag: maxBlockLag,
+		logger:      logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	if metrics != nil {
+		mux.Handle("/metrics", metrics)
+	}
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return s
+}
+
+// Start binds the address and serves requests in the background
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
+	}
+
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Health server stopped", zap.Error(err))
+		}
+	}()
+
+	s.logger.Info("Health server listening", zap.String("address", ln.Addr().String()))
+
+	return nil
+}
+
+// Shutdown stops the server, waiting for in-flight requests until the context is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// healthz reports that the process is alive
+func (s *Server) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// readyz reports whether the listener is connected and keeping up with the chain
+func (s *Server) readyz(w http.ResponseWriter, _ *http.Request) {
+	connected := s.listener.IsConnected()
+	lag, known := s.listener.BlockLag()
+
+	body := map[string]any{
+		"connected":   connected,
+		"maxBlockLag": s.maxBlockLag,
+	}
+	if known {
+		body["blockLag"] = lag
+	}
+
+	switch {
+	case !connected:
+		body["status"] = "not connected to the blockchain node"
+	case !known:
+		body["status"] = "waiting for the first block"
+	case lag > s.maxBlockLag:
+		body["status"] = "behind the chain head"
+	default:
+		body["status"] = "ready"
+		writeJSON(w, http.StatusOK, body)
+		return
+	}
+
+	writeJSON(w, http.StatusServiceUnavailable, body)
+}
+
+// writeJSON writes the body as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
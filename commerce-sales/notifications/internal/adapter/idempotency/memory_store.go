@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"container/list"
+	"context"
+	"notifications/internal/core/port/driven"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an IdempotencyStore that keeps keys in memory for a TTL, holding at most
+// maxEntries keys. When full, the keys closest to expiring are evicted first.
+type MemoryStore struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // *memoryEntry values, oldest expiry first
+}
+
+// memoryEntry is a key and the time it expires at
+type memoryEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// Ensure MemoryStore implements the driven.IdempotencyStore interface
+var _ driven.IdempotencyStore = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory idempotency store. A maxEntries of 0 means unbounded.
+func NewMemoryStore(ttl time.Duration, maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Seen reports whether the key was recorded and hasn't expired yet
+func (s *MemoryStore) Seen(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired(time.Now())
+
+	_, ok := s.entries[key]
+	return ok, nil
+}
+
+// Record marks the key as notified for the store's TTL
+func (s *MemoryStore) Record(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evictExpired(now)
+
+	// Every entry has the same TTL, so appending keeps the list ordered by expiry
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+	}
+	s.entries[key] = s.order.PushBack(&memoryEntry{key: key, expiresAt: now.Add(s.ttl)})
+
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		s.remove(s.order.Front())
+	}
+
+	return nil
+}
+
+// Forget removes the key
+func (s *MemoryStore) Forget(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+// evictExpired removes the keys that expired before now. The caller must hold s.mu.
+func (s *MemoryStore) evictExpired(now time.Time) {
+	for element := s.order.Front(); element != nil; element = s.order.Front() {
+		if element.Value.(*memoryEntry).expiresAt.After(now) {
+			return
+		}
+		s.remove(element)
+	}
+}
+
+// remove deletes an entry from the index and the expiry list. The caller must hold s.mu.
+func (s *MemoryStore) remove(element *list.Element) {
+	delete(s.entries, element.Value.(*memoryEntry).key)
+	s.order.Remove(element)
+}
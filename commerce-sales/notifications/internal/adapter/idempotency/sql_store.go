@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"notifications/internal/core/port/driven"
+	"sync/atomic"
+	"time"
+)
+
+// pruneEvery is the number of recorded keys after which expired rows are deleted
+const pruneEvery = 1000
+
+// SQLStore is an IdempotencyStore backed by a PostgreSQL table. Expired rows are ignored
+// and deleted periodically, which keeps the table bounded by the TTL.
+type SQLStore struct {
+	db       *sql.DB
+	ttl      time.Duration
+	recorded atomic.Uint64
+}
+
+// Ensure SQLStore implements the driven.IdempotencyStore interface
+var _ driven.IdempotencyStore = (*SQLStore)(nil)
+
+// NewSQLStore creates a new SQL idempotency store and makes sure its table exists
+func NewSQLStore(ctx context.Context, db *sql.DB, ttl time.Duration) (*SQLStore, error) {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS notified_events (
+			key        TEXT PRIMARY KEY,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency table: %w", err)
+	}
+
+	return &SQLStore{
+		db:  db,
+		ttl: ttl,
+	}, nil
+}
+
+// Seen reports whether the key was recorded and hasn't expired yet
+func (s *SQLStore) Seen(ctx context.Context, key string) (bool, error) {
+	var seen bool
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM notified_events WHERE key = $1 AND expires_at > now())`, key,
+	).Scan(&seen)
+	if err != nil {
+		return false, fmt.Errorf("failed to query idempotency key: %w", err)
+	}
+
+	return seen, nil
+}
+
+// Record marks the key as notified for the store's TTL
+func (s *SQLStore) Record(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO notified_events (key, expires_at)
+		VALUES ($1, now() + make_interval(secs => $2))
+		ON CONFLICT (key) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		key, s.ttl.Seconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record idempotency key: %w", err)
+	}
+
+	if s.recorded.Add(1)%pruneEvery == 0 {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM notified_events WHERE expires_at <= now()`); err != nil {
+			return fmt.Errorf("failed to prune idempotency keys: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Forget removes the key
+func (s *SQLStore) Forget(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM notified_events WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to forget idempotency key: %w", err)
+	}
+
+	return nil
+}
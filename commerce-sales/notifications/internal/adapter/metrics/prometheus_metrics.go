@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"notifications/internal/core/port/driven"
+)
+
+// PrometheusMetrics records the service metrics in a Prometheus registry of its own
+type PrometheusMetrics struct {
+	registry          *prometheus.Registry
+	eventsReceived    *prometheus.CounterVec
+	processorDuration *prometheus.HistogramVec
+	processorFailures *prometheus.CounterVec
+	notificationsSent *prometheus.CounterVec
+	reconnects        prometheus.Counter
+	blockLag          prometheus.Gauge
+}
+
+// Ensure PrometheusMetrics implements the driven.Metrics interface
+var _ driven.Metrics = (*PrometheusMetrics)(nil)
+
+// NewPrometheusMetrics creates the service metrics, along with the Go runtime and process metrics
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		eventsReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_events_received_total",
+			Help: "Blockchain events decoded by the listener, by event type.",
+		}, []string{"event_type"}),
+		processorDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notifications_processor_duration_seconds",
+			Help:    "Time an event processor took to handle an event, by processor.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"processor"}),
+		processorFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_processor_failures_total",
+			Help: "Events an event processor failed to handle, by processor.",
+		}, []string{"processor"}),
+		notificationsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notifications_sent_total",
+			Help: "Notifications sent by a notifier backend, by backend and result (success or failure).",
+		}, []string{"backend", "result"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "notifications_listener_reconnects_total",
+			Help: "Reconnections to the blockchain node.",
+		}),
+		blockLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "notifications_listener_block_lag",
+			Help: "Confirmed blocks the listener has not processed yet.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.eventsReceived,
+		m.processorDuration,
+		m.processorFailures,
+		m.notificationsSent,
+		m.reconnects,
+		m.blockLag,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// EventReceived counts a decoded event of the given type
+func (m *PrometheusMetrics) EventReceived(eventType string) {
+	m.eventsReceived.WithLabelValues(eventType).Inc()
+}
+
+// ProcessorCompleted records how long a processor took to handle an event, and whether it failed
+func (m *PrometheusMetrics) ProcessorCompleted(processor string, duration time.Duration, err error) {
+	m.processorDuration.WithLabelValues(processor).Observe(duration.Seconds())
+	if err != nil {
+		m.processorFailures.WithLabelValues(processor).Inc()
+	}
+}
+
+// NotificationSent counts a notification delivered, or failed, by a notifier backend
+func (m *PrometheusMetrics) NotificationSent(backend string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.notificationsSent.WithLabelValues(backend, result).Inc()
+}
+
+// Reconnected counts a reconnection to the blockchain node
+func (m *PrometheusMetrics) Reconnected() {
+	m.reconnects.Inc()
+}
+
+// BlockLag records how many confirmed blocks are not processed yet
+func (m *PrometheusMetrics) BlockLag(blocks uint64) {
+	m.blockLag.Set(float64(blocks))
+}
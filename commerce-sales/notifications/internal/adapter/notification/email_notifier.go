@@ -0,0 +1,248 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/port/driven"
+)
+
+// defaultTemplate is the template used for event types without their own template
+const defaultTemplate = "default"
+
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// SMTPConfig holds the settings of the SMTP server the EmailNotifier sends through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // authentication is skipped when empty
+	Password string
+	From     string
+	To       []string
+	Timeout  time.Duration
+}
+
+// EmailNotifier is an adapter that renders events with a per-event-type template and sends them by SMTP.
+// Templates are named after the event type (e.g. SLAViolated.tmpl) and define a "subject" and a "body"
+// template, executed with the notification message; default.tmpl covers the other event types.
+type EmailNotifier struct {
+	config    SMTPConfig
+	templates map[string]*template.Template
+	logger    *zap.Logger
+}
+
+// Ensure EmailNotifier implements the driven.Notifier and driven.DirectNotifier interfaces
+var (
+	_ driven.Notifier       = (*EmailNotifier)(nil)
+	_ driven.DirectNotifier = (*EmailNotifier)(nil)
+)
+
+// NewEmailNotifier creates a new EmailNotifier instance. Templates found in templateDir
+// (optional) replace the built-in template of the same event type.
+func NewEmailNotifier(config SMTPConfig, templateDir string, logger *zap.Logger) (*EmailNotifier, error) {
+	builtIn, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open built-in templates: %w", err)
+	}
+
+	templates, err := loadTemplates(builtIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateDir != "" {
+		overrides, err := loadTemplates(os.DirFS(templateDir))
+		if err != nil {
+			return nil, err
+		}
+		for eventType, tmpl := range overrides {
+			templates[eventType] = tmpl
+		}
+	}
+
+	logger.Info("Email notifier initialized",
+		zap.String("smtpHost", config.Host),
+		zap.Int("smtpPort", config.Port),
+		zap.Strings("to", config.To),
+		zap.Int("templates", len(templates)),
+	)
+
+	return &EmailNotifier{
+		config:    config,
+		templates: templates,
+		logger:    logger,
+	}, nil
+}
+
+// SendNotification renders the template of the event type and mails it to the configured recipients
+func (n *EmailNotifier) SendNotification(ctx context.Context, message map[string]any, eventType string) error {
+	return n.deliver(ctx, n.config.To, message, eventType)
+}
+
+// SendTo renders the template of the event type and mails it to the given address
+func (n *EmailNotifier) SendTo(ctx context.Context, recipient string, message map[string]any, eventType string) error {
+	return n.deliver(ctx, []string{recipient}, message, eventType)
+}
+
+// deliver renders the template of the event type and mails it to the recipients
+func (n *EmailNotifier) deliver(ctx context.Context, to []string, message map[string]any, eventType string) error {
+	subject, body, err := n.render(message, eventType)
+	if err != nil {
+		n.logger.Error("Failed to render email",
+			zap.Error(err),
+			zap.String("eventType", eventType),
+		)
+		return err
+	}
+
+	if err := n.send(ctx, to, n.compose(to, subject, body)); err != nil {
+		n.logger.Error("Failed to send email",
+			zap.Error(err),
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	n.logger.Info("Successfully sent email",
+		zap.String("subject", subject),
+		zap.Strings("to", to),
+		zap.String("eventType", eventType),
+	)
+
+	return nil
+}
+
+// render executes the "subject" and "body" templates of the event type
+func (n *EmailNotifier) render(message map[string]any, eventType string) (string, string, error) {
+	tmpl, ok := n.templates[eventType]
+	if !ok {
+		tmpl = n.templates[defaultTemplate]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", message); err != nil {
+		return "", "", fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", message); err != nil {
+		return "", "", fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// compose builds a plain text RFC 5322 message
+func (n *EmailNotifier) compose(to []string, subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+	return msg.Bytes()
+}
+
+// send delivers the message like smtp.SendMail, but bounded by the context and the configured timeout
+func (n *EmailNotifier) send(ctx context.Context, to []string, msg []byte) error {
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+
+	dialer := &net.Dialer{Timeout: n.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(n.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return err
+		}
+	}
+
+	if n.config.Username != "" {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.config.From); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// loadTemplates parses every *.tmpl file of fsys, keyed by file name without extension
+func loadTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
+	}
+
+	templates := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		tmpl, err := template.ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", file, err)
+		}
+		for _, name := range []string{"subject", "body"} {
+			if tmpl.Lookup(name) == nil {
+				return nil, fmt.Errorf("email template %s does not define %q", file, name)
+			}
+		}
+		templates[strings.TrimSuffix(path.Base(file), ".tmpl")] = tmpl
+	}
+
+	return templates, nil
+}
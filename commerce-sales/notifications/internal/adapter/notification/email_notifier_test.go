@@ -0,0 +1,269 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// smtpMail is a message received by the SMTP stub
+type smtpMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStub is a minimal SMTP server accepting every message, without STARTTLS nor authentication.
+// rejectRcpt makes it refuse the recipients, as a server would for unknown mailboxes.
+type smtpStub struct {
+	listener   net.Listener
+	rejectRcpt bool
+
+	mu    sync.Mutex
+	mails []smtpMail
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	stub := &smtpStub{listener: listener}
+	go stub.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return stub
+}
+
+// config returns the SMTP settings of an EmailNotifier sending through the stub
+func (s *smtpStub) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	return SMTPConfig{
+		Host:    host,
+		Port:    portNumber,
+		From:    "alerts@medisupply.test",
+		To:      []string{"ops@medisupply.test"},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func (s *smtpStub) received() []smtpMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.mails)
+}
+
+func (s *smtpStub) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStub) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 stub ESMTP")
+
+	var mail smtpMail
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 stub")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mail = smtpMail{from: strings.Trim(line[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if s.rejectRcpt {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			mail.data = data.String()
+
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case command == "RSET", command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func TestEmailNotifierSendNotification(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	notifier, err := NewEmailNotifier(stub.config(), "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	message := map[string]any{"eventType": "Unmapped", "contractId": "contract-1"}
+	if err := notifier.SendNotification(context.Background(), message, "Unmapped"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	mails := stub.received()
+	if len(mails) != 1 {
+		t.Fatalf("received %d mails, want 1", len(mails))
+	}
+
+	mail := mails[0]
+	if mail.from != "alerts@medisupply.test" {
+		t.Errorf("MAIL FROM = %q, want alerts@medisupply.test", mail.from)
+	}
+	if !slices.Equal(mail.to, []string{"ops@medisupply.test"}) {
+		t.Errorf("RCPT TO = %v, want [ops@medisupply.test]", mail.to)
+	}
+
+	// Rendered with the built-in default template
+	for _, want := range []string{
+		"To: ops@medisupply.test\r\n",
+		"Subject: Blockchain Event: Unmapped\r\n",
+		"Content-Type: text/plain; charset=UTF-8\r\n",
+		"contractId: contract-1",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("mail does not contain %q:\n%s", want, mail.data)
+		}
+	}
+}
+
+func TestEmailNotifierSendTo(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	notifier, err := NewEmailNotifier(stub.config(), "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	if err := notifier.SendTo(context.Background(), "customer@hospital.test", map[string]any{}, "Unmapped"); err != nil {
+		t.Fatalf("SendTo() error = %v", err)
+	}
+
+	mails := stub.received()
+	if len(mails) != 1 || !slices.Equal(mails[0].to, []string{"customer@hospital.test"}) {
+		t.Fatalf("received %+v, want one mail to customer@hospital.test", mails)
+	}
+}
+
+func TestEmailNotifierTemplateOverride(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	dir := t.TempDir()
+	override := `{{define "subject"}}Contract {{.contractId}} added{{end}}{{define "body"}}Custom body{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "ContractAdded.tmpl"), []byte(override), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	notifier, err := NewEmailNotifier(stub.config(), dir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{"contractId": "contract-1"}, "ContractAdded"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	mails := stub.received()
+	if len(mails) != 1 {
+		t.Fatalf("received %d mails, want 1", len(mails))
+	}
+	if !strings.Contains(mails[0].data, "Subject: Contract contract-1 added\r\n") ||
+		!strings.Contains(mails[0].data, "Custom body") {
+		t.Errorf("mail not rendered with the override:\n%s", mails[0].data)
+	}
+}
+
+func TestEmailNotifierInvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ContractAdded.tmpl"), []byte(`{{define "subject"}}only a subject{{end}}`), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	if _, err := NewEmailNotifier(SMTPConfig{}, dir, zap.NewNop()); err == nil {
+		t.Fatal("NewEmailNotifier() error = nil, want missing body template")
+	}
+}
+
+func TestEmailNotifierRejectedRecipient(t *testing.T) {
+	stub := newSMTPStub(t)
+	stub.rejectRcpt = true
+
+	notifier, err := NewEmailNotifier(stub.config(), "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "Unmapped"); err == nil {
+		t.Fatal("SendNotification() error = nil, want rejected recipient")
+	}
+	if mails := stub.received(); len(mails) != 0 {
+		t.Errorf("received %d mails, want none", len(mails))
+	}
+}
+
+func TestEmailNotifierUnreachableServer(t *testing.T) {
+	stub := newSMTPStub(t)
+	config := stub.config()
+	stub.listener.Close()
+
+	notifier, err := NewEmailNotifier(config, "", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "Unmapped"); err == nil {
+		t.Fatal("SendNotification() error = nil, want connection error")
+	}
+}
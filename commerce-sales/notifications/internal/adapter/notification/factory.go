@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/config"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+// NewFromConfig creates a FanOutNotifier over the backends selected by NOTIFIERS. With metrics,
+// every backend counts its notifications. The returned function releases their resources.
+func NewFromConfig(ctx context.Context, cfg *config.Config, metrics driven.Metrics, logger *zap.Logger) (driven.Notifier, func(), error) {
+	var backends []Backend
+	var closers []func()
+
+	closeAll := func() {
+		for _, closeBackend := range closers {
+			closeBackend()
+		}
+	}
+
+	for _, name := range cfg.Notifiers {
+		var notifier driven.Notifier
+
+		switch name {
+		case "sns":
+			snsNotifier, err := NewSNSNotifier(ctx, cfg.AWSRegion, cfg.SNSTopicARN, logger)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			notifier = snsNotifier
+		case "webhook":
+			notifier = NewWebhookNotifier(
+				cfg.WebhookURL,
+				cfg.WebhookSecret,
+				time.Duration(cfg.WebhookTimeout)*time.Second,
+				logger,
+			)
+		case "email":
+			emailNotifier, err := NewEmailNotifier(SMTPConfig{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.EmailFrom,
+				To:       cfg.EmailTo,
+				Timeout:  time.Duration(cfg.SMTPTimeout) * time.Second,
+			}, cfg.EmailTemplateDir, logger)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			notifier = emailNotifier
+		case "rabbitmq":
+			rabbitNotifier, err := NewRabbitMQNotifier(cfg.RabbitMQURL, cfg.RabbitMQExchange, logger)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			closers = append(closers, func() {
+				if err := rabbitNotifier.Close(); err != nil {
+					logger.Warn("Failed to close RabbitMQ notifier", zap.Error(err))
+				}
+			})
+			notifier = rabbitNotifier
+		default:
+			closeAll()
+			return nil, nil, fmt.Errorf("unknown notifier: %s", name)
+		}
+
+		if metrics != nil {
+			notifier = NewInstrumentedNotifier(name, notifier, metrics)
+		}
+
+		backends = append(backends, Backend{Name: name, Notifier: notifier})
+	}
+
+	if len(backends) == 0 {
+		logger.Warn("No notifier backends configured, events are only logged")
+	}
+
+	return NewFanOutNotifier(logger, backends...), closeAll, nil
+}
+
+// NewAlertChannelsFromConfig creates the notifiers customers can be alerted on (email when SMTP is
+// configured, SMS when ALERT_SMS_ENABLED is true) and the ops notifier (nil without OPS_SNS_TOPIC_ARN)
+func NewAlertChannelsFromConfig(ctx context.Context, cfg *config.Config, logger *zap.Logger) (map[domain.NotificationChannel]driven.DirectNotifier, driven.Notifier, error) {
+	channels := make(map[domain.NotificationChannel]driven.DirectNotifier)
+
+	if cfg.SMTPHost != "" && cfg.EmailFrom != "" {
+		emailNotifier, err := NewEmailNotifier(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.EmailFrom,
+			Timeout:  time.Duration(cfg.SMTPTimeout) * time.Second,
+		}, cfg.EmailTemplateDir, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		channels[domain.ChannelEmail] = emailNotifier
+	}
+
+	if cfg.AlertSMSEnabled {
+		smsNotifier, err := NewSNSNotifier(ctx, cfg.AWSRegion, "", logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		channels[domain.ChannelSMS] = smsNotifier
+	}
+
+	var ops driven.Notifier
+	if cfg.OpsSNSTopicARN != "" {
+		opsNotifier, err := NewSNSNotifier(ctx, cfg.AWSRegion, cfg.OpsSNSTopicARN, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		ops = opsNotifier
+	}
+
+	if len(channels) == 0 {
+		logger.Warn("No customer alert channels configured, violations only go to ops")
+	}
+
+	return channels, ops, nil
+}
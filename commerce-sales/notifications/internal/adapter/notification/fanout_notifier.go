@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/port/driven"
+)
+
+// Backend is a named notifier a FanOutNotifier sends to
+type Backend struct {
+	Name     string
+	Notifier driven.Notifier
+}
+
+// FanOutNotifier sends every notification to several backends concurrently.
+// It fails when any backend fails, so the event is retried; backends that
+// already succeeded will then receive it again.
+type FanOutNotifier struct {
+	backends []Backend
+	logger   *zap.Logger
+}
+
+// Ensure FanOutNotifier implements the driven.Notifier interface
+var _ driven.Notifier = (*FanOutNotifier)(nil)
+
+// NewFanOutNotifier creates a new FanOutNotifier instance. Without backends notifications are only logged.
+func NewFanOutNotifier(logger *zap.Logger, backends ...Backend) *FanOutNotifier {
+	return &FanOutNotifier{
+		backends: backends,
+		logger:   logger,
+	}
+}
+
+// SendNotification sends the message to every backend and joins their errors
+func (n *FanOutNotifier) SendNotification(ctx context.Context, message map[string]any, eventType string) error {
+	if len(n.backends) == 0 {
+		n.logger.Debug("No notifier backends configured, dropping notification",
+			zap.String("eventType", eventType),
+		)
+		return nil
+	}
+
+	errs := make([]error, len(n.backends))
+
+	var wg sync.WaitGroup
+	for i, backend := range n.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := backend.Notifier.SendNotification(ctx, message, eventType); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", backend.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
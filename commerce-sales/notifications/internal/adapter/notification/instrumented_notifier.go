@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+
+	"notifications/internal/core/port/driven"
+)
+
+// InstrumentedNotifier wraps a notifier backend, counting its successful and failed notifications
+type InstrumentedNotifier struct {
+	backend  string
+	notifier driven.Notifier
+	metrics  driven.Metrics
+}
+
+// Ensure InstrumentedNotifier implements the driven.Notifier interface
+var _ driven.Notifier = (*InstrumentedNotifier)(nil)
+
+// NewInstrumentedNotifier creates a new InstrumentedNotifier instance for the named backend
+func NewInstrumentedNotifier(backend string, notifier driven.Notifier, metrics driven.Metrics) *InstrumentedNotifier {
+	return &InstrumentedNotifier{
+		backend:  backend,
+		notifier: notifier,
+		metrics:  metrics,
+	}
+}
+
+// SendNotification sends the message with the wrapped notifier and records the outcome
+func (n *InstrumentedNotifier) SendNotification(ctx context.Context, message map[string]any, eventType string) error {
+	err := n.notifier.SendNotification(ctx, message, eventType)
+	n.metrics.NotificationSent(n.backend, err)
+
+	return err
+}
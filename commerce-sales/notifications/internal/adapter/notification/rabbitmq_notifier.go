@@ -0,0 +1,253 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+// rabbitMQReconnectDelay is the wait between attempts to reconnect to RabbitMQ
+const rabbitMQReconnectDelay = 5 * time.Second
+
+// RabbitMQNotifier is an adapter that publishes events to a RabbitMQ topic exchange,
+// using the event type as routing key and waiting for the broker's publisher confirm.
+// It reconnects in the background when the connection or the channel is closed.
+type RabbitMQNotifier struct {
+	url            string
+	exchange       string
+	logger         *zap.Logger
+	reconnectDelay time.Duration
+	done           chan struct{}
+
+	mu      sync.Mutex
+	conn    *amqp.Connection // nil while reconnecting
+	channel *amqp.Channel    // nil while reconnecting
+	closed  bool
+}
+
+// Ensure RabbitMQNotifier implements the driven.Notifier interface
+var _ driven.Notifier = (*RabbitMQNotifier)(nil)
+
+// NewRabbitMQNotifier connects to RabbitMQ and declares the durable topic exchange events are published to
+func NewRabbitMQNotifier(url, exchange string, logger *zap.Logger) (*RabbitMQNotifier, error) {
+	n := &RabbitMQNotifier{
+		url:            url,
+		exchange:       exchange,
+		logger:         logger,
+		reconnectDelay: rabbitMQReconnectDelay,
+		done:           make(chan struct{}),
+	}
+
+	conn, channel, closed, err := n.connect()
+	if err != nil {
+		return nil, err
+	}
+	n.conn, n.channel = conn, channel
+	go n.watch(closed)
+
+	logger.Info("RabbitMQ notifier initialized",
+		zap.String("exchange", exchange),
+	)
+
+	return n, nil
+}
+
+// connect dials RabbitMQ, declares the exchange and opens a channel in confirm mode. The returned
+// channel receives once the connection or the channel is closed.
+func (n *RabbitMQNotifier) connect() (*amqp.Connection, *amqp.Channel, <-chan *amqp.Error, error) {
+	conn, err := amqp.Dial(n.url)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	if err := channel.ExchangeDeclare(
+		n.exchange, // name
+		"topic",    // kind
+		true,       // durable
+		false,      // auto-deleted
+		false,      // internal
+		false,      // no-wait
+		nil,        // args
+	); err != nil {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("failed to declare exchange %s: %w", n.exchange, err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, nil, nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	// Closing the connection closes its channels too, so the channel's notification covers both
+	closed := channel.NotifyClose(make(chan *amqp.Error, 1))
+
+	return conn, channel, closed, nil
+}
+
+// watch reconnects whenever the current connection or channel is closed by the broker or the
+// network, until the notifier is closed
+func (n *RabbitMQNotifier) watch(closed <-chan *amqp.Error) {
+	for {
+		var reason *amqp.Error
+		select {
+		case <-n.done:
+			return
+		case reason = <-closed:
+		}
+
+		n.mu.Lock()
+		if n.closed {
+			n.mu.Unlock()
+			return
+		}
+		stale := n.conn
+		n.conn, n.channel = nil, nil
+		n.mu.Unlock()
+
+		fields := []zap.Field{zap.String("exchange", n.exchange)}
+		if reason != nil {
+			fields = append(fields, zap.Error(reason))
+		}
+		n.logger.Warn("RabbitMQ connection lost, reconnecting", fields...)
+		// Only the channel may have been closed, drop the connection along with it
+		_ = stale.Close()
+
+		if closed = n.reconnect(); closed == nil {
+			return
+		}
+	}
+}
+
+// reconnect connects again every reconnectDelay until it succeeds or the notifier is closed, in
+// which case it returns nil
+func (n *RabbitMQNotifier) reconnect() <-chan *amqp.Error {
+	for {
+		select {
+		case <-n.done:
+			return nil
+		case <-time.After(n.reconnectDelay):
+		}
+
+		conn, channel, closed, err := n.connect()
+		if err != nil {
+			n.logger.Warn("Failed to reconnect to RabbitMQ",
+				zap.Error(err),
+				zap.Duration("retryIn", n.reconnectDelay),
+			)
+			continue
+		}
+
+		n.mu.Lock()
+		if n.closed {
+			n.mu.Unlock()
+			conn.Close()
+			return nil
+		}
+		n.conn, n.channel = conn, channel
+		n.mu.Unlock()
+
+		n.logger.Info("Reconnected to RabbitMQ",
+			zap.String("exchange", n.exchange),
+		)
+		return closed
+	}
+}
+
+// SendNotification publishes the message as a persistent JSON message and waits until the broker confirms it
+func (n *RabbitMQNotifier) SendNotification(ctx context.Context, message map[string]any, eventType string) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	// The idempotency key doubles as message ID, so consumers can deduplicate redeliveries
+	messageID, _ := domain.IdempotencyKeyFromContext(ctx)
+
+	// Publishes are serialized so delivery tags follow the publish order, but the confirm is
+	// awaited without the lock so concurrent notifications don't queue behind it
+	n.mu.Lock()
+	channel := n.channel
+	if channel == nil {
+		n.mu.Unlock()
+		return fmt.Errorf("failed to publish to RabbitMQ: reconnecting to %s", n.exchange)
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx,
+		n.exchange, // exchange
+		eventType,  // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			Type:         eventType,
+			MessageId:    messageID,
+			Body:         body,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+		})
+	n.mu.Unlock()
+	if err != nil {
+		n.logger.Error("Failed to publish message to RabbitMQ",
+			zap.Error(err),
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("failed to publish to RabbitMQ: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for RabbitMQ confirm: %w", err)
+	}
+	if !acked {
+		n.logger.Error("RabbitMQ rejected message",
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("RabbitMQ rejected message (delivery tag %d)", confirmation.DeliveryTag)
+	}
+
+	n.logger.Info("Successfully published to RabbitMQ",
+		zap.String("exchange", n.exchange),
+		zap.String("eventType", eventType),
+	)
+
+	return nil
+}
+
+// Close stops reconnecting and closes the channel and connection
+func (n *RabbitMQNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return nil
+	}
+	n.closed = true
+	close(n.done)
+
+	// Closed while reconnecting
+	if n.conn == nil {
+		return nil
+	}
+
+	if err := n.channel.Close(); err != nil {
+		return fmt.Errorf("failed to close channel: %w", err)
+	}
+	if err := n.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %w", err)
+	}
+	return nil
+}
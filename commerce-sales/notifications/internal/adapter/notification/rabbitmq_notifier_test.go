@@ -0,0 +1,551 @@
+package notification
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+)
+
+// AMQP 0-9-1 frame types and the class and method IDs the fake broker speaks
+const (
+	amqpFrameMethod    = 1
+	amqpFrameHeader    = 2
+	amqpFrameBody      = 3
+	amqpFrameHeartbeat = 8
+	amqpFrameEnd       = 0xCE
+
+	amqpClassConnection = 10
+	amqpClassChannel    = 20
+	amqpClassExchange   = 40
+	amqpClassBasic      = 60
+	amqpClassConfirm    = 85
+)
+
+// amqpMessage is a message published to the fake broker
+type amqpMessage struct {
+	exchange     string
+	routingKey   string
+	contentType  string
+	messageID    string
+	messageType  string
+	deliveryMode uint8
+	body         []byte
+}
+
+// fakeBroker is a local stand-in for RabbitMQ, implementing the part of AMQP 0-9-1 the notifier
+// uses: the connection handshake, channels, exchange declaration, publisher confirms and publish.
+// Publishes are acknowledged, or negatively acknowledged when nack is set, once hold is closed
+// when it is set.
+type fakeBroker struct {
+	listener net.Listener
+	nack     bool
+	hold     chan struct{}
+
+	mu        sync.Mutex
+	exchanges map[string]string // name to kind
+	messages  []amqpMessage
+	conns     map[net.Conn]struct{}
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	broker := &fakeBroker{
+		listener:  listener,
+		exchanges: make(map[string]string),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	go broker.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return broker
+}
+
+func (b *fakeBroker) url() string {
+	return "amqp://guest:guest@" + b.listener.Addr().String() + "/"
+}
+
+func (b *fakeBroker) published() []amqpMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]amqpMessage(nil), b.messages...)
+}
+
+// drop closes every client connection, as a broker restart would
+func (b *fakeBroker) drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.conns {
+		conn.Close()
+	}
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+// handle serves a client connection until it closes it
+func (b *fakeBroker) handle(conn net.Conn) {
+	b.mu.Lock()
+	b.conns[conn] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	protocolHeader := make([]byte, 8)
+	if _, err := io.ReadFull(reader, protocolHeader); err != nil || string(protocolHeader) != "AMQP\x00\x00\x09\x01" {
+		return
+	}
+
+	// Connection.Start: version 0-9, no server properties, PLAIN authentication
+	start := amqpArgs{}
+	start.octet(0).octet(9).table().longstr("PLAIN").longstr("en_US")
+	if err := writeMethod(conn, 0, amqpClassConnection, 10, start.Bytes()); err != nil {
+		return
+	}
+
+	var (
+		deliveryTag uint64
+		publish     *amqpMessage
+		bodySize    uint64
+	)
+
+	for {
+		frameType, channel, payload, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+
+		switch frameType {
+		case amqpFrameHeartbeat:
+			continue
+
+		case amqpFrameHeader:
+			if publish == nil {
+				return
+			}
+			bodySize = parseContentHeader(payload, publish)
+			if bodySize == 0 {
+				deliveryTag++
+				b.store(conn, channel, *publish, deliveryTag)
+				publish = nil
+			}
+			continue
+
+		case amqpFrameBody:
+			if publish == nil {
+				return
+			}
+			publish.body = append(publish.body, payload...)
+			if uint64(len(publish.body)) >= bodySize {
+				deliveryTag++
+				b.store(conn, channel, *publish, deliveryTag)
+				publish = nil
+			}
+			continue
+		}
+
+		args := amqpReader{data: payload}
+		classID, methodID := args.short(), args.short()
+
+		switch {
+		case classID == amqpClassConnection && methodID == 11: // Start-Ok
+			tune := amqpArgs{}
+			tune.short(2047).long(131072).short(0)
+			err = writeMethod(conn, 0, amqpClassConnection, 30, tune.Bytes())
+		case classID == amqpClassConnection && methodID == 31: // Tune-Ok
+		case classID == amqpClassConnection && methodID == 40: // Open
+			err = writeMethod(conn, 0, amqpClassConnection, 41, new(amqpArgs).shortstr("").Bytes())
+		case classID == amqpClassConnection && methodID == 50: // Close
+			_ = writeMethod(conn, 0, amqpClassConnection, 51, nil)
+			return
+		case classID == amqpClassChannel && methodID == 10: // Open
+			err = writeMethod(conn, channel, amqpClassChannel, 11, new(amqpArgs).longstr("").Bytes())
+		case classID == amqpClassChannel && methodID == 40: // Close
+			err = writeMethod(conn, channel, amqpClassChannel, 41, nil)
+		case classID == amqpClassExchange && methodID == 10: // Declare
+			args.short()
+			name, kind := args.shortstr(), args.shortstr()
+			b.mu.Lock()
+			b.exchanges[name] = kind
+			b.mu.Unlock()
+			err = writeMethod(conn, channel, amqpClassExchange, 11, nil)
+		case classID == amqpClassConfirm && methodID == 10: // Select
+			err = writeMethod(conn, channel, amqpClassConfirm, 11, nil)
+		case classID == amqpClassBasic && methodID == 40: // Publish
+			args.short()
+			publish = &amqpMessage{exchange: args.shortstr(), routingKey: args.shortstr()}
+		default:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// store records a published message and confirms it to the client
+func (b *fakeBroker) store(conn net.Conn, channel uint16, message amqpMessage, deliveryTag uint64) {
+	confirm := amqpArgs{}
+	confirm.longlong(deliveryTag).octet(0)
+
+	if b.nack {
+		_ = writeMethod(conn, channel, amqpClassBasic, 120, confirm.Bytes()) // Nack
+		return
+	}
+
+	b.mu.Lock()
+	b.messages = append(b.messages, message)
+	b.mu.Unlock()
+
+	if b.hold != nil {
+		go func() {
+			<-b.hold
+			_ = writeMethod(conn, channel, amqpClassBasic, 80, confirm.Bytes()) // Ack
+		}()
+		return
+	}
+	_ = writeMethod(conn, channel, amqpClassBasic, 80, confirm.Bytes()) // Ack
+}
+
+// parseContentHeader reads the properties of a content header frame into the message and
+// returns the body size
+func parseContentHeader(payload []byte, message *amqpMessage) uint64 {
+	header := amqpReader{data: payload}
+	header.short() // class
+	header.short() // weight
+	bodySize := header.longlong()
+	flags := header.short()
+
+	// Properties are present in flag order, from the most significant bit
+	if flags&0x8000 != 0 {
+		message.contentType = header.shortstr()
+	}
+	if flags&0x4000 != 0 {
+		header.shortstr() // content-encoding
+	}
+	if flags&0x2000 != 0 {
+		header.skip(int(header.long())) // headers
+	}
+	if flags&0x1000 != 0 {
+		message.deliveryMode = header.octet()
+	}
+	if flags&0x0800 != 0 {
+		header.octet() // priority
+	}
+	for _, flag := range []uint16{0x0400, 0x0200, 0x0100} { // correlation-id, reply-to, expiration
+		if flags&flag != 0 {
+			header.shortstr()
+		}
+	}
+	if flags&0x0080 != 0 {
+		message.messageID = header.shortstr()
+	}
+	if flags&0x0040 != 0 {
+		header.longlong() // timestamp
+	}
+	if flags&0x0020 != 0 {
+		message.messageType = header.shortstr()
+	}
+
+	return bodySize
+}
+
+// readFrame reads a frame and returns its type, channel and payload
+func readFrame(reader io.Reader) (byte, uint16, []byte, error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, 0, nil, err
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[3:]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, 0, nil, err
+	}
+
+	end := make([]byte, 1)
+	if _, err := io.ReadFull(reader, end); err != nil {
+		return 0, 0, nil, err
+	}
+	if end[0] != amqpFrameEnd {
+		return 0, 0, nil, errors.New("invalid frame end")
+	}
+
+	return header[0], binary.BigEndian.Uint16(header[1:3]), payload, nil
+}
+
+// writeMethod writes a method frame
+func writeMethod(w io.Writer, channel, classID, methodID uint16, args []byte) error {
+	payload := amqpArgs{}
+	payload.short(classID).short(methodID)
+	payload.Write(args)
+
+	frame := amqpArgs{}
+	frame.octet(amqpFrameMethod).short(channel).long(uint32(payload.Len()))
+	frame.Write(payload.Bytes())
+	frame.octet(amqpFrameEnd)
+
+	_, err := w.Write(frame.Bytes())
+	return err
+}
+
+// amqpArgs encodes AMQP method arguments
+type amqpArgs struct {
+	bytes.Buffer
+}
+
+func (a *amqpArgs) octet(v uint8) *amqpArgs {
+	a.WriteByte(v)
+	return a
+}
+
+func (a *amqpArgs) short(v uint16) *amqpArgs {
+	_ = binary.Write(&a.Buffer, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) long(v uint32) *amqpArgs {
+	_ = binary.Write(&a.Buffer, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) longlong(v uint64) *amqpArgs {
+	_ = binary.Write(&a.Buffer, binary.BigEndian, v)
+	return a
+}
+
+func (a *amqpArgs) shortstr(s string) *amqpArgs {
+	a.WriteByte(byte(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+func (a *amqpArgs) longstr(s string) *amqpArgs {
+	a.long(uint32(len(s)))
+	a.WriteString(s)
+	return a
+}
+
+// table writes an empty field table
+func (a *amqpArgs) table() *amqpArgs {
+	return a.long(0)
+}
+
+// amqpReader decodes AMQP method arguments
+type amqpReader struct {
+	data []byte
+}
+
+func (r *amqpReader) take(n int) []byte {
+	n = min(n, len(r.data))
+	taken := r.data[:n]
+	r.data = r.data[n:]
+	return taken
+}
+
+func (r *amqpReader) skip(n int) {
+	r.take(n)
+}
+
+func (r *amqpReader) octet() uint8 {
+	if b := r.take(1); len(b) == 1 {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *amqpReader) short() uint16 {
+	if b := r.take(2); len(b) == 2 {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *amqpReader) long() uint32 {
+	if b := r.take(4); len(b) == 4 {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *amqpReader) longlong() uint64 {
+	if b := r.take(8); len(b) == 8 {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *amqpReader) shortstr() string {
+	return string(r.take(int(r.octet())))
+}
+
+func TestRabbitMQNotifierSendNotification(t *testing.T) {
+	broker := newFakeBroker(t)
+
+	notifier, err := NewRabbitMQNotifier(broker.url(), "notifications", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRabbitMQNotifier() error = %v", err)
+	}
+	defer notifier.Close()
+
+	broker.mu.Lock()
+	kind := broker.exchanges["notifications"]
+	broker.mu.Unlock()
+	if kind != "topic" {
+		t.Errorf("exchange kind = %q, want topic", kind)
+	}
+
+	ctx := domain.WithIdempotencyKey(context.Background(), "0xabc:3")
+	for i := range 2 {
+		message := map[string]any{"contractId": fmt.Sprintf("contract-%d", i)}
+		if err := notifier.SendNotification(ctx, message, "ContractAdded"); err != nil {
+			t.Fatalf("SendNotification() error = %v", err)
+		}
+	}
+
+	messages := broker.published()
+	if len(messages) != 2 {
+		t.Fatalf("published %d messages, want 2", len(messages))
+	}
+
+	message := messages[0]
+	if message.exchange != "notifications" || message.routingKey != "ContractAdded" {
+		t.Errorf("published to %s/%s, want notifications/ContractAdded", message.exchange, message.routingKey)
+	}
+	if message.contentType != "application/json" {
+		t.Errorf("content type = %q, want application/json", message.contentType)
+	}
+	if message.messageType != "ContractAdded" {
+		t.Errorf("type = %q, want ContractAdded", message.messageType)
+	}
+	if message.messageID != "0xabc:3" {
+		t.Errorf("message ID = %q, want the idempotency key", message.messageID)
+	}
+	if message.deliveryMode != 2 {
+		t.Errorf("delivery mode = %d, want persistent (2)", message.deliveryMode)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(message.body, &body); err != nil || body["contractId"] != "contract-0" {
+		t.Errorf("body = %s, want the JSON message", message.body)
+	}
+}
+
+func TestRabbitMQNotifierNack(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.nack = true
+
+	notifier, err := NewRabbitMQNotifier(broker.url(), "notifications", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRabbitMQNotifier() error = %v", err)
+	}
+	defer notifier.Close()
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "ContractAdded"); err == nil {
+		t.Fatal("SendNotification() error = nil, want rejected message")
+	}
+}
+
+func TestRabbitMQNotifierPublishesWhileAwaitingConfirms(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.hold = make(chan struct{})
+
+	notifier, err := NewRabbitMQNotifier(broker.url(), "notifications", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRabbitMQNotifier() error = %v", err)
+	}
+	defer notifier.Close()
+
+	errs := make(chan error, 2)
+	for i := range 2 {
+		go func() {
+			errs <- notifier.SendNotification(context.Background(), map[string]any{"n": i}, "ContractAdded")
+		}()
+	}
+
+	// Both messages reach the broker before either is confirmed
+	deadline := time.Now().Add(5 * time.Second)
+	for len(broker.published()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("published %d messages before the first confirm, want 2", len(broker.published()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(broker.hold)
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Errorf("SendNotification() error = %v", err)
+		}
+	}
+}
+
+func TestRabbitMQNotifierReconnects(t *testing.T) {
+	broker := newFakeBroker(t)
+
+	notifier, err := NewRabbitMQNotifier(broker.url(), "notifications", zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewRabbitMQNotifier() error = %v", err)
+	}
+	notifier.reconnectDelay = 10 * time.Millisecond
+	defer notifier.Close()
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "ContractAdded"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	broker.drop()
+
+	// Notifications fail until the notifier is connected again
+	deadline := time.Now().Add(5 * time.Second)
+	for notifier.SendNotification(context.Background(), map[string]any{}, "ContractAdded") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("SendNotification() kept failing after the broker came back")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := len(broker.published()); got != 2 {
+		t.Errorf("published %d messages, want 2", got)
+	}
+}
+
+func TestRabbitMQNotifierUnreachableBroker(t *testing.T) {
+	broker := newFakeBroker(t)
+	url := broker.url()
+	broker.listener.Close()
+
+	if _, err := NewRabbitMQNotifier(url, "notifications", zap.NewNop()); err == nil {
+		t.Fatal("NewRabbitMQNotifier() error = nil, want connection error")
+	}
+}
@@ -2,14 +2,20 @@ package notification
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
 )
 
 // SNSNotifier is an adapter that publishes events to AWS SNS
@@ -19,6 +25,12 @@ type SNSNotifier struct {
 	logger   *zap.Logger
 }
 
+// Ensure SNSNotifier implements the driven.Notifier and driven.DirectNotifier interfaces
+var (
+	_ driven.Notifier       = (*SNSNotifier)(nil)
+	_ driven.DirectNotifier = (*SNSNotifier)(nil)
+)
+
 // NewSNSNotifier creates a new SNSNotifier instance
 func NewSNSNotifier(ctx context.Context, region, topicARN string, logger *zap.Logger) (*SNSNotifier, error) {
 	// Load AWS configuration
@@ -67,6 +79,18 @@ func (n *SNSNotifier) SendNotification(ctx context.Context, message map[string]a
 		},
 	}
 
+	// Pass the idempotency key downstream; FIFO topics deduplicate on it and the event type
+	if key, ok := domain.IdempotencyKeyFromContext(ctx); ok {
+		input.MessageAttributes["idempotencyKey"] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(key),
+		}
+		if strings.HasSuffix(n.topicARN, ".fifo") {
+			input.MessageDeduplicationId = aws.String(deduplicationID(key, eventType))
+			input.MessageGroupId = aws.String(messageGroupID(message, eventType))
+		}
+	}
+
 	output, err := n.client.Publish(ctx, input)
 	if err != nil {
 		n.logger.Error("Failed to publish message to SNS",
@@ -83,3 +107,52 @@ func (n *SNSNotifier) SendNotification(ctx context.Context, message map[string]a
 
 	return nil
 }
+
+// SendTo sends the message as an SMS to the given phone number (E.164 format). The text is the
+// message's "summary" field, or the message as JSON when it has none.
+func (n *SNSNotifier) SendTo(ctx context.Context, recipient string, message map[string]any, eventType string) error {
+	text, ok := message["summary"].(string)
+	if !ok {
+		messageJSON, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		text = string(messageJSON)
+	}
+
+	output, err := n.client.Publish(ctx, &sns.PublishInput{
+		Message:     aws.String(text),
+		PhoneNumber: aws.String(recipient),
+	})
+	if err != nil {
+		n.logger.Error("Failed to send SMS through SNS",
+			zap.Error(err),
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+
+	n.logger.Info("Successfully sent SMS through SNS",
+		zap.String("messageId", *output.MessageId),
+		zap.String("eventType", eventType),
+	)
+
+	return nil
+}
+
+// deduplicationID returns the FIFO deduplication ID of a notification. Notifications derived from
+// the same log, such as a status update and the violation sent to ops, share the idempotency key,
+// so the event type is part of the ID. It is hashed to fit the 128 characters SNS accepts.
+func deduplicationID(key, eventType string) string {
+	sum := sha256.Sum256([]byte(key + "/" + eventType))
+	return hex.EncodeToString(sum[:])
+}
+
+// messageGroupID returns the FIFO message group of a message: its contract, so the events of a
+// contract are delivered in order, or the event type when it has none
+func messageGroupID(message map[string]any, eventType string) string {
+	if contractID, ok := message["contractId"].(string); ok && contractID != "" {
+		return contractID
+	}
+	return eventType
+}
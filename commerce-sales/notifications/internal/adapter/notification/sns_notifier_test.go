@@ -0,0 +1,202 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+)
+
+// snsStub is a local stand-in for the SNS Query API, recording the Publish requests it receives
+type snsStub struct {
+	server *httptest.Server
+	status int
+
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func newSNSStub(t *testing.T) *snsStub {
+	t.Helper()
+
+	stub := &snsStub{status: http.StatusOK}
+	stub.server = httptest.NewServer(http.HandlerFunc(stub.handle))
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (s *snsStub) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.PostForm)
+	count := len(s.requests)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	if s.status != http.StatusOK {
+		w.WriteHeader(s.status)
+		fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>InvalidParameter</Code>`+
+			`<Message>rejected by stub</Message></Error><RequestId>stub</RequestId></ErrorResponse>`)
+		return
+	}
+
+	fmt.Fprintf(w, `<PublishResponse xmlns="http://sns.amazonaws.com/doc/2010-03-31/">`+
+		`<PublishResult><MessageId>message-%d</MessageId></PublishResult>`+
+		`<ResponseMetadata><RequestId>stub</RequestId></ResponseMetadata></PublishResponse>`, count)
+}
+
+func (s *snsStub) received() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]url.Values(nil), s.requests...)
+}
+
+// notifier returns an SNSNotifier publishing to the topic through the stub
+func (s *snsStub) notifier(topicARN string) *SNSNotifier {
+	client := sns.New(sns.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(s.server.URL),
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+		RetryMaxAttempts: 1,
+	})
+
+	return &SNSNotifier{
+		client:   client,
+		topicARN: topicARN,
+		logger:   zap.NewNop(),
+	}
+}
+
+// attribute returns the string value of a message attribute of a Publish request
+func attribute(request url.Values, name string) string {
+	for i := 1; request.Has(fmt.Sprintf("MessageAttributes.entry.%d.Name", i)); i++ {
+		if request.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i)) == name {
+			return request.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i))
+		}
+	}
+	return ""
+}
+
+func TestSNSNotifierSendNotification(t *testing.T) {
+	stub := newSNSStub(t)
+	notifier := stub.notifier("arn:aws:sns:us-east-1:000000000000:notifications")
+
+	ctx := domain.WithIdempotencyKey(context.Background(), "0xabc:3")
+	if err := notifier.SendNotification(ctx, map[string]any{"contractId": "contract-1"}, "ContractAdded"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	requests := stub.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+
+	request := requests[0]
+	if got := request.Get("Action"); got != "Publish" {
+		t.Errorf("Action = %q, want Publish", got)
+	}
+	if got := request.Get("TopicArn"); got != "arn:aws:sns:us-east-1:000000000000:notifications" {
+		t.Errorf("TopicArn = %q", got)
+	}
+	if got := request.Get("Message"); got != `{"contractId":"contract-1"}` {
+		t.Errorf("Message = %q", got)
+	}
+	if got := attribute(request, "eventType"); got != "ContractAdded" {
+		t.Errorf("eventType attribute = %q, want ContractAdded", got)
+	}
+	if got := attribute(request, "idempotencyKey"); got != "0xabc:3" {
+		t.Errorf("idempotencyKey attribute = %q, want 0xabc:3", got)
+	}
+
+	// Standard topics accept neither deduplication IDs nor message groups
+	if request.Has("MessageDeduplicationId") || request.Has("MessageGroupId") {
+		t.Errorf("FIFO parameters sent to a standard topic: %v", request)
+	}
+}
+
+func TestSNSNotifierFIFODeduplication(t *testing.T) {
+	stub := newSNSStub(t)
+	notifier := stub.notifier("arn:aws:sns:us-east-1:000000000000:notifications.fifo")
+
+	// Two notifications derived from the same log
+	ctx := domain.WithIdempotencyKey(context.Background(), "0xabc:3")
+	message := map[string]any{"contractId": "contract-1"}
+	for _, eventType := range []string{"SLAStatusUpdated", "SLAViolated", "SLAViolated"} {
+		if err := notifier.SendNotification(ctx, message, eventType); err != nil {
+			t.Fatalf("SendNotification(%s) error = %v", eventType, err)
+		}
+	}
+
+	requests := stub.received()
+	if len(requests) != 3 {
+		t.Fatalf("received %d requests, want 3", len(requests))
+	}
+
+	for _, request := range requests {
+		if got := request.Get("MessageGroupId"); got != "contract-1" {
+			t.Errorf("MessageGroupId = %q, want contract-1", got)
+		}
+		if got := len(request.Get("MessageDeduplicationId")); got == 0 || got > 128 {
+			t.Errorf("MessageDeduplicationId length = %d, want 1 to 128", got)
+		}
+	}
+
+	statusID := requests[0].Get("MessageDeduplicationId")
+	violationID := requests[1].Get("MessageDeduplicationId")
+	if statusID == violationID {
+		t.Error("notifications of different types share a deduplication ID")
+	}
+	if redelivered := requests[2].Get("MessageDeduplicationId"); redelivered != violationID {
+		t.Errorf("redelivered notification deduplication ID = %q, want %q", redelivered, violationID)
+	}
+}
+
+func TestSNSNotifierSendTo(t *testing.T) {
+	stub := newSNSStub(t)
+	notifier := stub.notifier("arn:aws:sns:us-east-1:000000000000:notifications")
+
+	message := map[string]any{"summary": "SLA sla-1 of contract contract-1 was violated."}
+	if err := notifier.SendTo(context.Background(), "+15550123", message, "SLAViolationAlert"); err != nil {
+		t.Fatalf("SendTo() error = %v", err)
+	}
+
+	requests := stub.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	if got := requests[0].Get("PhoneNumber"); got != "+15550123" {
+		t.Errorf("PhoneNumber = %q, want +15550123", got)
+	}
+	if got := requests[0].Get("Message"); got != message["summary"] {
+		t.Errorf("Message = %q, want the summary", got)
+	}
+	if requests[0].Has("TopicArn") {
+		t.Error("SMS published to the topic")
+	}
+}
+
+func TestSNSNotifierRejected(t *testing.T) {
+	stub := newSNSStub(t)
+	stub.status = http.StatusBadRequest
+	notifier := stub.notifier("arn:aws:sns:us-east-1:000000000000:notifications")
+
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "ContractAdded"); err == nil {
+		t.Fatal("SendNotification() error = nil, want rejected publish")
+	}
+}
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+)
+
+const (
+	// WebhookEventTypeHeader carries the event type of the notification
+	WebhookEventTypeHeader = "X-Event-Type"
+	// WebhookTimestampHeader carries the Unix time (seconds) the notification was signed at
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookIdempotencyKeyHeader carries the idempotency key of the event, identical across redeliveries
+	WebhookIdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// WebhookNotifier is an adapter that posts events as HMAC-signed JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+	logger *zap.Logger
+}
+
+// Ensure WebhookNotifier implements the driven.Notifier interface
+var _ driven.Notifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier creates a new WebhookNotifier instance. Payloads are only signed when a secret is set.
+func NewWebhookNotifier(url, secret string, timeout time.Duration, logger *zap.Logger) *WebhookNotifier {
+	logger.Info("Webhook notifier initialized",
+		zap.String("url", url),
+		zap.Bool("signed", secret != ""),
+	)
+
+	return &WebhookNotifier{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger,
+	}
+}
+
+// SendNotification posts the message as a JSON body. Any non-2xx response is an error.
+func (n *WebhookNotifier) SendNotification(ctx context.Context, message map[string]any, eventType string) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventTypeHeader, eventType)
+	if key, ok := domain.IdempotencyKeyFromContext(ctx); ok {
+		req.Header.Set(WebhookIdempotencyKeyHeader, key)
+	}
+
+	if len(n.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+n.sign(timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		n.logger.Error("Failed to call webhook",
+			zap.Error(err),
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		n.logger.Error("Webhook rejected notification",
+			zap.Int("status", resp.StatusCode),
+			zap.String("eventType", eventType),
+		)
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	n.logger.Info("Successfully posted to webhook",
+		zap.Int("status", resp.StatusCode),
+		zap.String("eventType", eventType),
+	)
+
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of "<timestamp>.<body>"
+func (n *WebhookNotifier) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, n.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package notification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"notifications/internal/core/domain"
+)
+
+func TestWebhookNotifierSendNotification(t *testing.T) {
+	const secret = "s3cr3t"
+
+	var (
+		header http.Header
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, secret, 5*time.Second, zap.NewNop())
+	ctx := domain.WithIdempotencyKey(context.Background(), "0xabc:3")
+
+	if err := notifier.SendNotification(ctx, map[string]any{"contractId": "contract-1"}, "ContractAdded"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	var message map[string]any
+	if err := json.Unmarshal(body, &message); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if message["contractId"] != "contract-1" {
+		t.Errorf("body contractId = %v, want contract-1", message["contractId"])
+	}
+
+	if got := header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := header.Get(WebhookEventTypeHeader); got != "ContractAdded" {
+		t.Errorf("%s = %q, want ContractAdded", WebhookEventTypeHeader, got)
+	}
+	if got := header.Get(WebhookIdempotencyKeyHeader); got != "0xabc:3" {
+		t.Errorf("%s = %q, want 0xabc:3", WebhookIdempotencyKeyHeader, got)
+	}
+
+	// The receiver recomputes the signature from the timestamp and the raw body
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header.Get(WebhookTimestampHeader) + "."))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := header.Get(WebhookSignatureHeader); got != want {
+		t.Errorf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+}
+
+func TestWebhookNotifierUnsigned(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "", 5*time.Second, zap.NewNop())
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "SLAAdded"); err != nil {
+		t.Fatalf("SendNotification() error = %v", err)
+	}
+
+	for _, name := range []string{WebhookSignatureHeader, WebhookTimestampHeader, WebhookIdempotencyKeyHeader} {
+		if got := header.Get(name); got != "" {
+			t.Errorf("%s = %q, want none", name, got)
+		}
+	}
+}
+
+func TestWebhookNotifierRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "no content", status: http.StatusNoContent},
+		{name: "redirect", status: http.StatusNotModified, wantErr: true},
+		{name: "client error", status: http.StatusBadRequest, wantErr: true},
+		{name: "server error", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier := NewWebhookNotifier(server.URL, "", 5*time.Second, zap.NewNop())
+			err := notifier.SendNotification(context.Background(), map[string]any{}, "SLAAdded")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendNotification() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifierTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	notifier := NewWebhookNotifier(server.URL, "", 50*time.Millisecond, zap.NewNop())
+	if err := notifier.SendNotification(context.Background(), map[string]any{}, "SLAAdded"); err == nil {
+		t.Fatal("SendNotification() error = nil, want timeout")
+	}
+}
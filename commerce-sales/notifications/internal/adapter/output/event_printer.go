@@ -0,0 +1,39 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"notifications/internal/core/port/driven"
+	"sync"
+)
+
+// EventPrinter is an event processor that writes every domain event it receives as a
+// JSON line, used for dry runs instead of sending notifications
+type EventPrinter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// Ensure EventPrinter implements the driven.EventProcessor interface
+var _ driven.EventProcessor = (*EventPrinter)(nil)
+
+// NewEventPrinter creates a new EventPrinter writing to w
+func NewEventPrinter(w io.Writer) *EventPrinter {
+	return &EventPrinter{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Process writes the event as a single JSON line
+func (p *EventPrinter) Process(_ context.Context, event interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.encoder.Encode(event); err != nil {
+		return fmt.Errorf("failed to print event: %w", err)
+	}
+
+	return nil
+}
@@ -24,6 +24,11 @@ func NewContractEventProcessor(logger *zap.Logger, notifier driven.Notifier) *Co
 
 // Process handles contract-related events
 func (p *ContractEventProcessor) Process(ctx context.Context, event interface{}) error {
+	// Handle retracted ContractAdded events
+	if retracted, ok := event.(*domain.EventRetractedEvent); ok {
+		return p.processRetracted(ctx, retracted)
+	}
+
 	// Type asserts to ContractAddedEvent
 	contractEvent, ok := event.(*domain.ContractAddedEvent)
 	if !ok {
@@ -60,9 +65,10 @@ func (p *ContractEventProcessor) Process(ctx context.Context, event interface{})
 
 	// Send notification
 	message := map[string]interface{}{
-		"contractId": contractEvent.ContractID,
-		"customerId": contractEvent.CustomerID,
-		"eventType":  string(domain.EventTypeContractAdded),
+		"contractId":     contractEvent.ContractID,
+		"contractIdHash": contractEvent.ContractIDHash,
+		"customerId":     contractEvent.CustomerID,
+		"eventType":      string(domain.EventTypeContractAdded),
 	}
 
 	if err := p.notifier.SendNotification(ctx, message, string(domain.EventTypeContractAdded)); err != nil {
@@ -79,3 +85,37 @@ func (p *ContractEventProcessor) Process(ctx context.Context, event interface{})
 
 	return nil
 }
+
+// processRetracted announces that a ContractAdded event was removed by a chain reorganisation
+func (p *ContractEventProcessor) processRetracted(ctx context.Context, event *domain.EventRetractedEvent) error {
+	contractEvent, ok := event.Retracted.(*domain.ContractAddedEvent)
+	if !ok {
+		// Only retractions of ContractAdded events are handled here
+		return nil
+	}
+
+	p.logger.Warn("ContractAdded event retracted by chain reorganisation",
+		zap.String("contractId", contractEvent.ContractID),
+		zap.Uint64("blockNumber", event.BlockNumber),
+		zap.String("txHash", event.TxHash),
+	)
+
+	message := map[string]interface{}{
+		"contractId":         contractEvent.ContractID,
+		"contractIdHash":     contractEvent.ContractIDHash,
+		"customerId":         contractEvent.CustomerID,
+		"retractedEventType": string(domain.EventTypeContractAdded),
+		"txHash":             event.TxHash,
+		"eventType":          string(domain.EventTypeEventRetracted),
+	}
+
+	if err := p.notifier.SendNotification(ctx, message, string(domain.EventTypeEventRetracted)); err != nil {
+		p.logger.Error("Failed to send notification for retracted ContractAdded event",
+			zap.Error(err),
+			zap.String("contractId", contractEvent.ContractID),
+		)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+
+	"go.uber.org/zap"
+)
+
+// CustomerAlerter alerts the customer a contract belongs to about SLA violations. The customer is
+// found through the on-chain contract and the customer directory, and reached on their preferred
+// channel, or the next one when that fails; when no channel works, the alert goes to the ops
+// notifier instead.
+type CustomerAlerter struct {
+	logger    *zap.Logger
+	contracts driven.ContractRegistry
+	customers driven.CustomerDirectory
+	channels  map[domain.NotificationChannel]driven.DirectNotifier
+	ops       driven.Notifier
+}
+
+// NewCustomerAlerter creates a new CustomerAlerter instance. channels holds the notifier of every
+// available channel; ops is optional.
+func NewCustomerAlerter(
+	logger *zap.Logger,
+	contracts driven.ContractRegistry,
+	customers driven.CustomerDirectory,
+	channels map[domain.NotificationChannel]driven.DirectNotifier,
+	ops driven.Notifier,
+) *CustomerAlerter {
+	return &CustomerAlerter{
+		logger:    logger,
+		contracts: contracts,
+		customers: customers,
+		channels:  channels,
+		ops:       ops,
+	}
+}
+
+// AlertSLAViolation sends the violation to the contract's customer, falling back to the ops notifier.
+// Without an ops notifier the customer alert error is returned, so the event is retried.
+func (a *CustomerAlerter) AlertSLAViolation(ctx context.Context, event *domain.SLAStatusUpdatedEvent) error {
+	message := map[string]interface{}{
+		"contractId": event.ContractID,
+		"slaId":      event.SLAID,
+		"status":     event.NewStatus,
+		"statusName": domain.SLAStatus(event.NewStatus).String(),
+		"summary":    fmt.Sprintf("SLA %s of contract %s was violated.", event.SLAID, event.ContractID),
+		"eventType":  string(domain.SLAViolationAlert),
+	}
+
+	err := a.alertCustomer(ctx, event.ContractID, message)
+	if err == nil {
+		return nil
+	}
+
+	a.logger.Warn("Failed to alert customer about SLA violation",
+		zap.Error(err),
+		zap.String("contractId", event.ContractID),
+		zap.String("slaId", event.SLAID),
+	)
+
+	if a.ops == nil {
+		return err
+	}
+
+	message["eventType"] = string(domain.SLAViolated)
+	message["customerAlertError"] = err.Error()
+
+	if err := a.ops.SendNotification(ctx, message, string(domain.SLAViolated)); err != nil {
+		a.logger.Error("Failed to send SLA violation to ops",
+			zap.Error(err),
+			zap.String("contractId", event.ContractID),
+			zap.String("slaId", event.SLAID),
+		)
+		return err
+	}
+
+	a.logger.Info("SLA violation sent to ops",
+		zap.String("contractId", event.ContractID),
+		zap.String("slaId", event.SLAID),
+	)
+
+	return nil
+}
+
+// alertCustomer looks up the contract's customer and sends the message on the first reachable
+// channel. A channel that fails is skipped for the next one, and the errors of every channel are
+// returned when none worked.
+func (a *CustomerAlerter) alertCustomer(ctx context.Context, contractID string, message map[string]interface{}) error {
+	customerID, err := a.contracts.CustomerID(ctx, contractID)
+	if err != nil {
+		return fmt.Errorf("failed to get customer of contract: %w", err)
+	}
+	message["customerId"] = customerID
+
+	customer, err := a.customers.FindCustomer(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to find customer: %w", err)
+	}
+	message["customerName"] = customer.Name
+
+	var errs []error
+	for _, channel := range customer.Channels() {
+		recipient := customer.Recipient(channel)
+		notifier, ok := a.channels[channel]
+		if recipient == "" || !ok {
+			continue
+		}
+
+		if err := notifier.SendTo(ctx, recipient, message, string(domain.SLAViolationAlert)); err != nil {
+			a.logger.Warn("Failed to alert customer on channel, trying the next one",
+				zap.Error(err),
+				zap.String("customerId", customerID),
+				zap.String("channel", string(channel)),
+			)
+			errs = append(errs, fmt.Errorf("failed to send %s alert: %w", channel, err))
+			continue
+		}
+
+		a.logger.Info("Customer alerted about SLA violation",
+			zap.String("customerId", customerID),
+			zap.String("channel", string(channel)),
+			zap.String("contractId", contractID),
+		)
+		return nil
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return fmt.Errorf("customer %s has no reachable channel", customerID)
+}
@@ -0,0 +1,185 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+
+	"go.uber.org/zap"
+)
+
+// stubRegistry returns the same customer for every contract
+type stubRegistry struct {
+	customerID string
+	err        error
+}
+
+func (r stubRegistry) CustomerID(context.Context, string) (string, error) {
+	return r.customerID, r.err
+}
+
+// stubDirectory returns the same customer for every ID
+type stubDirectory struct {
+	customer *domain.Customer
+	err      error
+}
+
+func (d stubDirectory) FindCustomer(context.Context, string) (*domain.Customer, error) {
+	return d.customer, d.err
+}
+
+// stubNotifier records the recipients it was called with and fails with err
+type stubNotifier struct {
+	err        error
+	recipients []string
+	eventTypes []string
+}
+
+func (n *stubNotifier) SendTo(_ context.Context, recipient string, _ map[string]interface{}, eventType string) error {
+	n.recipients = append(n.recipients, recipient)
+	n.eventTypes = append(n.eventTypes, eventType)
+	return n.err
+}
+
+func (n *stubNotifier) SendNotification(_ context.Context, _ map[string]interface{}, eventType string) error {
+	n.eventTypes = append(n.eventTypes, eventType)
+	return n.err
+}
+
+func TestCustomerAlerterAlertSLAViolation(t *testing.T) {
+	errSend := errors.New("send failed")
+	customer := &domain.Customer{
+		ID:               "customer-1",
+		Name:             "Hospital San Rafael",
+		Email:            "procurement@sanrafael.com",
+		Phone:            "+15550123",
+		PreferredChannel: domain.ChannelSMS,
+	}
+
+	tests := []struct {
+		name       string
+		registry   stubRegistry
+		directory  stubDirectory
+		smsErr     error
+		emailErr   error
+		withOps    bool
+		opsErr     error
+		wantErr    bool
+		wantSMS    []string
+		wantEmail  []string
+		wantOpsHit bool
+	}{
+		{
+			name:      "preferred channel delivers",
+			registry:  stubRegistry{customerID: customer.ID},
+			directory: stubDirectory{customer: customer},
+			withOps:   true,
+			wantSMS:   []string{customer.Phone},
+		},
+		{
+			name:      "failed preferred channel falls back to the next one",
+			registry:  stubRegistry{customerID: customer.ID},
+			directory: stubDirectory{customer: customer},
+			smsErr:    errSend,
+			withOps:   true,
+			wantSMS:   []string{customer.Phone},
+			wantEmail: []string{customer.Email},
+		},
+		{
+			name:       "every channel failing goes to ops",
+			registry:   stubRegistry{customerID: customer.ID},
+			directory:  stubDirectory{customer: customer},
+			smsErr:     errSend,
+			emailErr:   errSend,
+			withOps:    true,
+			wantSMS:    []string{customer.Phone},
+			wantEmail:  []string{customer.Email},
+			wantOpsHit: true,
+		},
+		{
+			name:      "every channel failing without ops returns the error",
+			registry:  stubRegistry{customerID: customer.ID},
+			directory: stubDirectory{customer: customer},
+			smsErr:    errSend,
+			emailErr:  errSend,
+			wantErr:   true,
+			wantSMS:   []string{customer.Phone},
+			wantEmail: []string{customer.Email},
+		},
+		{
+			name:      "unknown customer without ops returns the error",
+			registry:  stubRegistry{customerID: customer.ID},
+			directory: stubDirectory{err: driven.ErrCustomerNotFound},
+			wantErr:   true,
+		},
+		{
+			name:       "ops failure is returned",
+			registry:   stubRegistry{err: errors.New("node unavailable")},
+			withOps:    true,
+			opsErr:     errSend,
+			wantErr:    true,
+			wantOpsHit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sms := &stubNotifier{err: tt.smsErr}
+			email := &stubNotifier{err: tt.emailErr}
+			channels := map[domain.NotificationChannel]driven.DirectNotifier{
+				domain.ChannelSMS:   sms,
+				domain.ChannelEmail: email,
+			}
+
+			var ops driven.Notifier
+			opsNotifier := &stubNotifier{err: tt.opsErr}
+			if tt.withOps {
+				ops = opsNotifier
+			}
+
+			alerter := NewCustomerAlerter(zap.NewNop(), tt.registry, tt.directory, channels, ops)
+			err := alerter.AlertSLAViolation(context.Background(), &domain.SLAStatusUpdatedEvent{
+				ContractID: "contract-1",
+				SLAID:      "sla-1",
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AlertSLAViolation() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(sms.recipients, tt.wantSMS) {
+				t.Errorf("sms recipients = %v, want %v", sms.recipients, tt.wantSMS)
+			}
+			if !slices.Equal(email.recipients, tt.wantEmail) {
+				t.Errorf("email recipients = %v, want %v", email.recipients, tt.wantEmail)
+			}
+			if opsHit := len(opsNotifier.eventTypes) > 0; opsHit != tt.wantOpsHit {
+				t.Errorf("ops notified = %v, want %v", opsHit, tt.wantOpsHit)
+			}
+		})
+	}
+}
+
+func TestCustomerAlerterJoinsChannelErrors(t *testing.T) {
+	smsErr := errors.New("sms failed")
+	emailErr := errors.New("email failed")
+	customer := &domain.Customer{ID: "customer-1", Email: "a@example.com", Phone: "+15550123"}
+
+	alerter := NewCustomerAlerter(zap.NewNop(),
+		stubRegistry{customerID: customer.ID},
+		stubDirectory{customer: customer},
+		map[domain.NotificationChannel]driven.DirectNotifier{
+			domain.ChannelSMS:   &stubNotifier{err: smsErr},
+			domain.ChannelEmail: &stubNotifier{err: emailErr},
+		},
+		nil,
+	)
+
+	err := alerter.AlertSLAViolation(context.Background(), &domain.SLAStatusUpdatedEvent{ContractID: "contract-1"})
+	if !errors.Is(err, smsErr) || !errors.Is(err, emailErr) {
+		t.Fatalf("AlertSLAViolation() error = %v, want both channel errors", err)
+	}
+}
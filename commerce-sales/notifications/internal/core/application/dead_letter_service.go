@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeadLetterService lists dead-lettered events and replays them through the processor that failed them
+type DeadLetterService struct {
+	store      driven.DeadLetterStore
+	processors map[string]driven.EventProcessor
+	logger     *zap.Logger
+}
+
+// NewDeadLetterService creates a new DeadLetterService instance. Processors are matched to
+// dead letters by ProcessorName, so they must not be wrapped in a RetryingProcessor.
+func NewDeadLetterService(logger *zap.Logger, store driven.DeadLetterStore, processors ...driven.EventProcessor) *DeadLetterService {
+	byName := make(map[string]driven.EventProcessor, len(processors))
+	for _, processor := range processors {
+		byName[ProcessorName(processor)] = processor
+	}
+
+	return &DeadLetterService{
+		store:      store,
+		processors: byName,
+		logger:     logger,
+	}
+}
+
+// List returns all dead letters, oldest first
+func (s *DeadLetterService) List(ctx context.Context) ([]domain.DeadLetter, error) {
+	return s.store.List(ctx)
+}
+
+// Replay processes a dead letter again. It is deleted on success; on failure its error and
+// attempt count are updated and the error is returned.
+func (s *DeadLetterService) Replay(ctx context.Context, id string) error {
+	deadLetter, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	processor, ok := s.processors[deadLetter.Processor]
+	if !ok {
+		return fmt.Errorf("no processor %s to replay dead letter %s", deadLetter.Processor, id)
+	}
+
+	event, err := domain.DecodeEvent(deadLetter.Event)
+	if err != nil {
+		return fmt.Errorf("failed to restore dead letter %s: %w", id, err)
+	}
+
+	if deadLetter.IdempotencyKey != "" {
+		ctx = domain.WithIdempotencyKey(ctx, deadLetter.IdempotencyKey)
+	}
+
+	if err := processor.Process(ctx, event); err != nil {
+		deadLetter.Attempts++
+		deadLetter.Error = err.Error()
+		deadLetter.FailedAt = time.Now().UTC()
+
+		if saveErr := s.store.Save(ctx, *deadLetter); saveErr != nil {
+			s.logger.Error("Failed to update dead letter", zap.Error(saveErr), zap.String("deadLetterId", id))
+		}
+		return fmt.Errorf("replay of dead letter %s failed: %w", id, err)
+	}
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("dead letter %s replayed but not deleted: %w", id, err)
+	}
+
+	s.logger.Info("Dead letter replayed",
+		zap.String("deadLetterId", id),
+		zap.String("processor", deadLetter.Processor),
+		zap.String("eventType", string(deadLetter.EventType)),
+	)
+
+	return nil
+}
+
+// ReplayAll replays every dead letter and returns the number that succeeded.
+// Failures are logged and don't stop the remaining replays.
+func (s *DeadLetterService) ReplayAll(ctx context.Context) (int, error) {
+	deadLetters, err := s.store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, deadLetter := range deadLetters {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+
+		if err := s.Replay(ctx, deadLetter.ID); err != nil {
+			s.logger.Error("Failed to replay dead letter", zap.Error(err), zap.String("deadLetterId", deadLetter.ID))
+			continue
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+	"notifications/internal/core/port/driven"
+	"time"
+)
+
+// InstrumentedProcessor wraps an EventProcessor, recording the latency and failures of every call
+type InstrumentedProcessor struct {
+	name      string
+	processor driven.EventProcessor
+	metrics   driven.Metrics
+}
+
+// NewInstrumentedProcessor creates a new InstrumentedProcessor instance. It keeps the name of the
+// wrapped processor, so it can be wrapped in a RetryingProcessor without changing dead letters.
+func NewInstrumentedProcessor(processor driven.EventProcessor, metrics driven.Metrics) *InstrumentedProcessor {
+	return &InstrumentedProcessor{
+		name:      ProcessorName(processor),
+		processor: processor,
+		metrics:   metrics,
+	}
+}
+
+// Name returns the name of the wrapped processor
+func (p *InstrumentedProcessor) Name() string {
+	return p.name
+}
+
+// Process handles the event with the wrapped processor and records the outcome
+func (p *InstrumentedProcessor) Process(ctx context.Context, event interface{}) error {
+	start := time.Now()
+	err := p.processor.Process(ctx, event)
+	p.metrics.ProcessorCompleted(p.name, time.Since(start), err)
+
+	return err
+}
@@ -0,0 +1,147 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RetryPolicy controls how often and how long a failed event is retried
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts, including the first one
+	InitialBackoff time.Duration // wait before the first retry
+	MaxBackoff     time.Duration // upper bound of the wait between retries
+}
+
+// backoff returns the wait before the given retry (1 = first retry): the initial backoff doubled for
+// every previous retry and capped at MaxBackoff, of which a random half is jitter
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	wait = min(wait, p.MaxBackoff)
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+// RetryingProcessor wraps an EventProcessor, retrying failed events with exponential backoff.
+// Events that still fail after the last attempt are saved to the dead-letter store.
+type RetryingProcessor struct {
+	name        string
+	processor   driven.EventProcessor
+	policy      RetryPolicy
+	deadLetters driven.DeadLetterStore
+	logger      *zap.Logger
+}
+
+// NewRetryingProcessor creates a new RetryingProcessor instance. The dead-letter store is optional;
+// without it the last error is returned, so the listener retries the event later.
+func NewRetryingProcessor(
+	logger *zap.Logger,
+	processor driven.EventProcessor,
+	policy RetryPolicy,
+	deadLetters driven.DeadLetterStore,
+) *RetryingProcessor {
+	return &RetryingProcessor{
+		name:        ProcessorName(processor),
+		processor:   processor,
+		policy:      policy,
+		deadLetters: deadLetters,
+		logger:      logger,
+	}
+}
+
+// ProcessorName identifies a processor in dead letters and metrics: its type, or the
+// name a wrapping processor reports for the processor it wraps
+func ProcessorName(processor driven.EventProcessor) string {
+	if named, ok := processor.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", processor)
+}
+
+// Name returns the name of the wrapped processor
+func (p *RetryingProcessor) Name() string {
+	return p.name
+}
+
+// Process handles the event with the wrapped processor, retrying and dead-lettering it on failure
+func (p *RetryingProcessor) Process(ctx context.Context, event interface{}) error {
+	var err error
+	attempts := 0
+
+	for attempts < max(p.policy.MaxAttempts, 1) {
+		if attempts > 0 {
+			wait := p.policy.backoff(attempts)
+			p.logger.Warn("Retrying failed event",
+				zap.Error(err),
+				zap.String("processor", p.name),
+				zap.Int("attempt", attempts+1),
+				zap.Duration("backoff", wait),
+			)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		attempts++
+		if err = p.processor.Process(ctx, event); err == nil {
+			return nil
+		}
+	}
+
+	if p.deadLetters == nil {
+		return err
+	}
+
+	return p.deadLetter(ctx, event, err, attempts)
+}
+
+// deadLetter saves an event that exhausted its retries
+func (p *RetryingProcessor) deadLetter(ctx context.Context, event interface{}, cause error, attempts int) error {
+	eventType, data, err := domain.EncodeEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to dead-letter event: %w (processing error: %v)", err, cause)
+	}
+
+	key, _ := domain.IdempotencyKeyFromContext(ctx)
+
+	deadLetter := domain.DeadLetter{
+		ID:             uuid.NewString(),
+		Processor:      p.name,
+		EventType:      eventType,
+		Event:          data,
+		IdempotencyKey: key,
+		Error:          cause.Error(),
+		Attempts:       attempts,
+		FailedAt:       time.Now().UTC(),
+	}
+
+	if err := p.deadLetters.Save(ctx, deadLetter); err != nil {
+		return fmt.Errorf("failed to dead-letter event: %w (processing error: %v)", err, cause)
+	}
+
+	p.logger.Error("Event dead-lettered after exhausting retries",
+		zap.Error(cause),
+		zap.String("deadLetterId", deadLetter.ID),
+		zap.String("processor", p.name),
+		zap.String("eventType", string(eventType)),
+		zap.Int("attempts", attempts),
+	)
+
+	return nil
+}
@@ -8,7 +8,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// SLAEventProcessor is responsible for processing SLA-related events
+// SLAEventProcessor is responsible for processing SLA-related events. Customers are alerted
+// about violations by the SLAViolationAlertProcessor.
 type SLAEventProcessor struct {
 	logger   *zap.Logger
 	notifier driven.Notifier
@@ -34,6 +35,11 @@ func (p *SLAEventProcessor) Process(ctx context.Context, event interface{}) erro
 		return p.processSLAStatusUpdated(ctx, slaStatusEvent)
 	}
 
+	// Handle retracted SLA events
+	if retracted, ok := event.(*domain.EventRetractedEvent); ok {
+		return p.processRetracted(ctx, retracted)
+	}
+
 	// This processor only handles SLA events, ignore others
 	return nil
 }
@@ -70,9 +76,10 @@ func (p *SLAEventProcessor) processSLAAdded(ctx context.Context, event *domain.S
 
 	// Send notification
 	message := map[string]interface{}{
-		"contractId": event.ContractID,
-		"slaId":      event.SLAID,
-		"eventType":  string(domain.EventTypeSLAAdded),
+		"contractId":     event.ContractID,
+		"contractIdHash": event.ContractIDHash,
+		"slaId":          event.SLAID,
+		"eventType":      string(domain.EventTypeSLAAdded),
 	}
 
 	if err := p.notifier.SendNotification(ctx, message, string(domain.EventTypeSLAAdded)); err != nil {
@@ -114,11 +121,13 @@ func (p *SLAEventProcessor) processSLAStatusUpdated(ctx context.Context, event *
 		)
 
 		message := map[string]interface{}{
-			"contractId": event.ContractID,
-			"slaId":      event.SLAID,
-			"status":     event.NewStatus,
-			"statusName": status.String(),
-			"eventType":  string(domain.SLAViolated),
+			"contractId":     event.ContractID,
+			"contractIdHash": event.ContractIDHash,
+			"slaId":          event.SLAID,
+			"slaIdHash":      event.SLAIDHash,
+			"status":         event.NewStatus,
+			"statusName":     status.String(),
+			"eventType":      string(domain.SLAViolated),
 		}
 
 		if err := p.notifier.SendNotification(ctx, message, string(domain.SLAViolated)); err != nil {
@@ -154,11 +163,13 @@ func (p *SLAEventProcessor) processSLAStatusUpdated(ctx context.Context, event *
 
 	// Send notification
 	message := map[string]interface{}{
-		"contractId": event.ContractID,
-		"slaId":      event.SLAID,
-		"status":     event.NewStatus,
-		"statusName": status.String(),
-		"eventType":  string(domain.EventTypeSLAStatusUpdated),
+		"contractId":     event.ContractID,
+		"contractIdHash": event.ContractIDHash,
+		"slaId":          event.SLAID,
+		"slaIdHash":      event.SLAIDHash,
+		"status":         event.NewStatus,
+		"statusName":     status.String(),
+		"eventType":      string(domain.EventTypeSLAStatusUpdated),
 	}
 
 	if err := p.notifier.SendNotification(ctx, message, string(domain.EventTypeSLAStatusUpdated)); err != nil {
@@ -179,3 +190,51 @@ func (p *SLAEventProcessor) processSLAStatusUpdated(ctx context.Context, event *
 
 	return nil
 }
+
+// processRetracted announces that an SLA event was removed by a chain reorganisation
+func (p *SLAEventProcessor) processRetracted(ctx context.Context, event *domain.EventRetractedEvent) error {
+	message := map[string]interface{}{
+		"txHash":    event.TxHash,
+		"eventType": string(domain.EventTypeEventRetracted),
+	}
+
+	switch retracted := event.Retracted.(type) {
+	case *domain.SLAAddedEvent:
+		message["contractId"] = retracted.ContractID
+		message["contractIdHash"] = retracted.ContractIDHash
+		message["slaId"] = retracted.SLAID
+		message["retractedEventType"] = string(domain.EventTypeSLAAdded)
+	case *domain.SLAStatusUpdatedEvent:
+		status := domain.SLAStatus(retracted.NewStatus)
+		message["contractId"] = retracted.ContractID
+		message["contractIdHash"] = retracted.ContractIDHash
+		message["slaId"] = retracted.SLAID
+		message["slaIdHash"] = retracted.SLAIDHash
+		message["status"] = retracted.NewStatus
+		message["statusName"] = status.String()
+		message["retractedEventType"] = string(domain.EventTypeSLAStatusUpdated)
+		if status == domain.VIOLATED {
+			message["retractedEventType"] = string(domain.SLAViolated)
+		}
+	default:
+		// Only retractions of SLA events are handled here
+		return nil
+	}
+
+	p.logger.Warn("SLA event retracted by chain reorganisation",
+		zap.Any("contractId", message["contractId"]),
+		zap.Any("slaId", message["slaId"]),
+		zap.Any("retractedEventType", message["retractedEventType"]),
+		zap.String("txHash", event.TxHash),
+	)
+
+	if err := p.notifier.SendNotification(ctx, message, string(domain.EventTypeEventRetracted)); err != nil {
+		p.logger.Error("Failed to send notification for retracted SLA event",
+			zap.Error(err),
+			zap.String("txHash", event.TxHash),
+		)
+		return err
+	}
+
+	return nil
+}
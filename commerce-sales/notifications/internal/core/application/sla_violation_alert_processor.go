@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"notifications/internal/core/domain"
+
+	"go.uber.org/zap"
+)
+
+// SLAViolationAlertProcessor alerts the customer of a contract when one of its SLAs is violated.
+// It runs apart from the SLAEventProcessor, with its own retries and dead letters, so a failed
+// customer alert never sends the SLAViolated notification again, and the other way around.
+type SLAViolationAlertProcessor struct {
+	logger  *zap.Logger
+	alerter *CustomerAlerter
+}
+
+// NewSLAViolationAlertProcessor creates a new SLAViolationAlertProcessor instance
+func NewSLAViolationAlertProcessor(logger *zap.Logger, alerter *CustomerAlerter) *SLAViolationAlertProcessor {
+	return &SLAViolationAlertProcessor{
+		logger:  logger,
+		alerter: alerter,
+	}
+}
+
+// Process alerts the customer about SLAStatusUpdated events reporting a violation
+func (p *SLAViolationAlertProcessor) Process(ctx context.Context, event interface{}) error {
+	statusEvent, ok := event.(*domain.SLAStatusUpdatedEvent)
+	if !ok || domain.SLAStatus(statusEvent.NewStatus) != domain.VIOLATED {
+		// Only violations are alerted
+		return nil
+	}
+
+	p.logger.Info("Alerting customer about SLA violation",
+		zap.String("contractId", statusEvent.ContractID),
+		zap.String("slaId", statusEvent.SLAID),
+		zap.String("txHash", statusEvent.TxHash),
+	)
+
+	return p.alerter.AlertSLAViolation(ctx, statusEvent)
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"notifications/internal/core/domain"
+	"notifications/internal/core/port/driven"
+
+	"go.uber.org/zap"
+)
+
+// memoryDeadLetters keeps the dead letters it is given
+type memoryDeadLetters struct {
+	saved []domain.DeadLetter
+}
+
+func (s *memoryDeadLetters) Save(_ context.Context, deadLetter domain.DeadLetter) error {
+	s.saved = append(s.saved, deadLetter)
+	return nil
+}
+
+func (s *memoryDeadLetters) Get(context.Context, string) (*domain.DeadLetter, error) {
+	return nil, driven.ErrDeadLetterNotFound
+}
+
+func (s *memoryDeadLetters) List(context.Context) ([]domain.DeadLetter, error) {
+	return s.saved, nil
+}
+
+func (s *memoryDeadLetters) Delete(context.Context, string) error {
+	return nil
+}
+
+func violation(status domain.SLAStatus) *domain.SLAStatusUpdatedEvent {
+	return &domain.SLAStatusUpdatedEvent{
+		BlockchainEvent: domain.BlockchainEvent{TxHash: "0xabc"},
+		ContractID:      "contract-1",
+		SLAID:           "sla-1",
+		NewStatus:       uint8(status),
+	}
+}
+
+func TestSLAViolationAlertProcessorOnlyAlertsViolations(t *testing.T) {
+	customer := &domain.Customer{ID: "customer-1", Email: "procurement@sanrafael.com", PreferredChannel: domain.ChannelEmail}
+	email := &stubNotifier{}
+	alerter := NewCustomerAlerter(zap.NewNop(), stubRegistry{customerID: customer.ID}, stubDirectory{customer: customer},
+		map[domain.NotificationChannel]driven.DirectNotifier{domain.ChannelEmail: email}, nil)
+	processor := NewSLAViolationAlertProcessor(zap.NewNop(), alerter)
+
+	events := []interface{}{
+		violation(domain.COMPLIANT),
+		&domain.SLAAddedEvent{ContractID: "contract-1", SLAID: "sla-1"},
+		&domain.EventRetractedEvent{Retracted: violation(domain.VIOLATED)},
+		violation(domain.VIOLATED),
+	}
+	for _, event := range events {
+		if err := processor.Process(context.Background(), event); err != nil {
+			t.Fatalf("Process(%T) error = %v", event, err)
+		}
+	}
+
+	if want := []string{customer.Email}; !slices.Equal(email.recipients, want) {
+		t.Errorf("alerted %v, want %v", email.recipients, want)
+	}
+}
+
+func TestFailedCustomerAlertDoesNotResendTheViolation(t *testing.T) {
+	customer := &domain.Customer{ID: "customer-1", Email: "procurement@sanrafael.com", PreferredChannel: domain.ChannelEmail}
+	email := &stubNotifier{err: errors.New("smtp unavailable")}
+	notifier := &stubNotifier{}
+	deadLetters := &memoryDeadLetters{}
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	alerter := NewCustomerAlerter(zap.NewNop(), stubRegistry{customerID: customer.ID}, stubDirectory{customer: customer},
+		map[domain.NotificationChannel]driven.DirectNotifier{domain.ChannelEmail: email}, nil)
+	processors := []driven.EventProcessor{
+		NewRetryingProcessor(zap.NewNop(), NewSLAEventProcessor(zap.NewNop(), notifier), policy, deadLetters),
+		NewRetryingProcessor(zap.NewNop(), NewSLAViolationAlertProcessor(zap.NewNop(), alerter), policy, deadLetters),
+	}
+
+	for _, processor := range processors {
+		if err := processor.Process(context.Background(), violation(domain.VIOLATED)); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	if want := []string{string(domain.SLAViolated)}; !slices.Equal(notifier.eventTypes, want) {
+		t.Errorf("notifications = %v, want %v", notifier.eventTypes, want)
+	}
+	if len(email.recipients) != policy.MaxAttempts {
+		t.Errorf("customer alerted %d times, want %d", len(email.recipients), policy.MaxAttempts)
+	}
+
+	if len(deadLetters.saved) != 1 {
+		t.Fatalf("%d dead letters, want 1", len(deadLetters.saved))
+	}
+	if got, want := deadLetters.saved[0].Processor, ProcessorName(processors[1]); got != want {
+		t.Errorf("dead letter processor = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+// NotificationChannel is a channel a customer can be alerted on
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	ChannelSMS   NotificationChannel = "sms"
+)
+
+// Customer is a customer of the contracts service, with the contact details used for alerts
+type Customer struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"name"`
+	Email            string              `json:"email"`
+	Phone            string              `json:"phone"`
+	PreferredChannel NotificationChannel `json:"preferredChannel"`
+}
+
+// Channels returns the channels to try when alerting the customer, the preferred one first
+func (c Customer) Channels() []NotificationChannel {
+	channels := []NotificationChannel{ChannelEmail, ChannelSMS}
+	if c.PreferredChannel == ChannelSMS {
+		channels = []NotificationChannel{ChannelSMS, ChannelEmail}
+	}
+	return channels
+}
+
+// Recipient returns the customer's address on the channel, or "" when it is unknown
+func (c Customer) Recipient(channel NotificationChannel) string {
+	switch channel {
+	case ChannelEmail:
+		return c.Email
+	case ChannelSMS:
+		return c.Phone
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DeadLetter is an event a processor still failed to handle after all retries
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	Processor      string          `json:"processor"`
+	EventType      EventType       `json:"eventType"`
+	Event          json.RawMessage `json:"event"`
+	IdempotencyKey string          `json:"idempotencyKey"`
+	Error          string          `json:"error"`
+	Attempts       int             `json:"attempts"`
+	FailedAt       time.Time       `json:"failedAt"`
+}
+
+// EncodeEvent serializes a domain event (*ContractAddedEvent, *SLAAddedEvent, *SLAStatusUpdatedEvent
+// or *EventRetractedEvent) so it can be restored by DecodeEvent
+func EncodeEvent(event interface{}) (EventType, json.RawMessage, error) {
+	eventType, ok := EventTypeOf(event)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported event type %T", event)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return eventType, data, nil
+}
+
+// DecodeEvent restores an event serialized by EncodeEvent
+func DecodeEvent(data json.RawMessage) (interface{}, error) {
+	var header struct {
+		EventType EventType
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+
+	var event interface{}
+	switch header.EventType {
+	case EventTypeContractAdded:
+		event = &ContractAddedEvent{}
+	case EventTypeSLAAdded:
+		event = &SLAAddedEvent{}
+	case EventTypeSLAStatusUpdated:
+		event = &SLAStatusUpdatedEvent{}
+	case EventTypeEventRetracted:
+		return decodeRetractedEvent(data)
+	default:
+		return nil, fmt.Errorf("unsupported event type %q", header.EventType)
+	}
+
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", header.EventType, err)
+	}
+
+	return event, nil
+}
+
+// decodeRetractedEvent restores an EventRetractedEvent along with the event it retracts
+func decodeRetractedEvent(data json.RawMessage) (*EventRetractedEvent, error) {
+	var raw struct {
+		BlockchainEvent
+		Retracted json.RawMessage
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", EventTypeEventRetracted, err)
+	}
+
+	retracted, err := DecodeEvent(raw.Retracted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventRetractedEvent{
+		BlockchainEvent: raw.BlockchainEvent,
+		Retracted:       retracted,
+	}, nil
+}
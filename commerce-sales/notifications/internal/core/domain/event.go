@@ -9,7 +9,9 @@ import (
 type BlockchainEvent struct {
 	EventType   EventType
 	BlockNumber uint64
+	BlockHash   string
 	TxHash      string
+	LogIndex    uint
 	Timestamp   time.Time
 }
 
@@ -21,28 +23,62 @@ const (
 	EventTypeSLAAdded         EventType = "SLAAdded"
 	EventTypeSLAStatusUpdated EventType = "SLAStatusUpdated"
 	SLAViolated               EventType = "SLAViolated"
+	SLAViolationAlert         EventType = "SLAViolationAlert"
+	EventTypeEventRetracted   EventType = "EventRetracted"
 )
 
-// ContractAddedEvent represents the ContractAdded event from the blockchain
+// ContractAddedEvent represents the ContractAdded event from the blockchain.
+// Indexed string IDs are only emitted as their keccak256 hash: ContractIDHash always holds
+// that hash, and ContractID the readable ID, or the hash when it could not be resolved.
 type ContractAddedEvent struct {
 	BlockchainEvent
-	ContractID string
-	CustomerID string
+	ContractID     string
+	ContractIDHash string
+	CustomerID     string
 }
 
 // SLAAddedEvent represents the SLAAdded event from the blockchain
 type SLAAddedEvent struct {
 	BlockchainEvent
-	ContractID string
-	SLAID      string
+	ContractID     string
+	ContractIDHash string
+	SLAID          string
 }
 
-// SLAStatusUpdatedEvent represents the SLAStatusUpdated event from the blockchain
+// SLAStatusUpdatedEvent represents the SLAStatusUpdated event from the blockchain.
+// Both IDs are indexed, so SLAID falls back to SLAIDHash when it could not be resolved.
 type SLAStatusUpdatedEvent struct {
 	BlockchainEvent
-	ContractID string
-	SLAID      string
-	NewStatus  uint8
+	ContractID     string
+	ContractIDHash string
+	SLAID          string
+	SLAIDHash      string
+	NewStatus      uint8
+}
+
+// EventRetractedEvent is emitted when a log that was already dispatched is removed
+// from the canonical chain by a reorganisation
+type EventRetractedEvent struct {
+	BlockchainEvent
+	// Retracted is the previously dispatched event (*ContractAddedEvent, *SLAAddedEvent
+	// or *SLAStatusUpdatedEvent) that no longer happened
+	Retracted interface{}
+}
+
+// EventTypeOf returns the type of a domain event (*ContractAddedEvent, *SLAAddedEvent,
+// *SLAStatusUpdatedEvent or *EventRetractedEvent), or false for anything else
+func EventTypeOf(event interface{}) (EventType, bool) {
+	switch e := event.(type) {
+	case *ContractAddedEvent:
+		return e.EventType, true
+	case *SLAAddedEvent:
+		return e.EventType, true
+	case *SLAStatusUpdatedEvent:
+		return e.EventType, true
+	case *EventRetractedEvent:
+		return e.EventType, true
+	}
+	return "", false
 }
 
 // SLAStatus represents the status of an SLA
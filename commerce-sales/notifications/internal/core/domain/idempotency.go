@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+type idempotencyKeyContextKey struct{}
+
+// IdempotencyKey identifies the notifications of an event by (txHash, logIndex). A retraction
+// gets its own key, so it isn't mistaken for a duplicate of the event it retracts.
+func (e BlockchainEvent) IdempotencyKey() string {
+	key := fmt.Sprintf("%s:%d", e.TxHash, e.LogIndex)
+	if e.EventType == EventTypeEventRetracted {
+		key += ":retracted"
+	}
+	return key
+}
+
+// WithIdempotencyKey returns a copy of ctx carrying the idempotency key of the event being processed,
+// so notifiers can pass it downstream
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if any
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
@@ -0,0 +1,13 @@
+package driven
+
+import "context"
+
+// CheckpointStore persists the last block whose events were fully processed,
+// so the listener can resume from it after a restart
+type CheckpointStore interface {
+	// Load returns the last processed block for the given key and whether a checkpoint exists
+	Load(ctx context.Context, key string) (uint64, bool, error)
+
+	// Save records the last processed block for the given key
+	Save(ctx context.Context, key string, block uint64) error
+}
@@ -0,0 +1,23 @@
+package driven
+
+import (
+	"context"
+	"errors"
+
+	"notifications/internal/core/domain"
+)
+
+// ErrCustomerNotFound is returned when a customer or the contract it is looked up by doesn't exist
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// ContractRegistry reads the contracts stored on chain
+type ContractRegistry interface {
+	// CustomerID returns the ID of the customer a contract belongs to
+	CustomerID(ctx context.Context, contractID string) (string, error)
+}
+
+// CustomerDirectory looks up the contact details of customers
+type CustomerDirectory interface {
+	// FindCustomer returns the customer with the given ID
+	FindCustomer(ctx context.Context, customerID string) (*domain.Customer, error)
+}
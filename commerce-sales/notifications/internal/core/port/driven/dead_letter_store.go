@@ -0,0 +1,26 @@
+package driven
+
+import (
+	"context"
+	"errors"
+
+	"notifications/internal/core/domain"
+)
+
+// ErrDeadLetterNotFound is returned when a dead letter doesn't exist
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetterStore keeps the events processors failed to handle, so they can be inspected and replayed
+type DeadLetterStore interface {
+	// Save adds the dead letter, or replaces the one with the same ID
+	Save(ctx context.Context, deadLetter domain.DeadLetter) error
+
+	// Get returns the dead letter with the given ID
+	Get(ctx context.Context, id string) (*domain.DeadLetter, error)
+
+	// List returns all dead letters, oldest first
+	List(ctx context.Context) ([]domain.DeadLetter, error)
+
+	// Delete removes the dead letter with the given ID
+	Delete(ctx context.Context, id string) error
+}
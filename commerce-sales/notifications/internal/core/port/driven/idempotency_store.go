@@ -0,0 +1,16 @@
+package driven
+
+import "context"
+
+// IdempotencyStore remembers the events that were already notified for a bounded time,
+// so that a log delivered twice doesn't produce duplicate notifications
+type IdempotencyStore interface {
+	// Seen reports whether the key was recorded and hasn't expired yet
+	Seen(ctx context.Context, key string) (bool, error)
+
+	// Record marks the key as notified for the store's TTL
+	Record(ctx context.Context, key string) error
+
+	// Forget removes the key, e.g. when its event was retracted by a reorg
+	Forget(ctx context.Context, key string) error
+}
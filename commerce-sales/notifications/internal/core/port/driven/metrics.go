@@ -0,0 +1,21 @@
+package driven
+
+import "time"
+
+// Metrics records the operational metrics of the listener, the event processors and the notifiers
+type Metrics interface {
+	// EventReceived counts a decoded event of the given type
+	EventReceived(eventType string)
+
+	// ProcessorCompleted records how long a processor took to handle an event, and whether it failed
+	ProcessorCompleted(processor string, duration time.Duration, err error)
+
+	// NotificationSent counts a notification delivered, or failed, by a notifier backend
+	NotificationSent(backend string, err error)
+
+	// Reconnected counts a reconnection to the blockchain node
+	Reconnected()
+
+	// BlockLag records how many confirmed blocks are not processed yet
+	BlockLag(blocks uint64)
+}
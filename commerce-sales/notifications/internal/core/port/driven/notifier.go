@@ -5,3 +5,8 @@ import "context"
 type Notifier interface {
 	SendNotification(ctx context.Context, message map[string]interface{}, eventType string) error
 }
+
+// DirectNotifier sends a notification to a single recipient, e.g. an email address or a phone number
+type DirectNotifier interface {
+	SendTo(ctx context.Context, recipient string, message map[string]interface{}, eventType string) error
+}
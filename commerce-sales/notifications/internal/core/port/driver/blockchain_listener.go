@@ -18,4 +18,7 @@ type BlockchainListener interface {
 
 	// IsConnected returns true if the listener is connected to the blockchain
 	IsConnected() bool
+
+	// BlockLag returns how many confirmed blocks are not processed yet, and false while unknown
+	BlockLag() (uint64, bool)
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/adapter/http"
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/adapter/queue"
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/application"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 )
 
 func main() {
@@ -18,6 +21,17 @@ func main() {
 	rabbitHost := os.Getenv("RABBITMQ_HOST")
 	rabbitExchange := os.Getenv("RABBITMQ_EXCHANGE")
 
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "invoices")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	// Initialize RabbitMQ adapter
 	rabbitMQ, err := queue.NewRabbitMQ(rabbitUser, rabbitPassword, rabbitHost)
 	if err != nil {
@@ -29,12 +43,17 @@ func main() {
 	service := application.NewInvoiceService(rabbitMQ, rabbitExchange)
 	handler := http.NewInvoiceHandler(service)
 
-	// Setup router
+	// Setup router with request tracing and Prometheus request metrics by route template
 	router := gin.Default()
+	router.Use(tracing.GinMiddleware())
+	router.Use(metrics.GinMiddleware())
 
 	// Health check
 	router.GET("/ping", http.PongHandler)
 
+	// Prometheus metrics
+	router.GET("/metrics", metrics.Handler())
+
 	// Invoice routes
 	router.GET("/", handler.GetInvoices)
 	router.GET("/:id", handler.GetInvoice)
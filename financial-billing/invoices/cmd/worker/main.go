@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/adapter/queue"
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/application"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 )
 
 func main() {
@@ -23,6 +26,23 @@ func main() {
 		rabbitQueue = "purchases.events" // Default queue name
 	}
 
+	// Address the Prometheus metrics are served on
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "invoices-worker")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	// Initialize RabbitMQ adapter
 	rabbitMQ, err := queue.NewRabbitMQ(rabbitUser, rabbitPassword, rabbitHost)
 	if err != nil {
@@ -33,6 +53,14 @@ func main() {
 	// Initialize invoice service (needed for handling purchase updates)
 	invoiceService := application.NewInvoiceService(rabbitMQ, rabbitExchange)
 
+	// Serve Prometheus metrics
+	go func() {
+		log.Printf("Serving metrics on %s", metricsAddr)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+
 	log.Println("Starting Invoice Service Events Consumer...")
 	log.Printf("  - Consuming from purchases queue: %s", rabbitQueue)
 
@@ -40,8 +68,8 @@ func main() {
 	done := make(chan error, 1)
 
 	// Start consuming purchase events
-	if err = rabbitMQ.Consume(rabbitQueue, func(body []byte) error {
-		return rabbitMQ.HandlePurchaseEvent(body, invoiceService)
+	if err = rabbitMQ.Consume(rabbitQueue, func(ctx context.Context, body []byte) error {
+		return rabbitMQ.HandlePurchaseEvent(ctx, body, invoiceService)
 	}, done); err != nil {
 		log.Fatalf("Failed to start consumer: %v", err)
 	}
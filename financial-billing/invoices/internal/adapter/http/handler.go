@@ -25,7 +25,7 @@ func PongHandler(c *gin.Context) {
 }
 
 func (h *InvoiceHandler) GetInvoices(c *gin.Context) {
-	invoices, err := h.service.RetrieveInvoices()
+	invoices, err := h.service.RetrieveInvoices(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -37,7 +37,7 @@ func (h *InvoiceHandler) GetInvoices(c *gin.Context) {
 func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
 	id := c.Param("id")
 
-	invoice, err := h.service.RetrieveInvoice(id)
+	invoice, err := h.service.RetrieveInvoice(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, application.ErrInvoiceNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -69,7 +69,7 @@ func (h *InvoiceHandler) PostInvoice(c *gin.Context) {
 		return
 	}
 
-	invoice, err := h.service.CreateInvoice(req.PurchasesID, req.Buyer, req.Subtotal, req.Discount, req.Taxes)
+	invoice, err := h.service.CreateInvoice(c.Request.Context(), req.PurchasesID, req.Buyer, req.Subtotal, req.Discount, req.Taxes)
 	if err != nil {
 		if errors.Is(err, application.ErrInvalidInput) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -98,7 +98,7 @@ func (h *InvoiceHandler) PutInvoice(c *gin.Context) {
 		return
 	}
 
-	invoice, err := h.service.UpdateInvoice(id, req.PurchasesID, req.Buyer, req.Subtotal, req.Discount, req.Taxes)
+	invoice, err := h.service.UpdateInvoice(c.Request.Context(), id, req.PurchasesID, req.Buyer, req.Subtotal, req.Discount, req.Taxes)
 	if err != nil {
 		if errors.Is(err, application.ErrInvoiceNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -118,7 +118,7 @@ func (h *InvoiceHandler) PutInvoice(c *gin.Context) {
 func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.service.DeleteInvoice(id)
+	err := h.service.DeleteInvoice(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, application.ErrInvoiceNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -10,7 +10,12 @@ import (
 
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/domain"
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/port/driver"
+	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/pkg/metrics"
 	amqp "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/amqptrace"
 )
 
 // RabbitMQ implements the MessagePublisher interface
@@ -52,22 +57,39 @@ func NewRabbitMQ(user, password, host string) (*RabbitMQ, error) {
 	}, nil
 }
 
-// Publish publishes a message to an exchange with a routing key
-func (r *RabbitMQ) Publish(exchange, routingKey string, body []byte) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Publish publishes a message to an exchange with a routing key within a producer span,
+// carrying the trace context in the message headers
+func (r *RabbitMQ) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
+	ctx, span := tracing.Tracer().Start(ctx, exchange+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+			semconv.MessagingMessageBodySize(len(body)),
+		),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent, // persistent messages
+		Timestamp:    time.Now(),
+	}
+	amqptrace.Inject(ctx, &msg)
+
 	err := r.channel.PublishWithContext(ctx,
 		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent, // persistent messages
-			Timestamp:    time.Now(),
-		})
+		msg)
+	metrics.RabbitMQMessagePublished(exchange, routingKey, err)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
@@ -76,7 +98,7 @@ func (r *RabbitMQ) Publish(exchange, routingKey string, body []byte) error {
 }
 
 // Consume starts consuming messages from a queue with connection monitoring
-func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error, done chan error) error {
+func (r *RabbitMQ) Consume(queueName string, handler func(context.Context, []byte) error, done chan error) error {
 	messages, err := r.channel.Consume(
 		queueName, // queue
 		"",        // consumer
@@ -103,7 +125,7 @@ func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error, done ch
 					done <- fmt.Errorf("messages channel closed")
 					return
 				}
-				if err = handler(msg.Body); err != nil {
+				if err := r.handleDelivery(queueName, msg, handler); err != nil {
 					log.Printf("Error handling message: %v", err)
 					// Negative acknowledgment - requeue the message
 					msg.Nack(false, true)
@@ -127,6 +149,27 @@ func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error, done ch
 	return nil
 }
 
+// handleDelivery runs the handler on a delivery within a consumer span that continues the
+// trace found in its headers, and counts the outcome
+func (r *RabbitMQ) handleDelivery(queueName string, msg amqp.Delivery, handler func(context.Context, []byte) error) error {
+	ctx, span := tracing.Tracer().Start(amqptrace.Extract(context.Background(), &msg), queueName+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(queueName),
+			semconv.MessagingRabbitMQDestinationRoutingKey(msg.RoutingKey),
+			semconv.MessagingMessageBodySize(len(msg.Body)),
+		),
+	)
+
+	err := handler(ctx, msg.Body)
+	metrics.RabbitMQMessageConsumed(queueName, err)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
 // Close closes the channel and connection
 func (r *RabbitMQ) Close() error {
 	if r.channel != nil {
@@ -148,7 +191,7 @@ func (r *RabbitMQ) IsConnected() bool {
 }
 
 // HandleInvoiceEvent processes incoming invoice events
-func (r *RabbitMQ) HandleInvoiceEvent(body []byte) error {
+func (r *RabbitMQ) HandleInvoiceEvent(_ context.Context, body []byte) error {
 	var event domain.InvoiceEvent
 	if err := json.Unmarshal(body, &event); err != nil {
 		log.Printf("ERROR: Failed to unmarshal invoice event: %v", err)
@@ -190,7 +233,7 @@ func (r *RabbitMQ) HandleInvoiceEvent(body []byte) error {
 }
 
 // HandlePurchaseEvent processes incoming purchase events from the purchases microservice
-func (r *RabbitMQ) HandlePurchaseEvent(body []byte, invoiceService driver.InvoiceService) error {
+func (r *RabbitMQ) HandlePurchaseEvent(ctx context.Context, body []byte, invoiceService driver.InvoiceService) error {
 	var event domain.PurchaseEvent
 	if err := json.Unmarshal(body, &event); err != nil {
 		log.Printf("ERROR: Failed to unmarshal purchase event: %v", err)
@@ -209,7 +252,7 @@ func (r *RabbitMQ) HandlePurchaseEvent(body []byte, invoiceService driver.Invoic
 			event.Purchase.Total,
 		)
 		// Call the service to handle the update
-		if err := invoiceService.ProcessPurchaseUpdated(event.Purchase); err != nil {
+		if err := invoiceService.ProcessPurchaseUpdated(ctx, event.Purchase); err != nil {
 			log.Printf("ERROR: Failed to handle purchase update: %v", err)
 			return err
 		}
@@ -219,7 +262,7 @@ func (r *RabbitMQ) HandlePurchaseEvent(body []byte, invoiceService driver.Invoic
 			event.Purchase.ID,
 		)
 		// Call the service to handle the deletion if needed
-		if err := invoiceService.ProcessPurchaseDeleted(event.Purchase.ID); err != nil {
+		if err := invoiceService.ProcessPurchaseDeleted(ctx, event.Purchase.ID); err != nil {
 			log.Printf("ERROR: Failed to handle purchase deletion: %v", err)
 			return err
 		}
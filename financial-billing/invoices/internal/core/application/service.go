@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
@@ -61,11 +62,11 @@ func NewInvoiceService(messagePublisher driven.Publisher, exchange string) *Invo
 	}
 }
 
-func (s *InvoiceService) RetrieveInvoices() ([]domain.Invoice, error) {
+func (s *InvoiceService) RetrieveInvoices(_ context.Context) ([]domain.Invoice, error) {
 	return data, nil
 }
 
-func (s *InvoiceService) RetrieveInvoice(id string) (*domain.Invoice, error) {
+func (s *InvoiceService) RetrieveInvoice(_ context.Context, id string) (*domain.Invoice, error) {
 	if id == "" {
 		return nil, ErrInvalidInput
 	}
@@ -79,7 +80,7 @@ func (s *InvoiceService) RetrieveInvoice(id string) (*domain.Invoice, error) {
 	return nil, ErrInvoiceNotFound
 }
 
-func (s *InvoiceService) CreateInvoice(purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error) {
+func (s *InvoiceService) CreateInvoice(ctx context.Context, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error) {
 	if len(purchasesID) == 0 || buyer == "" || subtotal < 0 || discount < 0 || taxes < 0 {
 		return nil, ErrInvalidInput
 	}
@@ -100,12 +101,12 @@ func (s *InvoiceService) CreateInvoice(purchasesID []string, buyer string, subto
 	data = append(data, *invoice)
 
 	// Publish event
-	s.publishEvent(domain.NewInvoiceCreatedEvent(*invoice), s.exchange)
+	s.publishEvent(ctx, domain.NewInvoiceCreatedEvent(*invoice), s.exchange)
 
 	return invoice, nil
 }
 
-func (s *InvoiceService) UpdateInvoice(id string, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error) {
+func (s *InvoiceService) UpdateInvoice(ctx context.Context, id string, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error) {
 	if id == "" || len(purchasesID) == 0 || buyer == "" || subtotal < 0 || discount < 0 || taxes < 0 {
 		return nil, ErrInvalidInput
 	}
@@ -144,12 +145,12 @@ func (s *InvoiceService) UpdateInvoice(id string, purchasesID []string, buyer st
 	}
 
 	// Publish event
-	s.publishEvent(domain.NewInvoiceUpdatedEvent(*invoice), s.exchange)
+	s.publishEvent(ctx, domain.NewInvoiceUpdatedEvent(*invoice), s.exchange)
 
 	return invoice, nil
 }
 
-func (s *InvoiceService) DeleteInvoice(id string) error {
+func (s *InvoiceService) DeleteInvoice(ctx context.Context, id string) error {
 	if id == "" {
 		return ErrInvalidInput
 	}
@@ -171,7 +172,7 @@ func (s *InvoiceService) DeleteInvoice(id string) error {
 		if invoice.ID == id {
 			data = append(data[:i], data[i+1:]...)
 			// Publish event
-			s.publishEvent(domain.NewInvoiceDeletedEvent(invoice), s.exchange)
+			s.publishEvent(ctx, domain.NewInvoiceDeletedEvent(invoice), s.exchange)
 			break
 		}
 	}
@@ -181,7 +182,7 @@ func (s *InvoiceService) DeleteInvoice(id string) error {
 
 // ProcessPurchaseUpdated process purchase.updated events from the purchases microservice
 // It updates all invoices that contain the modified purchase and recalculates totals
-func (s *InvoiceService) ProcessPurchaseUpdated(purchase domain.Purchase) error {
+func (s *InvoiceService) ProcessPurchaseUpdated(ctx context.Context, purchase domain.Purchase) error {
 	log.Printf("📦 Processing purchase.updated event for Purchase ID: %s", purchase.ID)
 
 	updatedCount := 0
@@ -203,7 +204,7 @@ func (s *InvoiceService) ProcessPurchaseUpdated(purchase domain.Purchase) error
 
 			// Optionally publish an event that this invoice was affected
 			updatedInvoice := data[i]
-			s.publishEvent(domain.NewInvoiceUpdatedEvent(updatedInvoice), s.exchange)
+			s.publishEvent(ctx, domain.NewInvoiceUpdatedEvent(updatedInvoice), s.exchange)
 			updatedCount++
 		}
 	}
@@ -219,7 +220,7 @@ func (s *InvoiceService) ProcessPurchaseUpdated(purchase domain.Purchase) error
 
 // ProcessPurchaseDeleted process purchase.deleted events from the purchases microservice
 // It removes the deleted purchase from all invoices that contain it and recalculates totals
-func (s *InvoiceService) ProcessPurchaseDeleted(id string) error {
+func (s *InvoiceService) ProcessPurchaseDeleted(ctx context.Context, id string) error {
 	log.Printf("📦 Processing purchase.deleted event for Purchase ID: %s", id)
 
 	updatedCount := 0
@@ -249,7 +250,7 @@ func (s *InvoiceService) ProcessPurchaseDeleted(id string) error {
 
 		// Optionally publish an event that this invoice was affected
 		updatedInvoice := data[i]
-		s.publishEvent(domain.NewInvoiceUpdatedEvent(updatedInvoice), s.exchange)
+		s.publishEvent(ctx, domain.NewInvoiceUpdatedEvent(updatedInvoice), s.exchange)
 		updatedCount++
 	}
 	if updatedCount > 0 {
@@ -259,7 +260,7 @@ func (s *InvoiceService) ProcessPurchaseDeleted(id string) error {
 }
 
 // publishEvent publishes a domain event to RabbitMQ
-func (s *InvoiceService) publishEvent(event domain.InvoiceEvent, routingKey string) {
+func (s *InvoiceService) publishEvent(ctx context.Context, event domain.InvoiceEvent, routingKey string) {
 	if s.messagePublisher == nil {
 		log.Println("Message publisher not configured, skipping event publishing")
 		return
@@ -271,7 +272,7 @@ func (s *InvoiceService) publishEvent(event domain.InvoiceEvent, routingKey stri
 		return
 	}
 
-	err = s.messagePublisher.Publish(s.exchange, routingKey, body)
+	err = s.messagePublisher.Publish(ctx, s.exchange, routingKey, body)
 	if err != nil {
 		log.Printf("Failed to publish event: %v", err)
 	}
@@ -1,7 +1,9 @@
 package driven
 
+import "context"
+
 // Publisher defines the interface for publishing messages
 type Publisher interface {
-	Publish(exchange, routingKey string, body []byte) error
+	Publish(ctx context.Context, exchange, routingKey string, body []byte) error
 	Close() error
 }
@@ -1,13 +1,17 @@
 package driver
 
-import "github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/domain"
+import (
+	"context"
+
+	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/invoices/internal/core/domain"
+)
 
 type InvoiceService interface {
-	RetrieveInvoices() ([]domain.Invoice, error)
-	RetrieveInvoice(id string) (*domain.Invoice, error)
-	CreateInvoice(purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error)
-	UpdateInvoice(id string, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error)
-	DeleteInvoice(id string) error
-	ProcessPurchaseUpdated(purchase domain.Purchase) error
-	ProcessPurchaseDeleted(id string) error
+	RetrieveInvoices(ctx context.Context) ([]domain.Invoice, error)
+	RetrieveInvoice(ctx context.Context, id string) (*domain.Invoice, error)
+	CreateInvoice(ctx context.Context, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error)
+	UpdateInvoice(ctx context.Context, id string, purchasesID []string, buyer string, subtotal, discount, taxes float64) (*domain.Invoice, error)
+	DeleteInvoice(ctx context.Context, id string) error
+	ProcessPurchaseUpdated(ctx context.Context, purchase domain.Purchase) error
+	ProcessPurchaseDeleted(ctx context.Context, id string) error
 }
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/purchases/internal/adapter/queue"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 )
 
 func main() {
@@ -16,6 +19,23 @@ func main() {
 	rabbitHost := os.Getenv("RABBITMQ_HOST")
 	rabbitQueue := os.Getenv("RABBITMQ_QUEUE")
 
+	// Address the Prometheus metrics are served on
+	metricsAddr := os.Getenv("METRICS_ADDR")
+	if metricsAddr == "" {
+		metricsAddr = ":9090"
+	}
+
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "purchases-worker")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	// Initialize RabbitMQ adapter
 	rabbitMQ, err := queue.NewRabbitMQ(rabbitUser, rabbitPassword, rabbitHost)
 	if err != nil {
@@ -23,6 +43,14 @@ func main() {
 	}
 	defer rabbitMQ.Close()
 
+	// Serve Prometheus metrics
+	go func() {
+		log.Printf("Serving metrics on %s", metricsAddr)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+
 	log.Println("Starting Purchase Events Consumer...")
 
 	// Start consuming messages
@@ -37,7 +37,7 @@ func PongHandler(c *gin.Context) {
 }
 
 func (h *PurchaseHandler) GetPurchases(c *gin.Context) {
-	purchases, err := h.service.RetrievePurchases()
+	purchases, err := h.service.RetrievePurchases(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -49,7 +49,7 @@ func (h *PurchaseHandler) GetPurchases(c *gin.Context) {
 func (h *PurchaseHandler) GetPurchase(c *gin.Context) {
 	id := c.Param("id")
 
-	purchase, err := h.service.RetrievePurchase(id)
+	purchase, err := h.service.RetrievePurchase(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -77,7 +77,7 @@ func (h *PurchaseHandler) PostPurchase(c *gin.Context) {
 	// Publish raw message to RabbitMQ with a generic routing key
 	if h.publisher != nil {
 		routingKey := "purchases.events"
-		if err := h.publisher.Publish(h.exchange, routingKey, bodyBytes); err != nil {
+		if err := h.publisher.Publish(c.Request.Context(), h.exchange, routingKey, bodyBytes); err != nil {
 			log.Printf("Failed to publish CloudEvent purchase message: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to publish message"})
 			return
@@ -102,7 +102,7 @@ func (h *PurchaseHandler) PutPurchase(c *gin.Context) {
 		return
 	}
 
-	purchase, err := h.service.UpdatePurchase(id, req.Price, req.Quantity)
+	purchase, err := h.service.UpdatePurchase(c.Request.Context(), id, req.Price, req.Quantity)
 	if err != nil {
 		if errors.Is(err, application.ErrPurchaseNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -122,7 +122,7 @@ func (h *PurchaseHandler) PutPurchase(c *gin.Context) {
 func (h *PurchaseHandler) DeletePurchase(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.service.DeletePurchase(id)
+	err := h.service.DeletePurchase(c.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, application.ErrPurchaseNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
@@ -201,7 +201,7 @@ func (h *PurchaseHandler) HandleCloudEvent(c *gin.Context) {
 		}
 
 		// Publish to RabbitMQ
-		err = h.publisher.Publish(h.exchange, routingKey, eventBytes)
+		err = h.publisher.Publish(c.Request.Context(), h.exchange, routingKey, eventBytes)
 		if err != nil {
 			log.Printf("Failed to publish CloudEvent to RabbitMQ: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish event"})
@@ -9,7 +9,12 @@ import (
 	"time"
 
 	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/purchases/internal/core/domain"
+	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/purchases/pkg/metrics"
 	amqp "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/amqptrace"
 )
 
 // RabbitMQ implements the MessagePublisher interface
@@ -51,22 +56,39 @@ func NewRabbitMQ(user, password, host string) (*RabbitMQ, error) {
 	}, nil
 }
 
-// Publish publishes a message to an exchange with a routing key
-func (r *RabbitMQ) Publish(exchange, routingKey string, body []byte) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Publish publishes a message to an exchange with a routing key within a producer span,
+// carrying the trace context in the message headers
+func (r *RabbitMQ) Publish(ctx context.Context, exchange, routingKey string, body []byte) error {
+	ctx, span := tracing.Tracer().Start(ctx, exchange+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(exchange),
+			semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+			semconv.MessagingMessageBodySize(len(body)),
+		),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		DeliveryMode: amqp.Persistent, // persistent messages
+		Timestamp:    time.Now(),
+	}
+	amqptrace.Inject(ctx, &msg)
+
 	err := r.channel.PublishWithContext(ctx,
 		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
 		false,      // immediate
-		amqp.Publishing{
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent, // persistent messages
-			Timestamp:    time.Now(),
-		})
+		msg)
+	metrics.RabbitMQMessagePublished(exchange, routingKey, err)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
@@ -75,7 +97,7 @@ func (r *RabbitMQ) Publish(exchange, routingKey string, body []byte) error {
 }
 
 // Consume starts consuming messages from a queue
-func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
+func (r *RabbitMQ) Consume(queueName string, handler func(context.Context, []byte) error) error {
 	messages, err := r.channel.Consume(
 		queueName, // queue
 		"",        // consumer
@@ -91,7 +113,7 @@ func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
 
 	go func() {
 		for msg := range messages {
-			if err := handler(msg.Body); err != nil {
+			if err := r.handleDelivery(queueName, msg, handler); err != nil {
 				log.Printf("Error handling message: %v", err)
 				// Negative acknowledgment - requeue the message
 				msg.Nack(false, true)
@@ -106,6 +128,27 @@ func (r *RabbitMQ) Consume(queueName string, handler func([]byte) error) error {
 	return nil
 }
 
+// handleDelivery runs the handler on a delivery within a consumer span that continues the
+// trace found in its headers, and counts the outcome
+func (r *RabbitMQ) handleDelivery(queueName string, msg amqp.Delivery, handler func(context.Context, []byte) error) error {
+	ctx, span := tracing.Tracer().Start(amqptrace.Extract(context.Background(), &msg), queueName+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(queueName),
+			semconv.MessagingRabbitMQDestinationRoutingKey(msg.RoutingKey),
+			semconv.MessagingMessageBodySize(len(msg.Body)),
+		),
+	)
+
+	err := handler(ctx, msg.Body)
+	metrics.RabbitMQMessageConsumed(queueName, err)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
 // Close closes the channel and connection
 func (r *RabbitMQ) Close() error {
 	if r.channel != nil {
@@ -127,7 +170,7 @@ func (r *RabbitMQ) IsConnected() bool {
 }
 
 // HandleEvent processes incoming purchase events
-func (r *RabbitMQ) HandleEvent(body []byte) error {
+func (r *RabbitMQ) HandleEvent(_ context.Context, body []byte) error {
 	var event domain.PurchaseEvent
 	if err := json.Unmarshal(body, &event); err != nil {
 		log.Printf("ERROR: Failed to unmarshal event: %v", err)
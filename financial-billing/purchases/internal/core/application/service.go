@@ -1,6 +1,7 @@
 package application
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
@@ -66,11 +67,11 @@ func NewPurchaseService(messagePublisher driven.Publisher, exchange string) *Pur
 	}
 }
 
-func (s *PurchaseService) RetrievePurchases() ([]domain.Purchase, error) {
+func (s *PurchaseService) RetrievePurchases(_ context.Context) ([]domain.Purchase, error) {
 	return data, nil
 }
 
-func (s *PurchaseService) RetrievePurchase(id string) (*domain.Purchase, error) {
+func (s *PurchaseService) RetrievePurchase(_ context.Context, id string) (*domain.Purchase, error) {
 	if id == "" {
 		return nil, ErrInvalidInput
 	}
@@ -84,7 +85,7 @@ func (s *PurchaseService) RetrievePurchase(id string) (*domain.Purchase, error)
 	return nil, ErrPurchaseNotFound
 }
 
-func (s *PurchaseService) CreatePurchase(price float64, quantity int) (*domain.Purchase, error) {
+func (s *PurchaseService) CreatePurchase(ctx context.Context, price float64, quantity int) (*domain.Purchase, error) {
 	if price <= 0 || quantity <= 0 {
 		return nil, ErrInvalidInput
 	}
@@ -100,12 +101,12 @@ func (s *PurchaseService) CreatePurchase(price float64, quantity int) (*domain.P
 	data = append(data, *purchase)
 
 	// Publish event
-	s.publishEvent(domain.NewPurchaseCreatedEvent(*purchase), s.exchange)
+	s.publishEvent(ctx, domain.NewPurchaseCreatedEvent(*purchase), s.exchange)
 
 	return purchase, nil
 }
 
-func (s *PurchaseService) UpdatePurchase(id string, price float64, quantity int) (*domain.Purchase, error) {
+func (s *PurchaseService) UpdatePurchase(ctx context.Context, id string, price float64, quantity int) (*domain.Purchase, error) {
 	if id == "" || price <= 0 || quantity <= 0 {
 		return nil, ErrInvalidInput
 	}
@@ -139,12 +140,12 @@ func (s *PurchaseService) UpdatePurchase(id string, price float64, quantity int)
 	}
 
 	// Publish event
-	s.publishEvent(domain.NewPurchaseUpdatedEvent(*purchase), s.exchange)
+	s.publishEvent(ctx, domain.NewPurchaseUpdatedEvent(*purchase), s.exchange)
 
 	return purchase, nil
 }
 
-func (s *PurchaseService) DeletePurchase(id string) error {
+func (s *PurchaseService) DeletePurchase(ctx context.Context, id string) error {
 	if id == "" {
 		return ErrInvalidInput
 	}
@@ -166,7 +167,7 @@ func (s *PurchaseService) DeletePurchase(id string) error {
 		if purchase.ID == id {
 			data = append(data[:i], data[i+1:]...)
 			// Publish event
-			s.publishEvent(domain.NewPurchaseDeletedEvent(purchase), s.exchange)
+			s.publishEvent(ctx, domain.NewPurchaseDeletedEvent(purchase), s.exchange)
 			break
 		}
 	}
@@ -175,7 +176,7 @@ func (s *PurchaseService) DeletePurchase(id string) error {
 }
 
 // publishEvent publishes a domain event to RabbitMQ
-func (s *PurchaseService) publishEvent(event domain.PurchaseEvent, routingKey string) {
+func (s *PurchaseService) publishEvent(ctx context.Context, event domain.PurchaseEvent, routingKey string) {
 	if s.messagePublisher == nil {
 		log.Println("Message publisher not configured, skipping event publishing")
 		return
@@ -187,7 +188,7 @@ func (s *PurchaseService) publishEvent(event domain.PurchaseEvent, routingKey st
 		return
 	}
 
-	err = s.messagePublisher.Publish("purchases_exchange", routingKey, body)
+	err = s.messagePublisher.Publish(ctx, "purchases_exchange", routingKey, body)
 	if err != nil {
 		log.Printf("Failed to publish event: %v", err)
 	}
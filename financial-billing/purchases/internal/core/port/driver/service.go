@@ -1,11 +1,15 @@
 package driver
 
-import "github.com/medisupply/medisupply-infrastructure/micro-dummies/services/purchases/internal/core/domain"
+import (
+	"context"
+
+	"github.com/medisupply/medisupply-infrastructure/micro-dummies/services/purchases/internal/core/domain"
+)
 
 type PurchaseService interface {
-	RetrievePurchases() ([]domain.Purchase, error)
-	RetrievePurchase(id string) (*domain.Purchase, error)
-	CreatePurchase(price float64, quantity int) (*domain.Purchase, error)
-	UpdatePurchase(id string, price float64, quantity int) (*domain.Purchase, error)
-	DeletePurchase(id string) error
+	RetrievePurchases(ctx context.Context) ([]domain.Purchase, error)
+	RetrievePurchase(ctx context.Context, id string) (*domain.Purchase, error)
+	CreatePurchase(ctx context.Context, price float64, quantity int) (*domain.Purchase, error)
+	UpdatePurchase(ctx context.Context, id string, price float64, quantity int) (*domain.Purchase, error)
+	DeletePurchase(ctx context.Context, id string) error
 }
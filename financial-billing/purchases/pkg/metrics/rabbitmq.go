@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	rabbitMQMessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_published_total",
+		Help: "RabbitMQ messages published, by exchange, routing key and result (success or failure).",
+	}, []string{"exchange", "routing_key", "result"})
+
+	rabbitMQMessagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rabbitmq_messages_consumed_total",
+		Help: "RabbitMQ messages consumed, by queue and result (success or failure).",
+	}, []string{"queue", "result"})
+)
+
+// RabbitMQMessagePublished counts a published message, failed when err is not nil
+func RabbitMQMessagePublished(exchange, routingKey string, err error) {
+	rabbitMQMessagesPublished.WithLabelValues(exchange, routingKey, result(err)).Inc()
+}
+
+// RabbitMQMessageConsumed counts a consumed message, failed when err is not nil
+func RabbitMQMessageConsumed(queue string, err error) {
+	rabbitMQMessagesConsumed.WithLabelValues(queue, result(err)).Inc()
+}
+
+// result labels the outcome of an operation
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
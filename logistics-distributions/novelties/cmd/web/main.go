@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"math/big"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 	"novelties/internal/adapter/blockchain"
 	"novelties/internal/adapter/http"
 	"novelties/pkg/logger"
 	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -24,7 +30,25 @@ func main() {
 	contractAddress := os.Getenv("CONTRACT_ADDRESS")
 	privateKey := os.Getenv("PRIVATE_KEY")
 
-	blockchainWriter, err := blockchain.NewEthereumWriter(rcpURL, contractAddress, privateKey, log)
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "novelties")
+	if err != nil {
+		log.Fatalw("Failed to initialize tracing",
+			"error", err,
+		)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Errorw("Failed to flush traces",
+				"error", err,
+			)
+		}
+	}()
+
+	gasConfig := loadGasConfig(log)
+	confirmations := getEnvAsUint64(log, "CONFIRMATION_DEPTH", 0)
+
+	blockchainWriter, err := blockchain.NewEthereumWriter(rcpURL, contractAddress, privateKey, gasConfig, confirmations, log)
 	if err != nil {
 		log.Fatalw("Failed to create blockchain writer",
 			"error", err,
@@ -34,15 +58,95 @@ func main() {
 
 	handler := http.NewNoveltyHandler(blockchainWriter, log)
 
-	// Setup router
+	// Setup router with request tracing and Prometheus request metrics by route template
 	router := gin.Default()
+	router.Use(tracing.GinMiddleware())
+	router.Use(metrics.GinMiddleware())
 
 	// Health check
 	router.GET("/ping", http.PongHandler)
 
+	// Prometheus metrics
+	router.GET("/metrics", metrics.Handler())
+
 	router.POST("/", handler.PostNovelty)
 
 	if err := router.Run(); err != nil {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// loadGasConfig reads the gas estimation margin and fee ceilings from environment variables
+func loadGasConfig(log *zap.SugaredLogger) blockchain.GasConfig {
+	config := blockchain.DefaultGasConfig()
+
+	if value := os.Getenv("GAS_LIMIT_MULTIPLIER"); value != "" {
+		multiplier, err := strconv.ParseFloat(value, 64)
+		if err != nil || multiplier < 1 {
+			log.Fatalw("Invalid GAS_LIMIT_MULTIPLIER, must be a number >= 1",
+				"value", value,
+			)
+		}
+		config.GasLimitMultiplier = multiplier
+	}
+
+	if value := os.Getenv("MAX_GAS_LIMIT"); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			log.Fatalw("Invalid MAX_GAS_LIMIT",
+				"value", value,
+				"error", err,
+			)
+		}
+		config.MaxGasLimit = limit
+	}
+
+	config.MaxFeePerGas = getEnvAsWei(log, "MAX_FEE_PER_GAS_WEI")
+	config.MaxTxFee = getEnvAsWei(log, "MAX_TX_FEE_WEI")
+
+	log.Infow("Gas configuration loaded",
+		"gas_limit_multiplier", config.GasLimitMultiplier,
+		"max_gas_limit", config.MaxGasLimit,
+		"max_fee_per_gas_wei", config.MaxFeePerGas,
+		"max_tx_fee_wei", config.MaxTxFee,
+	)
+
+	return config
+}
+
+// getEnvAsUint64 gets an environment variable as uint64 or returns a default value
+func getEnvAsUint64(log *zap.SugaredLogger, key string, defaultValue uint64) uint64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		log.Fatalw("Invalid unsigned integer",
+			"key", key,
+			"value", value,
+			"error", err,
+		)
+	}
+
+	return parsed
+}
+
+// getEnvAsWei parses an optional wei amount from an environment variable
+func getEnvAsWei(log *zap.SugaredLogger, key string) *big.Int {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 {
+		log.Fatalw("Invalid wei amount",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return amount
+}
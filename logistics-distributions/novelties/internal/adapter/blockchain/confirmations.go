@@ -0,0 +1,59 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// confirmationPollInterval is how often the chain head is checked while waiting for confirmations
+const confirmationPollInterval = 2 * time.Second
+
+// receiptSource is the subset of the Ethereum client used to follow a mined transaction
+type receiptSource interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// waitConfirmations blocks until the mined transaction is buried under the given number of
+// blocks. The receipt is fetched again once that depth is reached: a transaction moved to
+// another block by a reorganisation starts counting again from its new block, and one that
+// was removed is waited for until it is mined again or ctx expires.
+func waitConfirmations(ctx context.Context, client receiptSource, receipt *types.Receipt, confirmations uint64) (*types.Receipt, error) {
+	if confirmations == 0 {
+		return receipt, nil
+	}
+
+	txHash := receipt.TxHash
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if receipt == nil {
+			if current, err := client.TransactionReceipt(ctx, txHash); err == nil {
+				receipt = current
+			}
+		} else if head, err := client.BlockNumber(ctx); err == nil && head >= receipt.BlockNumber.Uint64()+confirmations {
+			current, err := client.TransactionReceipt(ctx, txHash)
+			switch {
+			case err == nil && current.BlockHash == receipt.BlockHash:
+				return current, nil
+			case err == nil:
+				receipt = current
+			case errors.Is(err, ethereum.NotFound):
+				receipt = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for confirmations: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -5,12 +5,18 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"novelties/internal/core/domain"
 	"novelties/internal/core/port/driven"
+	"novelties/pkg/metrics"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"novelties/internal/adapter/blockchain/binding"
@@ -24,6 +30,8 @@ type EthereumWriter struct {
 	privateKey      *ecdsa.PrivateKey
 	publicAddress   common.Address
 	chainID         *big.Int
+	gas             GasConfig
+	confirmations   uint64
 	logger          *zap.SugaredLogger
 }
 
@@ -32,6 +40,8 @@ func NewEthereumWriter(
 	rpcURL string,
 	contractAddress string,
 	privateKeyHex string,
+	gasConfig GasConfig,
+	confirmations uint64,
 	logger *zap.SugaredLogger,
 ) (*EthereumWriter, error) {
 	// Validate contract address
@@ -80,6 +90,8 @@ func NewEthereumWriter(
 		"contract_address", addr.Hex(),
 		"public_address", publicAddress.Hex(),
 		"chain_id", chainID.String(),
+		"gas_limit_multiplier", gasConfig.GasLimitMultiplier,
+		"confirmations", confirmations,
 	)
 
 	return &EthereumWriter{
@@ -89,6 +101,8 @@ func NewEthereumWriter(
 		privateKey:      privateKey,
 		publicAddress:   publicAddress,
 		chainID:         chainID,
+		gas:             gasConfig,
+		confirmations:   confirmations,
 		logger:          logger,
 	}, nil
 }
@@ -120,32 +134,135 @@ func (ew *EthereumWriter) GetBalance(ctx context.Context) (*big.Int, error) {
 	return balance, nil
 }
 
-// createTransactor creates a transactor with the current nonce and gas settings
+// createTransactor creates a transactor with the current nonce and fee settings.
+// The gas limit is left unset so that it is estimated for each call.
 func (ew *EthereumWriter) createTransactor(ctx context.Context) (*bind.TransactOpts, error) {
 	nonce, err := ew.client.PendingNonceAt(ctx, ew.publicAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %w", err)
 	}
 
-	gasPrice, err := ew.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
-	}
-
 	auth, err := bind.NewKeyedTransactorWithChainID(ew.privateKey, ew.chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transactor: %w", err)
 	}
 
+	if err := ew.gas.applyFees(ctx, ew.client, auth); err != nil {
+		return nil, err
+	}
+
 	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // in wei
-	auth.GasLimit = uint64(3000000) // in units
-	auth.GasPrice = gasPrice
+	auth.Value = big.NewInt(0) // in wei
 	auth.Context = ctx
 
 	return auth, nil
 }
 
+// transact sends a transaction calling the given contract method within a client span and counts the outcome
+func (ew *EthereumWriter) transact(ctx context.Context, method string, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "EthereumWriter."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attrMethod.String(method),
+			attrContractAddress.String(ew.contractAddress.Hex()),
+		),
+	)
+
+	tx, err := ew.signAndSend(ctx, send)
+	if err != nil {
+		metrics.TransactionFailed(metrics.TransactionFailedSend)
+		tracing.EndSpan(span, err)
+		return nil, err
+	}
+
+	metrics.TransactionSent(method)
+	traceSent(span, tx)
+	span.End()
+	return tx, nil
+}
+
+// signAndSend estimates the gas for a call, applies the safety margin and fee ceilings,
+// and then signs and sends the transaction
+func (ew *EthereumWriter) signAndSend(ctx context.Context, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	auth, err := ew.createTransactor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build and sign without sending so the binding estimates the gas
+	auth.NoSend = true
+	estimate, err := send(auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	gasLimit, err := ew.gas.gasLimit(estimate.Gas(), auth)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.NoSend = false
+	auth.GasLimit = gasLimit
+
+	tx, err := send(auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// waitMined waits for a sent transaction to be mined and buried under the configured
+// number of confirmations within a client span, and counts the outcome
+func (ew *EthereumWriter) waitMined(ctx context.Context, tx *types.Transaction) (receipt *types.Receipt, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "EthereumWriter.WaitMined",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrTxHash.String(tx.Hash().Hex())),
+	)
+	defer func() {
+		if err == nil {
+			traceReceipt(span, receipt)
+		}
+		tracing.EndSpan(span, err)
+	}()
+
+	receipt, err = bind.WaitMined(ctx, ew.client, tx)
+	if err != nil {
+		metrics.TransactionFailed(metrics.TransactionFailedTimeout)
+		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+
+	receipt, err = waitConfirmations(ctx, ew.client, receipt, ew.confirmations)
+	if err != nil {
+		metrics.TransactionFailed(metrics.TransactionFailedTimeout)
+		return nil, err
+	}
+
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		metrics.TransactionMined(receipt.GasUsed)
+	} else {
+		metrics.TransactionReverted(receipt.GasUsed)
+	}
+
+	return receipt, nil
+}
+
+// slaCheckOutcome returns the SLA status a mined SLA check moved the SLA to, from the
+// SLAStatusUpdated event in its receipt, or why it didn't change it
+func (ew *EthereumWriter) slaCheckOutcome(receipt *types.Receipt) string {
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return metrics.SLACheckReverted
+	}
+
+	for _, log := range receipt.Logs {
+		if event, err := ew.contract.ParseSLAStatusUpdated(*log); err == nil {
+			return strings.ToLower(domain.SLAStatus(event.NewStatus).String())
+		}
+	}
+
+	return metrics.SLACheckUnchanged
+}
+
 // AddContract adds a new contract to the blockchain
 func (ew *EthereumWriter) AddContract(ctx context.Context, contractID, path, customerID string) (*driven.TransactionReceipt, error) {
 	ew.logger.Infow("Adding contract to blockchain",
@@ -154,24 +271,22 @@ func (ew *EthereumWriter) AddContract(ctx context.Context, contractID, path, cus
 		"path", path,
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "addContract", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.AddContract(auth, contractID, path, customerID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := ew.contract.AddContract(auth, contractID, path, customerID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
-
 	ew.logger.Infow("Transaction sent to blockchain",
 		"tx_hash", tx.Hash().Hex(),
+		"gas_limit", tx.Gas(),
 	)
 
 	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	receipt, err := ew.waitMined(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		return nil, err
 	}
 
 	txReceipt := &driven.TransactionReceipt{
@@ -209,24 +324,22 @@ func (ew *EthereumWriter) AddSLA(ctx context.Context, contractID, slaID, name, d
 		"comparator", comparator,
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "addSLA", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.AddSLA(auth, contractID, slaID, name, description, target, comparator)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := ew.contract.AddSLA(auth, contractID, slaID, name, description, target, comparator)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
-
 	ew.logger.Infow("Transaction sent to blockchain",
 		"tx_hash", tx.Hash().Hex(),
+		"gas_limit", tx.Gas(),
 	)
 
 	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	receipt, err := ew.waitMined(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		return nil, err
 	}
 
 	txReceipt := &driven.TransactionReceipt{
@@ -262,24 +375,22 @@ func (ew *EthereumWriter) SetSLAStatus(ctx context.Context, contractID string, s
 		"status", status,
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "setSLAStatus", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.SetSLAStatus(auth, contractID, big.NewInt(int64(slaIndex)), status)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	tx, err := ew.contract.SetSLAStatus(auth, contractID, big.NewInt(int64(slaIndex)), status)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
-
 	ew.logger.Infow("Transaction sent to blockchain",
 		"tx_hash", tx.Hash().Hex(),
+		"gas_limit", tx.Gas(),
 	)
 
 	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	receipt, err := ew.waitMined(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		return nil, err
 	}
 
 	txReceipt := &driven.TransactionReceipt{
@@ -317,25 +428,26 @@ func (ew *EthereumWriter) CheckSLA(ctx context.Context, contractID string, slaID
 		"actual_value", actualValue.String(),
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "checkSLA", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.CheckSLA(auth, contractID, slaID, actualValue)
+	})
 	if err != nil {
+		metrics.SLACheck(metrics.SLACheckError)
 		return nil, err
 	}
 
-	tx, err := ew.contract.CheckSLA(auth, contractID, slaID, actualValue)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
-
 	ew.logger.Infow("Transaction sent to blockchain",
 		"tx_hash", tx.Hash().Hex(),
+		"gas_limit", tx.Gas(),
 	)
 
 	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	receipt, err := ew.waitMined(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		metrics.SLACheck(metrics.SLACheckError)
+		return nil, err
 	}
+	metrics.SLACheck(ew.slaCheckOutcome(receipt))
 
 	txReceipt := &driven.TransactionReceipt{
 		TxHash:      receipt.TxHash.Hex(),
@@ -0,0 +1,133 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrFeeCeilingExceeded is returned when a transaction would exceed a configured gas or fee ceiling
+var ErrFeeCeilingExceeded = errors.New("transaction fee ceiling exceeded")
+
+// GasConfig controls gas estimation and the fee ceilings enforced before a transaction is sent
+type GasConfig struct {
+	// GasLimitMultiplier is the safety margin applied to the estimated gas (1.2 = +20%)
+	GasLimitMultiplier float64
+	// MaxGasLimit rejects transactions whose gas limit is higher (0 = no ceiling)
+	MaxGasLimit uint64
+	// MaxFeePerGas is the highest fee cap (EIP-1559) or gas price (legacy) in wei (nil = no ceiling)
+	MaxFeePerGas *big.Int
+	// MaxTxFee is the highest total fee, gas limit times fee per gas, in wei (nil = no ceiling)
+	MaxTxFee *big.Int
+}
+
+// DefaultGasConfig returns a gas configuration with a 20% estimation margin and no ceilings
+func DefaultGasConfig() GasConfig {
+	return GasConfig{
+		GasLimitMultiplier: 1.2,
+	}
+}
+
+// feeSource is the subset of the Ethereum client used to price transactions
+type feeSource interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// applyFees prices the transactor with GasFeeCap/GasTipCap when the latest block has a
+// base fee (EIP-1559), and with a legacy gas price otherwise
+func (c GasConfig) applyFees(ctx context.Context, client feeSource, auth *bind.TransactOpts) error {
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block header: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		gasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+
+		if c.MaxFeePerGas != nil && gasPrice.Cmp(c.MaxFeePerGas) > 0 {
+			return fmt.Errorf("%w: gas price %s wei is above the maximum of %s wei",
+				ErrFeeCeilingExceeded, gasPrice, c.MaxFeePerGas)
+		}
+
+		auth.GasPrice = gasPrice
+		return nil
+	}
+
+	tipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+
+	// Leave room for the base fee to double before the transaction becomes unincludable
+	feeCap := new(big.Int).Add(tipCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	if c.MaxFeePerGas != nil && feeCap.Cmp(c.MaxFeePerGas) > 0 {
+		// The ceiling still has to cover the current base fee plus the tip
+		minimum := new(big.Int).Add(head.BaseFee, tipCap)
+		if minimum.Cmp(c.MaxFeePerGas) > 0 {
+			return fmt.Errorf("%w: base fee plus tip of %s wei is above the maximum fee per gas of %s wei",
+				ErrFeeCeilingExceeded, minimum, c.MaxFeePerGas)
+		}
+		feeCap = new(big.Int).Set(c.MaxFeePerGas)
+	}
+
+	auth.GasFeeCap = feeCap
+	auth.GasTipCap = tipCap
+	return nil
+}
+
+// gasLimit applies the safety margin to an estimated amount of gas and checks the ceilings
+func (c GasConfig) gasLimit(estimated uint64, auth *bind.TransactOpts) (uint64, error) {
+	multiplier := c.GasLimitMultiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	// The multiplier is applied as the decimal it was configured as: in floating point,
+	// 21000 * 1.1 is slightly above 23100 and would round up to 23101
+	margin, ok := new(big.Rat).SetString(strconv.FormatFloat(multiplier, 'f', -1, 64))
+	if !ok {
+		return 0, fmt.Errorf("invalid gas limit multiplier %v", multiplier)
+	}
+	product := new(big.Rat).Mul(new(big.Rat).SetUint64(estimated), margin)
+	quotient, remainder := new(big.Int).QuoRem(product.Num(), product.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if !quotient.IsUint64() {
+		return 0, fmt.Errorf("%w: gas limit %s overflows", ErrFeeCeilingExceeded, quotient)
+	}
+	limit := quotient.Uint64()
+
+	if c.MaxGasLimit > 0 && limit > c.MaxGasLimit {
+		return 0, fmt.Errorf("%w: gas limit %d is above the maximum of %d",
+			ErrFeeCeilingExceeded, limit, c.MaxGasLimit)
+	}
+
+	if c.MaxTxFee != nil {
+		feePerGas := auth.GasFeeCap
+		if feePerGas == nil {
+			feePerGas = auth.GasPrice
+		}
+
+		if feePerGas != nil {
+			fee := new(big.Int).Mul(feePerGas, new(big.Int).SetUint64(limit))
+			if fee.Cmp(c.MaxTxFee) > 0 {
+				return 0, fmt.Errorf("%w: maximum transaction fee %s wei is above the ceiling of %s wei",
+					ErrFeeCeilingExceeded, fee, c.MaxTxFee)
+			}
+		}
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attributes describing blockchain transactions
+const (
+	attrMethod          = attribute.Key("blockchain.method")
+	attrContractAddress = attribute.Key("blockchain.contract_address")
+	attrTxHash          = attribute.Key("blockchain.tx_hash")
+	attrNonce           = attribute.Key("blockchain.nonce")
+	attrGasLimit        = attribute.Key("blockchain.gas_limit")
+	attrBlockNumber     = attribute.Key("blockchain.block_number")
+	attrGasUsed         = attribute.Key("blockchain.gas_used")
+	attrStatus          = attribute.Key("blockchain.status")
+)
+
+// traceSent records a transaction accepted by the node on the span
+func traceSent(span trace.Span, tx *types.Transaction) {
+	span.SetAttributes(
+		attrTxHash.String(tx.Hash().Hex()),
+		attrNonce.Int64(int64(tx.Nonce())),
+		attrGasLimit.Int64(int64(tx.Gas())),
+	)
+}
+
+// traceReceipt records a mined transaction on the span, marking it failed when it reverted
+func traceReceipt(span trace.Span, receipt *types.Receipt) {
+	span.SetAttributes(
+		attrBlockNumber.Int64(receipt.BlockNumber.Int64()),
+		attrGasUsed.Int64(int64(receipt.GasUsed)),
+		attrStatus.Int64(int64(receipt.Status)),
+	)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		span.SetStatus(codes.Error, "transaction reverted")
+	}
+}
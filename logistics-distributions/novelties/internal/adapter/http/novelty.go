@@ -29,7 +29,7 @@ func (h *NoveltyHandler) PostNovelty(c *gin.Context) {
 		return
 	}
 
-	receipt, err := h.writer.CheckSLA(c, payload.ContractID, payload.SLAID, payload.Value)
+	receipt, err := h.writer.CheckSLA(c.Request.Context(), payload.ContractID, payload.SLAID, payload.Value)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Errorf("failed to check SLA to blockchain: %w", err)})
 		return
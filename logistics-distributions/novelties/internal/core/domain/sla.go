@@ -20,6 +20,21 @@ const (
 	Inactive
 )
 
+// String returns the name of the SLA status
+func (s SLAStatus) String() string {
+	switch s {
+	case Active:
+		return "Active"
+	case Violated:
+		return "Violated"
+	case Compliant:
+		return "Compliant"
+	case Inactive:
+		return "Inactive"
+	}
+	return "Unknown"
+}
+
 type SLA struct {
 	ID          string     `json:"id" abi:"id"`
 	Name        string     `json:"name" abi:"name"`
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reasons a blockchain transaction failed
+const (
+	TransactionFailedSend     = "send"     // rejected by the node, or could not be signed or estimated
+	TransactionFailedReverted = "reverted" // mined with a failed status
+	TransactionFailedDropped  = "dropped"  // disappeared from the node's pool
+	TransactionFailedTimeout  = "timeout"  // not mined or confirmed in time
+)
+
+var (
+	transactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blockchain_transactions_sent_total",
+		Help: "Transactions accepted by the node, by contract method.",
+	}, []string{"method"})
+
+	transactionsMined = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "blockchain_transactions_mined_total",
+		Help: "Transactions mined successfully.",
+	})
+
+	transactionsFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blockchain_transactions_failed_total",
+		Help: "Transactions that failed, by reason (send, reverted, dropped or timeout).",
+	}, []string{"reason"})
+
+	transactionGasUsed = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "blockchain_transaction_gas_used",
+		Help:    "Gas used by mined transactions, by status (success or reverted).",
+		Buckets: prometheus.ExponentialBuckets(25000, 2, 8),
+	}, []string{"status"})
+)
+
+// TransactionSent counts a transaction accepted by the node
+func TransactionSent(method string) {
+	transactionsSent.WithLabelValues(method).Inc()
+}
+
+// TransactionMined counts a transaction mined successfully and records its gas
+func TransactionMined(gasUsed uint64) {
+	transactionsMined.Inc()
+	transactionGasUsed.WithLabelValues("success").Observe(float64(gasUsed))
+}
+
+// TransactionReverted counts a transaction mined with a failed status and records its gas
+func TransactionReverted(gasUsed uint64) {
+	transactionsFailed.WithLabelValues(TransactionFailedReverted).Inc()
+	transactionGasUsed.WithLabelValues("reverted").Observe(float64(gasUsed))
+}
+
+// TransactionFailed counts a transaction that failed before being mined
+func TransactionFailed(reason string) {
+	transactionsFailed.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Outcomes of an SLA check besides the status the SLA was moved to
+const (
+	SLACheckUnchanged = "unchanged" // mined without changing the SLA status
+	SLACheckReverted  = "reverted"  // mined with a failed status
+	SLACheckError     = "error"     // not sent, or not mined in time
+)
+
+var slaChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sla_checks_total",
+	Help: "SLA checks sent to the blockchain, by outcome (the new SLA status, unchanged, reverted or error).",
+}, []string{"outcome"})
+
+// SLACheck counts an SLA check with the given outcome
+func SLACheck(outcome string) {
+	slaChecks.WithLabelValues(outcome).Inc()
+}
@@ -0,0 +1,55 @@
+// Package amqptrace carries the trace context in the headers of AMQP messages.
+package amqptrace
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+)
+
+// amqpHeaderCarrier adapts AMQP message headers to the OpenTelemetry TextMapCarrier interface
+type amqpHeaderCarrier amqp.Table
+
+// Get returns the header value with the given key as a string
+func (c amqpHeaderCarrier) Get(key string) string {
+	value, ok := c[key]
+	if !ok {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// Set stores the header value with the given key
+func (c amqpHeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys returns the keys of all headers
+func (c amqpHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Inject writes the trace context of ctx into the message headers, creating them if needed
+func Inject(ctx context.Context, msg *amqp.Publishing) {
+	if msg.Headers == nil {
+		msg.Headers = amqp.Table{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, amqpHeaderCarrier(msg.Headers))
+}
+
+// Extract returns ctx carrying the trace context found in the delivery headers
+func Extract(ctx context.Context, msg *amqp.Delivery) context.Context {
+	if msg.Headers == nil {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, amqpHeaderCarrier(msg.Headers))
+}
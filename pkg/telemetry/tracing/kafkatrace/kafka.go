@@ -0,0 +1,54 @@
+// Package kafkatrace carries the trace context in the headers of Kafka messages.
+package kafkatrace
+
+import (
+	"context"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.opentelemetry.io/otel"
+)
+
+// kafkaHeaderCarrier adapts Kafka message headers to the OpenTelemetry TextMapCarrier interface
+type kafkaHeaderCarrier struct {
+	headers *[]kafka.Header
+}
+
+// Get returns the value of the last header with the given key
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for i := len(*c.headers) - 1; i >= 0; i-- {
+		if (*c.headers)[i].Key == key {
+			return string((*c.headers)[i].Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the headers with the given key by a single one holding the value
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	headers := (*c.headers)[:0]
+	for _, header := range *c.headers {
+		if header.Key != key {
+			headers = append(headers, header)
+		}
+	}
+	*c.headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of all headers
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, header := range *c.headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
+
+// Inject writes the trace context of ctx into the message headers
+func Inject(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, kafkaHeaderCarrier{headers: &msg.Headers})
+}
+
+// Extract returns ctx carrying the trace context found in the message headers
+func Extract(ctx context.Context, msg *kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, kafkaHeaderCarrier{headers: &msg.Headers})
+}
@@ -2,15 +2,28 @@ package main
 
 import (
 	"context"
+	"contracts/internal/adapter/outbox"
 	"contracts/internal/adapter/queue"
+	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/adapter/storage/postgres"
 	"contracts/internal/core/application"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
 	"contracts/pkg/logger"
 	"errors"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 	"os"
 	"os/signal"
+	"procurement-supply/pkg/events"
+	"procurement-supply/pkg/retry"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -26,6 +39,28 @@ func main() {
 	kafkaHost := getEnv("KAFKA_HOST", "localhost:9092")
 	kafkaGroupId := getEnv("KAFKA_GROUP_ID", "contracts-service")
 	kafkaTopics := getEnv("KAFKA_TOPICS", "medicine-events")
+	metricsAddr := getEnv("METRICS_ADDR", ":9090")
+	contractEventsTopic := getEnv("KAFKA_CONTRACT_EVENTS_TOPIC", "contract-events")
+	storageDriver := getEnv("STORAGE_DRIVER", "memory")
+	databaseURL := os.Getenv("DATABASE_URL")
+	driftThreshold := getEnvAsFloat(log, "PRICE_DRIFT_THRESHOLD", 0.1)
+	eventFormat := getEnv("EVENT_FORMAT", events.FormatJSON)
+	schemaRegistryURL := os.Getenv("SCHEMA_REGISTRY_URL")
+	replayCatalog := getEnvAsBool(log, "MEDICINE_CATALOG_REPLAY", false)
+	relayConfig := outbox.RelayConfig{
+		PollInterval:   getEnvAsDuration(log, "OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:      getEnvAsInt(log, "OUTBOX_BATCH_SIZE", 100),
+		PublishTimeout: getEnvAsDuration(log, "OUTBOX_PUBLISH_TIMEOUT", 30*time.Second),
+		MaxBackoff:     getEnvAsDuration(log, "OUTBOX_MAX_BACKOFF", time.Minute),
+	}
+
+	// Retry policy for messages that fail processing
+	retryPolicy := retry.Policy{
+		Attempts:        getEnvAsInt(log, "KAFKA_RETRY_ATTEMPTS", 3),
+		Backoff:         getEnvAsDuration(log, "KAFKA_RETRY_BACKOFF", 200*time.Millisecond),
+		RetryDelays:     getEnvAsDurations(log, "KAFKA_RETRY_DELAYS", []time.Duration{30 * time.Second, 5 * time.Minute}),
+		DeadLetterTopic: getEnv("KAFKA_DLQ_TOPIC", ""),
+	}
 
 	// Get configuration from environment variables with defaults
 	config := kafka.ConfigMap{
@@ -33,6 +68,9 @@ func main() {
 		"group.id":           kafkaGroupId,
 		"auto.offset.reset":  "earliest",
 		"enable.auto.commit": false, // Manual commit for better control
+
+		// Retry and dead-letter topics are created by the first message forwarded to them
+		"allow.auto.create.topics": true,
 	}
 
 	log.Infow("Kafka configuration loaded",
@@ -41,21 +79,162 @@ func main() {
 		"topics", kafkaTopics,
 		"auto_offset_reset", "earliest",
 		"auto_commit", false,
+		"retry_attempts", retryPolicy.Attempts,
+		"retry_backoff", retryPolicy.Backoff,
+		"retry_delays", retryPolicy.RetryDelays,
+		"dlq_topic", retryPolicy.DeadLetterTopic,
+		"contract_events_topic", contractEventsTopic,
+		"storage_driver", storageDriver,
+		"price_drift_threshold", driftThreshold,
+		"event_format", eventFormat,
+		"schema_registry_url", schemaRegistryURL,
+		"medicine_catalog_replay", replayCatalog,
+		"outbox_poll_interval", relayConfig.PollInterval,
+		"outbox_batch_size", relayConfig.BatchSize,
 	)
 
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "contracts-consumer")
+	if err != nil {
+		log.Fatalw("Failed to initialize tracing",
+			"error", err,
+		)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Errorw("Failed to flush traces",
+				"error", err,
+			)
+		}
+	}()
+
+	// Initialize the contract repository shared with the web API, its outbox of contract line events
+	// and the local medicine catalog (driven adapters)
+	var contractRepo driven.ContractRepository
+	var eventOutbox driven.ContractEventOutbox
+	var medicineRepo driven.Repository[string, domain.Medicine]
+
+	switch storageDriver {
+	case "postgres":
+		// The web API applies the schema migrations
+		db, err := postgres.Open(context.Background(), databaseURL)
+		if err != nil {
+			log.Fatalw("Failed to connect to PostgreSQL",
+				"error", err,
+			)
+		}
+		defer db.Close()
+
+		repository := postgres.NewContractRepository(db)
+		contractRepo = repository
+		eventOutbox = repository
+		medicineRepo = postgres.NewMedicineRepository(db)
+	case "memory":
+		log.Warn("Using in-memory storage, contract lines managed by the web API are not visible")
+		repository := memory.NewContractRepository()
+		contractRepo = repository
+		eventOutbox = repository
+		medicineRepo = memory.NewMedicineRepository()
+	default:
+		log.Fatalw("Unsupported storage driver",
+			"storage_driver", storageDriver,
+		)
+	}
+
+	// Events are read in the format named by their content type header, and contract line
+	// events are written in the configured one
+	schemaRegistry, err := events.NewRegistry(schemaRegistryURL)
+	if err != nil {
+		log.Fatalw("Failed to create schema registry client",
+			"error", err,
+		)
+	}
+	encoder, err := events.NewEncoder(eventFormat, schemaRegistry, queue.ContractLineEventSchema)
+	if err != nil {
+		log.Fatalw("Failed to create event encoder",
+			"error", err,
+		)
+	}
+
+	// Create the publisher announcing contract line changes (driven adapter)
+	publisher, err := queue.NewKafkaEventPublisher(kafkaHost, contractEventsTopic, encoder)
+	if err != nil {
+		log.Fatalw("Failed to create Kafka publisher",
+			"error", err,
+		)
+	}
+	defer publisher.Close()
+
 	// Create application service (business logic layer)
 	log.Info("Initializing application service...")
-	eventService := application.NewMedicineEventService()
+	eventService := application.NewMedicineEventService(contractRepo, medicineRepo, driftThreshold)
+
+	// An empty catalog, on first start or with memory storage, is always rebuilt
+	if !replayCatalog {
+		medicines, err := medicineRepo.FindAll()
+		if err != nil {
+			log.Fatalw("Failed to read the medicine catalog",
+				"error", err,
+			)
+		}
+		replayCatalog = len(medicines) == 0
+	}
+
+	// Rebuild the medicine catalog before joining the consumer group, with a consumer of its own
+	// reading every partition without touching the offsets of the group
+	if replayCatalog {
+		log.Info("Rebuilding the medicine catalog from the earliest offset...")
+		replayer, err := queue.NewCatalogReplayer(kafkaHost, kafkaGroupId+"-replay", []string{kafkaTopics}, eventService, events.NewDecoder(schemaRegistry))
+		if err != nil {
+			log.Fatalw("Failed to create catalog replayer",
+				"error", err,
+			)
+		}
+
+		replayCtx, stopReplay := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err = replayer.Replay(replayCtx)
+		stopReplay()
+		if errors.Is(err, context.Canceled) {
+			log.Info("Shutdown signal received while rebuilding the medicine catalog")
+			return
+		}
+		if err != nil {
+			log.Fatalw("Failed to rebuild the medicine catalog",
+				"error", err,
+			)
+		}
+	}
 
 	// Create Kafka consumer adapter (infrastructure layer)
 	log.Info("Initializing Kafka consumer...")
-	consumer, err := queue.NewKafkaEventConsumer(config, []string{kafkaTopics}, eventService)
+	consumer, err := queue.NewKafkaEventConsumer(config, []string{kafkaTopics}, eventService, events.NewDecoder(schemaRegistry), retryPolicy)
 	if err != nil {
 		log.Fatalw("Failed to create Kafka consumer",
 			"error", err,
 		)
 	}
 
+	// Publish the contract line events of the outbox. Consumers sharing a database claim distinct events.
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		outbox.NewRelay(eventOutbox, publisher, relayConfig).Run(relayCtx)
+	}()
+
+	// Serve Prometheus metrics
+	go func() {
+		log.Infow("Starting metrics server",
+			"address", metricsAddr,
+		)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Errorw("Metrics server stopped",
+				"error", err,
+			)
+		}
+	}()
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -92,6 +271,10 @@ func main() {
 		)
 	}
 
+	// Stop the relay once no more events are written to the outbox, before the deferred Close of the publisher
+	stopRelay()
+	<-relayDone
+
 	log.Info("Contracts Consumer Service stopped successfully")
 }
 
@@ -102,3 +285,101 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvAsInt gets an environment variable as a positive integer or returns a default value
+func getEnvAsInt(log *zap.SugaredLogger, key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Fatalw("Invalid positive integer",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return parsed
+}
+
+// getEnvAsDuration gets an environment variable as a duration or returns a default value
+func getEnvAsDuration(log *zap.SugaredLogger, key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalw("Invalid duration",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return duration
+}
+
+// getEnvAsFloat gets an environment variable as a non-negative number or returns a default value
+func getEnvAsFloat(log *zap.SugaredLogger, key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		log.Fatalw("Invalid non-negative number",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return parsed
+}
+
+// getEnvAsBool gets an environment variable as a boolean or returns a default value
+func getEnvAsBool(log *zap.SugaredLogger, key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalw("Invalid boolean",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return parsed
+}
+
+// getEnvAsDurations gets an environment variable as a comma-separated list of durations or
+// returns a default value. "none" yields an empty list.
+func getEnvAsDurations(log *zap.SugaredLogger, key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if value == "none" {
+		return nil
+	}
+
+	durations := make([]time.Duration, 0)
+	for _, part := range strings.Split(value, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || duration <= 0 {
+			log.Fatalw("Invalid duration list",
+				"key", key,
+				"value", value,
+			)
+		}
+		durations = append(durations, duration)
+	}
+
+	return durations
+}
@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"contracts/internal/adapter/blockchain"
 	"contracts/internal/adapter/http"
 	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/adapter/storage/postgres"
+	"contracts/internal/adapter/webhook"
 	"contracts/internal/core/application"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
 	"contracts/pkg/logger"
+	"math/big"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -25,14 +36,39 @@ func main() {
 	rcpURL := os.Getenv("RCP_URL")
 	smartContractAddress := os.Getenv("SMART_CONTRACT_ADDRESS")
 	privateKey := os.Getenv("PRIVATE_KEY")
+	storageDriver := getEnv("STORAGE_DRIVER", "memory")
+	databaseURL := os.Getenv("DATABASE_URL")
+	webhookURL := os.Getenv("TRANSACTION_WEBHOOK_URL")
+	confirmationTimeout := getEnvAsDuration(log, "TX_CONFIRMATION_TIMEOUT", 10*time.Minute)
 
 	log.Infow("Configuration loaded",
 		"rcp_url", rcpURL,
 		"contract_address", smartContractAddress,
+		"storage_driver", storageDriver,
+		"transaction_webhook_enabled", webhookURL != "",
+		"tx_confirmation_timeout", confirmationTimeout.String(),
 	)
 
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "contracts")
+	if err != nil {
+		log.Fatalw("Failed to initialize tracing",
+			"error", err,
+		)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Errorw("Failed to flush traces",
+				"error", err,
+			)
+		}
+	}()
+
+	gasConfig := loadGasConfig(log)
+	confirmations := getEnvAsUint64(log, "CONFIRMATION_DEPTH", 0)
+
 	// Initialize blockchain writer (for state-changing operations)
-	blockchainWriter, err := blockchain.NewEthereumWriter(rcpURL, smartContractAddress, privateKey, log)
+	blockchainWriter, err := blockchain.NewEthereumWriter(rcpURL, smartContractAddress, privateKey, gasConfig, confirmations, log)
 	if err != nil {
 		log.Fatalw("Failed to create blockchain writer",
 			"error", err,
@@ -50,31 +86,100 @@ func main() {
 	defer blockchainReader.Close()
 
 	// Initialize repositories (driven adapters)
-	contractRepo := memory.NewContractRepository()
-	customerRepo := memory.NewCustomerRepository()
-	slaRepo := memory.NewSLARepository()
+	var (
+		contractRepo driven.ContractRepository
+		customerRepo driven.Repository[string, domain.Customer]
+		slaRepo      driven.Repository[string, domain.SLA]
+		txRepo       driven.TransactionRepository
+		medicineRepo driven.Repository[string, domain.Medicine]
+	)
+
+	switch storageDriver {
+	case "postgres":
+		db, err := postgres.Open(context.Background(), databaseURL)
+		if err != nil {
+			log.Fatalw("Failed to connect to PostgreSQL",
+				"error", err,
+			)
+		}
+		defer db.Close()
+
+		if err := postgres.Migrate(context.Background(), db); err != nil {
+			log.Fatalw("Failed to apply database migrations",
+				"error", err,
+			)
+		}
+		log.Info("PostgreSQL storage initialized")
+
+		contractRepo = postgres.NewContractRepository(db)
+		customerRepo = postgres.NewCustomerRepository(db)
+		slaRepo = postgres.NewSLARepository(db)
+		txRepo = postgres.NewTransactionRepository(db)
+		medicineRepo = postgres.NewMedicineRepository(db)
+	case "memory":
+		contractRepo = memory.NewContractRepository()
+		customerRepo = memory.NewCustomerRepository()
+		slaRepo = memory.NewSLARepository()
+		txRepo = memory.NewTransactionRepository()
+		log.Warn("Using in-memory storage, the medicine catalog kept by the consumer is not visible: use postgres to serve /medicines and check contract lines")
+		medicineRepo = memory.NewMedicineRepository()
+	default:
+		log.Fatalw("Unsupported storage driver",
+			"storage_driver", storageDriver,
+		)
+	}
+
+	// Initialize the optional webhook notified when a transaction reaches a final state
+	var txNotifier driven.TransactionNotifier
+	if webhookURL != "" {
+		txNotifier = webhook.NewTransactionNotifier(webhookURL, 10*time.Second)
+	}
 
 	// Initialize application services (business logic)
-	contractService := application.NewContractService(contractRepo, blockchainWriter, blockchainReader)
+	transactionService := application.NewTransactionService(txRepo, blockchainWriter, txNotifier, confirmationTimeout)
+	// Contract lines are only checked against the catalog kept by the consumer, which the memory
+	// storage does not share
+	var lineCatalog driven.Repository[string, domain.Medicine]
+	if storageDriver == "postgres" {
+		lineCatalog = medicineRepo
+	}
+	contractService := application.NewContractService(contractRepo, lineCatalog, blockchainWriter, blockchainReader, transactionService)
 	customerService := application.NewCustomerService(customerRepo)
 	slaService := application.NewSLAService(slaRepo, blockchainWriter, blockchainReader)
+	medicineService := application.NewMedicineService(medicineRepo)
+
+	// Follow again the transactions sent before the last restart
+	if err := transactionService.Resume(context.Background()); err != nil {
+		log.Fatalw("Failed to resume pending transactions",
+			"error", err,
+		)
+	}
 
 	// Initialize HTTP handlers (driver adapters)
 	contractHandler := http.NewContractHandler(contractService)
 	customerHandler := http.NewCustomerHandler(customerService)
 	slaHandler := http.NewSLAHandler(slaService)
+	transactionHandler := http.NewTransactionHandler(transactionService)
+	medicineHandler := http.NewMedicineHandler(medicineService)
 
 	// Setup router with custom middleware
 	router := gin.New()
 
+	// Add tracing middleware continuing the caller's W3C trace context
+	router.Use(tracing.GinMiddleware())
 	// Add Zap logger middleware for structured request logging
 	router.Use(logger.GinLogger(log))
 	// Add recovery middleware with Zap logging
 	router.Use(logger.GinRecovery(log))
+	// Add Prometheus request metrics by route template
+	router.Use(metrics.GinMiddleware())
 
 	// Health check
 	router.GET("/ping", http.PongHandler)
 
+	// Prometheus metrics
+	router.GET("/metrics", metrics.Handler())
+
 	// Contract routes
 	router.GET("", contractHandler.GetContracts)
 	router.GET("/:id", contractHandler.GetContract)
@@ -104,6 +209,19 @@ func main() {
 		slaRoutes.DELETE("/:id", slaHandler.DeleteSLA)
 	}
 
+	// Transaction routes
+	transactionRoutes := router.Group("/transactions")
+	{
+		transactionRoutes.GET("/:hash", transactionHandler.GetTransaction)
+	}
+
+	// Medicine routes, served from the local copy of the catalog
+	medicineRoutes := router.Group("/medicines")
+	{
+		medicineRoutes.GET("", medicineHandler.GetMedicines)
+		medicineRoutes.GET("/:id", medicineHandler.GetMedicine)
+	}
+
 	// Start server
 	log.Infow("Starting HTTP server",
 		"port", "8080",
@@ -115,3 +233,104 @@ func main() {
 		)
 	}
 }
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsDuration gets an environment variable as a duration or returns a default value
+func getEnvAsDuration(log *zap.SugaredLogger, key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalw("Invalid duration",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return duration
+}
+
+// loadGasConfig reads the gas estimation margin and fee ceilings from environment variables
+func loadGasConfig(log *zap.SugaredLogger) blockchain.GasConfig {
+	config := blockchain.DefaultGasConfig()
+
+	if value := os.Getenv("GAS_LIMIT_MULTIPLIER"); value != "" {
+		multiplier, err := strconv.ParseFloat(value, 64)
+		if err != nil || multiplier < 1 {
+			log.Fatalw("Invalid GAS_LIMIT_MULTIPLIER, must be a number >= 1",
+				"value", value,
+			)
+		}
+		config.GasLimitMultiplier = multiplier
+	}
+
+	if value := os.Getenv("MAX_GAS_LIMIT"); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			log.Fatalw("Invalid MAX_GAS_LIMIT",
+				"value", value,
+				"error", err,
+			)
+		}
+		config.MaxGasLimit = limit
+	}
+
+	config.MaxFeePerGas = getEnvAsWei(log, "MAX_FEE_PER_GAS_WEI")
+	config.MaxTxFee = getEnvAsWei(log, "MAX_TX_FEE_WEI")
+
+	log.Infow("Gas configuration loaded",
+		"gas_limit_multiplier", config.GasLimitMultiplier,
+		"max_gas_limit", config.MaxGasLimit,
+		"max_fee_per_gas_wei", config.MaxFeePerGas,
+		"max_tx_fee_wei", config.MaxTxFee,
+	)
+
+	return config
+}
+
+// getEnvAsUint64 gets an environment variable as uint64 or returns a default value
+func getEnvAsUint64(log *zap.SugaredLogger, key string, defaultValue uint64) uint64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		log.Fatalw("Invalid unsigned integer",
+			"key", key,
+			"value", value,
+			"error", err,
+		)
+	}
+
+	return parsed
+}
+
+// getEnvAsWei parses an optional wei amount from an environment variable
+func getEnvAsWei(log *zap.SugaredLogger, key string) *big.Int {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 {
+		log.Fatalw("Invalid wei amount",
+			"key", key,
+			"value", value,
+		)
+	}
+
+	return amount
+}
@@ -3,35 +3,68 @@ package blockchain
 import (
 	"context"
 	"contracts/internal/core/port/driven"
+	"contracts/pkg/metrics"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"contracts/internal/adapter/blockchain/binding"
 )
 
+// writerBackend is the subset of the Ethereum client used by the writer, also implemented by
+// the simulated backend in tests
+type writerBackend interface {
+	bind.ContractBackend
+	ethereum.ChainStateReader
+	ethereum.TransactionReader
+	receiptSource
+}
+
 // EthereumWriter is an adapter for writing to the blockchain
 type EthereumWriter struct {
 	client          *ethclient.Client
+	backend         writerBackend
 	contract        *binding.SLAEnforcer
 	contractAddress common.Address
 	privateKey      *ecdsa.PrivateKey
 	publicAddress   common.Address
 	chainID         *big.Int
+	nonces          *NonceManager
+	gas             GasConfig
+	confirmations   uint64
 	logger          *zap.SugaredLogger
 }
 
+const (
+	// maxNonceRetries is how many times a transaction is re-signed after the node rejects its nonce
+	// as too low or too high
+	maxNonceRetries = 3
+	// receiptPollInterval is how often the node is asked for the receipt of a sent transaction
+	receiptPollInterval = 2 * time.Second
+	// droppedAfterPolls is how many consecutive polls a transaction may be unknown to the node
+	// before it is considered dropped
+	droppedAfterPolls = 5
+)
+
 // NewEthereumWriter creates a new EthereumWriter instance
 func NewEthereumWriter(
 	rpcURL string,
 	contractAddress string,
 	privateKeyHex string,
+	gasConfig GasConfig,
+	confirmations uint64,
 	logger *zap.SugaredLogger,
 ) (*EthereumWriter, error) {
 	// Validate contract address
@@ -80,15 +113,21 @@ func NewEthereumWriter(
 		"contract_address", addr.Hex(),
 		"public_address", publicAddress.Hex(),
 		"chain_id", chainID.String(),
+		"gas_limit_multiplier", gasConfig.GasLimitMultiplier,
+		"confirmations", confirmations,
 	)
 
 	return &EthereumWriter{
 		client:          client,
+		backend:         client,
 		contract:        contract,
 		contractAddress: addr,
 		privateKey:      privateKey,
 		publicAddress:   publicAddress,
 		chainID:         chainID,
+		nonces:          NewNonceManager(client, publicAddress, logger),
+		gas:             gasConfig,
+		confirmations:   confirmations,
 		logger:          logger,
 	}, nil
 }
@@ -107,7 +146,7 @@ func (ew *EthereumWriter) GetAddress() string {
 
 // GetBalance returns the balance of the account
 func (ew *EthereumWriter) GetBalance(ctx context.Context) (*big.Int, error) {
-	balance, err := ew.client.BalanceAt(ctx, ew.publicAddress, nil)
+	balance, err := ew.backend.BalanceAt(ctx, ew.publicAddress, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
@@ -120,115 +159,223 @@ func (ew *EthereumWriter) GetBalance(ctx context.Context) (*big.Int, error) {
 	return balance, nil
 }
 
-// createTransactor creates a transactor with the current nonce and gas settings
-func (ew *EthereumWriter) createTransactor(ctx context.Context) (*bind.TransactOpts, error) {
-	nonce, err := ew.client.PendingNonceAt(ctx, ew.publicAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	gasPrice, err := ew.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
-	}
-
+// createTransactor creates a transactor with the given nonce and the current fee settings.
+// The gas limit is left unset so that it is estimated for each call.
+func (ew *EthereumWriter) createTransactor(ctx context.Context, nonce uint64) (*bind.TransactOpts, error) {
 	auth, err := bind.NewKeyedTransactorWithChainID(ew.privateKey, ew.chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transactor: %w", err)
 	}
 
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)      // in wei
-	auth.GasLimit = uint64(3000000) // in units
-	auth.GasPrice = gasPrice
+	if err := ew.gas.applyFees(ctx, ew.backend, auth); err != nil {
+		return nil, err
+	}
+
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.Value = big.NewInt(0) // in wei
 	auth.Context = ctx
 
 	return auth, nil
 }
 
-// AddContract adds a new contract to the blockchain
-func (ew *EthereumWriter) AddContract(ctx context.Context, contractID, path, customerID string) (*driven.TransactionReceipt, error) {
-	ew.logger.Infow("Adding contract to blockchain",
-		"contract_id", contractID,
-		"customer_id", customerID,
-		"path", path,
-	)
+// estimateGas builds and signs the transaction without sending it, letting the binding
+// estimate the gas, and returns the estimate with the configured safety margin applied
+func (ew *EthereumWriter) estimateGas(auth *bind.TransactOpts, send func(*bind.TransactOpts) (*types.Transaction, error)) (uint64, error) {
+	auth.NoSend = true
+	auth.GasLimit = 0
+	defer func() {
+		auth.NoSend = false
+	}()
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := send(auth)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
 	}
 
-	tx, err := ew.contract.AddContract(auth, contractID, path, customerID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
+	return ew.gas.gasLimit(tx.Gas(), auth)
+}
 
-	ew.logger.Infow("Transaction sent to blockchain",
-		"tx_hash", tx.Hash().Hex(),
+// transact sends a transaction calling the given contract method within a client span and counts the outcome
+func (ew *EthereumWriter) transact(ctx context.Context, method string, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "EthereumWriter."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attrMethod.String(method),
+			attrContractAddress.String(ew.contractAddress.Hex()),
+		),
 	)
 
-	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	tx, err := ew.sendWithNonce(ctx, send)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		metrics.TransactionFailed(metrics.TransactionFailedSend)
+		tracing.EndSpan(span, err)
+		return nil, err
 	}
 
-	txReceipt := &driven.TransactionReceipt{
-		TxHash:      receipt.TxHash.Hex(),
-		BlockNumber: receipt.BlockNumber.Uint64(),
-		Status:      receipt.Status,
-		GasUsed:     receipt.GasUsed,
-	}
+	metrics.TransactionSent(method)
+	traceSent(span, tx)
+	span.End()
+	return tx, nil
+}
 
-	if receipt.Status == 1 {
-		ew.logger.Infow("Contract added successfully to blockchain",
-			"contract_id", contractID,
-			"tx_hash", txReceipt.TxHash,
-			"block_number", txReceipt.BlockNumber,
-			"gas_used", txReceipt.GasUsed,
-		)
-	} else {
-		ew.logger.Errorw("Contract addition failed on blockchain",
-			"contract_id", contractID,
-			"tx_hash", txReceipt.TxHash,
-			"status", receipt.Status,
-		)
+// sendWithNonce signs a transaction with a nonce reserved from the nonce manager and sends it.
+// When the node rejects the nonce as too low or too high, the call is retried with another one.
+// A transaction the node already knows is treated as sent, while a different pending transaction
+// with the same nonce fails the call rather than sending the same write twice.
+func (ew *EthereumWriter) sendWithNonce(ctx context.Context, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	for attempt := 0; ; attempt++ {
+		nonce, err := ew.nonces.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := ew.signWithNonce(ctx, nonce, send)
+		if err != nil {
+			ew.nonces.Release(nonce)
+			return nil, err
+		}
+
+		err = ew.backend.SendTransaction(ctx, tx)
+		switch {
+		case err == nil:
+			ew.nonces.Track(nonce, tx.Hash())
+			return tx, nil
+
+		case isAlreadyKnown(err):
+			ew.logger.Infow("Transaction already known by node",
+				"tx_hash", tx.Hash().Hex(),
+				"nonce", nonce,
+			)
+			ew.nonces.Track(nonce, tx.Hash())
+			return tx, nil
+
+		case isNonceTaken(err):
+			// The nonce stays consumed by the pending transaction
+			ew.nonces.Reject(nonce)
+			return nil, fmt.Errorf("failed to send transaction: nonce %d is used by another pending transaction: %w", nonce, err)
+
+		case isNonceError(err):
+			ew.nonces.Reject(nonce)
+			if attempt >= maxNonceRetries {
+				return nil, fmt.Errorf("failed to send transaction: %w", err)
+			}
+
+			ew.logger.Warnw("Transaction nonce rejected by node, retrying",
+				"nonce", nonce,
+				"attempt", attempt+1,
+				"error", err,
+			)
+
+		default:
+			ew.nonces.Release(nonce)
+			return nil, fmt.Errorf("failed to send transaction: %w", err)
+		}
 	}
-
-	return txReceipt, nil
 }
 
-// AddSLA adds a new SLA to an existing contract
-func (ew *EthereumWriter) AddSLA(ctx context.Context, contractID, slaID, name, description string, target *big.Int, comparator uint8) (*driven.TransactionReceipt, error) {
-	ew.logger.Infow("Adding SLA to contract",
-		"contract_id", contractID,
-		"sla_id", slaID,
-		"name", name,
-		"target", target.String(),
-		"comparator", comparator,
-	)
+// signWithNonce builds and signs the transaction with the given nonce and an estimated gas
+// limit, without sending it
+func (ew *EthereumWriter) signWithNonce(ctx context.Context, nonce uint64, send func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	auth, err := ew.createTransactor(ctx, nonce)
+	if err != nil {
+		return nil, err
+	}
 
-	auth, err := ew.createTransactor(ctx)
+	gasLimit, err := ew.estimateGas(auth, send)
 	if err != nil {
 		return nil, err
 	}
+	auth.GasLimit = gasLimit
+	auth.NoSend = true
 
-	tx, err := ew.contract.AddSLA(auth, contractID, slaID, name, description, target, comparator)
+	tx, err := send(auth)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	ew.logger.Infow("Transaction sent to blockchain",
-		"tx_hash", tx.Hash().Hex(),
+	return tx, nil
+}
+
+// WaitForReceipt polls the node until the transaction is mined and buried under the configured
+// number of confirmations, then releases its in-flight slot. A transaction that is neither mined
+// nor in the node's pool for several consecutive polls is reported as dropped. The wait is traced
+// as a client span.
+func (ew *EthereumWriter) WaitForReceipt(ctx context.Context, txHash string) (*driven.TransactionReceipt, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "EthereumWriter.WaitForReceipt",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrTxHash.String(txHash)),
 	)
 
-	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	receipt, err := ew.waitForReceipt(ctx, txHash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
+		tracing.EndSpan(span, err)
+		return nil, err
+	}
+
+	traceReceipt(span, receipt)
+	span.End()
+	return ew.toReceipt(receipt), nil
+}
+
+// waitForReceipt polls the node for the receipt of a transaction until it has enough confirmations
+func (ew *EthereumWriter) waitForReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	hash := common.HexToHash(txHash)
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	missing := 0
+	for {
+		receipt, err := ew.backend.TransactionReceipt(ctx, hash)
+		if err == nil {
+			ew.nonces.Done(hash)
+
+			receipt, err = waitConfirmations(ctx, ew.backend, receipt, ew.confirmations)
+			if err != nil {
+				metrics.TransactionFailed(metrics.TransactionFailedTimeout)
+				return nil, err
+			}
+			return receipt, nil
+		}
+
+		if errors.Is(err, ethereum.NotFound) {
+			_, _, err = ew.backend.TransactionByHash(ctx, hash)
+			switch {
+			case errors.Is(err, ethereum.NotFound):
+				missing++
+			case err == nil:
+				missing = 0
+			}
+
+			if missing >= droppedAfterPolls {
+				ew.nonces.Dropped(hash)
+				metrics.TransactionFailed(metrics.TransactionFailedDropped)
+				ew.logger.Warnw("Transaction dropped by node",
+					"tx_hash", txHash,
+					"in_flight", ew.nonces.InFlight(),
+				)
+				return nil, fmt.Errorf("%w: %s", driven.ErrTransactionDropped, txHash)
+			}
+		}
+
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			ew.logger.Debugw("Failed to poll transaction, retrying",
+				"tx_hash", txHash,
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			metrics.TransactionFailed(metrics.TransactionFailedTimeout)
+			return nil, fmt.Errorf("failed to wait for transaction: %w", ctx.Err())
+		case <-ticker.C:
+		}
 	}
+}
 
+// toReceipt converts a go-ethereum receipt, and logs and counts the outcome
+func (ew *EthereumWriter) toReceipt(receipt *types.Receipt) *driven.TransactionReceipt {
 	txReceipt := &driven.TransactionReceipt{
 		TxHash:      receipt.TxHash.Hex(),
 		BlockNumber: receipt.BlockNumber.Uint64(),
@@ -237,129 +384,108 @@ func (ew *EthereumWriter) AddSLA(ctx context.Context, contractID, slaID, name, d
 	}
 
 	if receipt.Status == 1 {
-		ew.logger.Infow("SLA added successfully to blockchain",
-			"sla_id", slaID,
+		metrics.TransactionMined(receipt.GasUsed)
+		ew.logger.Infow("Transaction mined successfully",
 			"tx_hash", txReceipt.TxHash,
 			"block_number", txReceipt.BlockNumber,
 			"gas_used", txReceipt.GasUsed,
 		)
 	} else {
-		ew.logger.Errorw("SLA addition failed on blockchain",
-			"sla_id", slaID,
+		metrics.TransactionReverted(receipt.GasUsed)
+		ew.logger.Errorw("Transaction reverted on blockchain",
 			"tx_hash", txReceipt.TxHash,
-			"status", receipt.Status,
+			"block_number", txReceipt.BlockNumber,
+			"status", txReceipt.Status,
 		)
 	}
 
-	return txReceipt, nil
+	return txReceipt
 }
 
-// SetSLAStatus updates the status of an SLA
-func (ew *EthereumWriter) SetSLAStatus(ctx context.Context, contractID string, slaIndex uint64, status uint8) (*driven.TransactionReceipt, error) {
-	ew.logger.Infow("Setting SLA status",
+// logSent logs a transaction that was accepted by the node
+func (ew *EthereumWriter) logSent(tx *types.Transaction) {
+	ew.logger.Infow("Transaction sent to blockchain",
+		"tx_hash", tx.Hash().Hex(),
+		"nonce", tx.Nonce(),
+		"gas_limit", tx.Gas(),
+		"in_flight", ew.nonces.InFlight(),
+	)
+}
+
+// AddContract sends a transaction adding a new contract to the blockchain
+func (ew *EthereumWriter) AddContract(ctx context.Context, contractID, path, customerID string) (string, error) {
+	ew.logger.Infow("Adding contract to blockchain",
 		"contract_id", contractID,
-		"sla_index", slaIndex,
-		"status", status,
+		"customer_id", customerID,
+		"path", path,
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "addContract", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.AddContract(auth, contractID, path, customerID)
+	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	tx, err := ew.contract.SetSLAStatus(auth, contractID, big.NewInt(int64(slaIndex)), status)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
+	ew.logSent(tx)
+	return tx.Hash().Hex(), nil
+}
 
-	ew.logger.Infow("Transaction sent to blockchain",
-		"tx_hash", tx.Hash().Hex(),
+// AddSLA sends a transaction adding a new SLA to an existing contract
+func (ew *EthereumWriter) AddSLA(ctx context.Context, contractID, slaID, name, description string, target *big.Int, comparator uint8) (string, error) {
+	ew.logger.Infow("Adding SLA to contract",
+		"contract_id", contractID,
+		"sla_id", slaID,
+		"name", name,
+		"target", target.String(),
+		"comparator", comparator,
 	)
 
-	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	tx, err := ew.transact(ctx, "addSLA", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.AddSLA(auth, contractID, slaID, name, description, target, comparator)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
-	}
-
-	txReceipt := &driven.TransactionReceipt{
-		TxHash:      receipt.TxHash.Hex(),
-		BlockNumber: receipt.BlockNumber.Uint64(),
-		Status:      receipt.Status,
-		GasUsed:     receipt.GasUsed,
-	}
-
-	if receipt.Status == 1 {
-		ew.logger.Infow("SLA status updated successfully on blockchain",
-			"contract_id", contractID,
-			"sla_index", slaIndex,
-			"tx_hash", txReceipt.TxHash,
-			"block_number", txReceipt.BlockNumber,
-			"gas_used", txReceipt.GasUsed,
-		)
-	} else {
-		ew.logger.Errorw("SLA status update failed on blockchain",
-			"contract_id", contractID,
-			"sla_index", slaIndex,
-			"tx_hash", txReceipt.TxHash,
-			"status", receipt.Status,
-		)
+		return "", err
 	}
 
-	return txReceipt, nil
+	ew.logSent(tx)
+	return tx.Hash().Hex(), nil
 }
 
-// CheckSLA checks an SLA against an actual value
-func (ew *EthereumWriter) CheckSLA(ctx context.Context, contractID string, slaIndex uint64, actualValue *big.Int) (*driven.TransactionReceipt, error) {
-	ew.logger.Infow("Checking SLA",
+// SetSLAStatus sends a transaction updating the status of an SLA
+func (ew *EthereumWriter) SetSLAStatus(ctx context.Context, contractID string, slaIndex uint64, status uint8) (string, error) {
+	ew.logger.Infow("Setting SLA status",
 		"contract_id", contractID,
 		"sla_index", slaIndex,
-		"actual_value", actualValue.String(),
+		"status", status,
 	)
 
-	auth, err := ew.createTransactor(ctx)
+	tx, err := ew.transact(ctx, "setSLAStatus", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.SetSLAStatus(auth, contractID, big.NewInt(int64(slaIndex)), status)
+	})
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	tx, err := ew.contract.CheckSLA(auth, contractID, big.NewInt(int64(slaIndex)), actualValue)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
-	}
+	ew.logSent(tx)
+	return tx.Hash().Hex(), nil
+}
 
-	ew.logger.Infow("Transaction sent to blockchain",
-		"tx_hash", tx.Hash().Hex(),
+// CheckSLA sends a transaction checking an SLA against an actual value
+func (ew *EthereumWriter) CheckSLA(ctx context.Context, contractID string, slaIndex uint64, actualValue *big.Int) (string, error) {
+	ew.logger.Infow("Checking SLA",
+		"contract_id", contractID,
+		"sla_index", slaIndex,
+		"actual_value", actualValue.String(),
 	)
 
-	// Wait for transaction receipt
-	receipt, err := bind.WaitMined(ctx, ew.client, tx)
+	tx, err := ew.transact(ctx, "checkSLA", func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		return ew.contract.CheckSLA(auth, contractID, big.NewInt(int64(slaIndex)), actualValue)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction: %w", err)
-	}
-
-	txReceipt := &driven.TransactionReceipt{
-		TxHash:      receipt.TxHash.Hex(),
-		BlockNumber: receipt.BlockNumber.Uint64(),
-		Status:      receipt.Status,
-		GasUsed:     receipt.GasUsed,
-	}
-
-	if receipt.Status == 1 {
-		ew.logger.Infow("SLA checked successfully on blockchain",
-			"contract_id", contractID,
-			"sla_index", slaIndex,
-			"tx_hash", txReceipt.TxHash,
-			"block_number", txReceipt.BlockNumber,
-			"gas_used", txReceipt.GasUsed,
-		)
-	} else {
-		ew.logger.Errorw("SLA check failed on blockchain",
-			"contract_id", contractID,
-			"sla_index", slaIndex,
-			"tx_hash", txReceipt.TxHash,
-			"status", receipt.Status,
-		)
+		return "", err
 	}
 
-	return txReceipt, nil
+	ew.logSent(tx)
+	return tx.Hash().Hex(), nil
 }
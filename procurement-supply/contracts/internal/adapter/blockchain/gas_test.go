@@ -0,0 +1,244 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stubFeeSource prices transactions with fixed values
+type stubFeeSource struct {
+	baseFee  *big.Int // nil for a pre-London chain
+	gasPrice *big.Int
+	tipCap   *big.Int
+	err      error
+}
+
+func (s stubFeeSource) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &types.Header{BaseFee: s.baseFee}, nil
+}
+
+func (s stubFeeSource) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return s.gasPrice, nil
+}
+
+func (s stubFeeSource) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return s.tipCap, nil
+}
+
+func TestGasConfigApplyFees(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       GasConfig
+		source       stubFeeSource
+		wantErr      error
+		wantGasPrice *big.Int
+		wantFeeCap   *big.Int
+		wantTipCap   *big.Int
+	}{
+		{
+			name:         "legacy chain uses the suggested gas price",
+			source:       stubFeeSource{gasPrice: big.NewInt(20)},
+			wantGasPrice: big.NewInt(20),
+		},
+		{
+			name:         "legacy gas price at the ceiling",
+			config:       GasConfig{MaxFeePerGas: big.NewInt(20)},
+			source:       stubFeeSource{gasPrice: big.NewInt(20)},
+			wantGasPrice: big.NewInt(20),
+		},
+		{
+			name:    "legacy gas price above the ceiling",
+			config:  GasConfig{MaxFeePerGas: big.NewInt(19)},
+			source:  stubFeeSource{gasPrice: big.NewInt(20)},
+			wantErr: ErrFeeCeilingExceeded,
+		},
+		{
+			name:       "fee cap leaves room for the base fee to double",
+			source:     stubFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			wantFeeCap: big.NewInt(202),
+			wantTipCap: big.NewInt(2),
+		},
+		{
+			name:       "fee cap clamped to the ceiling",
+			config:     GasConfig{MaxFeePerGas: big.NewInt(150)},
+			source:     stubFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			wantFeeCap: big.NewInt(150),
+			wantTipCap: big.NewInt(2),
+		},
+		{
+			name:       "ceiling exactly covering base fee and tip",
+			config:     GasConfig{MaxFeePerGas: big.NewInt(102)},
+			source:     stubFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			wantFeeCap: big.NewInt(102),
+			wantTipCap: big.NewInt(2),
+		},
+		{
+			name:    "ceiling below base fee and tip",
+			config:  GasConfig{MaxFeePerGas: big.NewInt(101)},
+			source:  stubFeeSource{baseFee: big.NewInt(100), tipCap: big.NewInt(2)},
+			wantErr: ErrFeeCeilingExceeded,
+		},
+		{
+			name:    "tip above the ceiling",
+			config:  GasConfig{MaxFeePerGas: big.NewInt(5)},
+			source:  stubFeeSource{baseFee: big.NewInt(0), tipCap: big.NewInt(6)},
+			wantErr: ErrFeeCeilingExceeded,
+		},
+		{
+			name:       "zero base fee",
+			source:     stubFeeSource{baseFee: big.NewInt(0), tipCap: big.NewInt(3)},
+			wantFeeCap: big.NewInt(3),
+			wantTipCap: big.NewInt(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &bind.TransactOpts{}
+			err := tt.config.applyFees(context.Background(), tt.source, auth)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applyFees() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyFees() error = %v", err)
+			}
+
+			assertWei(t, "GasPrice", auth.GasPrice, tt.wantGasPrice)
+			assertWei(t, "GasFeeCap", auth.GasFeeCap, tt.wantFeeCap)
+			assertWei(t, "GasTipCap", auth.GasTipCap, tt.wantTipCap)
+
+			if auth.GasTipCap != nil && auth.GasTipCap.Cmp(auth.GasFeeCap) > 0 {
+				t.Errorf("tip cap %s above fee cap %s", auth.GasTipCap, auth.GasFeeCap)
+			}
+		})
+	}
+}
+
+func TestGasConfigApplyFeesHeaderError(t *testing.T) {
+	errNode := errors.New("node unavailable")
+	err := DefaultGasConfig().applyFees(context.Background(), stubFeeSource{err: errNode}, &bind.TransactOpts{})
+	if !errors.Is(err, errNode) {
+		t.Fatalf("applyFees() error = %v, want %v", err, errNode)
+	}
+}
+
+func TestGasConfigGasLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    GasConfig
+		estimated uint64
+		auth      bind.TransactOpts
+		want      uint64
+		wantErr   error
+	}{
+		{
+			name:      "default margin",
+			config:    DefaultGasConfig(),
+			estimated: 21000,
+			want:      25200,
+		},
+		{
+			name:      "margin rounded up",
+			config:    GasConfig{GasLimitMultiplier: 1.1},
+			estimated: 21001,
+			want:      23102,
+		},
+		{
+			name:      "exact decimal margin is not rounded up",
+			config:    GasConfig{GasLimitMultiplier: 1.1},
+			estimated: 21000,
+			want:      23100,
+		},
+		{
+			name:      "zero multiplier keeps the estimate",
+			config:    GasConfig{},
+			estimated: 21000,
+			want:      21000,
+		},
+		{
+			name:      "multiplier below one keeps the estimate",
+			config:    GasConfig{GasLimitMultiplier: 0.5},
+			estimated: 21000,
+			want:      21000,
+		},
+		{
+			name:      "gas limit at the ceiling",
+			config:    GasConfig{GasLimitMultiplier: 1, MaxGasLimit: 21000},
+			estimated: 21000,
+			want:      21000,
+		},
+		{
+			name:      "margin pushing the gas limit above the ceiling",
+			config:    GasConfig{GasLimitMultiplier: 1.2, MaxGasLimit: 25000},
+			estimated: 21000,
+			wantErr:   ErrFeeCeilingExceeded,
+		},
+		{
+			name:      "transaction fee from the fee cap",
+			config:    GasConfig{GasLimitMultiplier: 1, MaxTxFee: big.NewInt(21000 * 10)},
+			estimated: 21000,
+			auth:      bind.TransactOpts{GasFeeCap: big.NewInt(10), GasPrice: big.NewInt(1000)},
+			want:      21000,
+		},
+		{
+			name:      "transaction fee above the ceiling",
+			config:    GasConfig{GasLimitMultiplier: 1, MaxTxFee: big.NewInt(21000*10 - 1)},
+			estimated: 21000,
+			auth:      bind.TransactOpts{GasFeeCap: big.NewInt(10)},
+			wantErr:   ErrFeeCeilingExceeded,
+		},
+		{
+			name:      "legacy transaction fee above the ceiling",
+			config:    GasConfig{GasLimitMultiplier: 1, MaxTxFee: big.NewInt(100)},
+			estimated: 21000,
+			auth:      bind.TransactOpts{GasPrice: big.NewInt(1)},
+			wantErr:   ErrFeeCeilingExceeded,
+		},
+		{
+			name:      "transaction fee unknown without a price",
+			config:    GasConfig{GasLimitMultiplier: 1, MaxTxFee: big.NewInt(1)},
+			estimated: 21000,
+			want:      21000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.gasLimit(tt.estimated, &tt.auth)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("gasLimit() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("gasLimit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("gasLimit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// assertWei compares an optional amount of wei
+func assertWei(t *testing.T, name string, got, want *big.Int) {
+	t.Helper()
+
+	if (got == nil) != (want == nil) || (got != nil && got.Cmp(want) != 0) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,234 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// NonceSource is the subset of the Ethereum client used to read the account nonce
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces for a single account so that concurrent
+// writers never sign two transactions with the same nonce. Nonces that end up unused
+// are kept in a local free list and handed out again before new ones. The node is only
+// queried on the first use and after it rejected a nonce. Such a resync does not wait for
+// the other transactions to settle: the nonces still reserved or in flight are left out,
+// so none of them is handed out a second time.
+type NonceManager struct {
+	source  NonceSource
+	address common.Address
+	logger  *zap.SugaredLogger
+
+	mu       sync.Mutex
+	next     uint64
+	synced   bool
+	stale    bool
+	reserved map[uint64]struct{}
+	free     []uint64
+	inFlight map[common.Hash]uint64
+}
+
+// NewNonceManager creates a new nonce manager for the given account
+func NewNonceManager(source NonceSource, address common.Address, logger *zap.SugaredLogger) *NonceManager {
+	return &NonceManager{
+		source:   source,
+		address:  address,
+		logger:   logger,
+		reserved: make(map[uint64]struct{}),
+		inFlight: make(map[common.Hash]uint64),
+	}
+}
+
+// Acquire reserves the lowest free nonce. Every acquired nonce must be followed by
+// Track (the transaction was sent), Release (it was not) or Reject (the node refused it).
+func (nm *NonceManager) Acquire(ctx context.Context) (uint64, error) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	if !nm.synced || nm.stale {
+		if err := nm.syncLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(nm.free) > 0 {
+		nonce = nm.free[0]
+		nm.free = nm.free[1:]
+	} else {
+		nonce = nm.next
+		nm.next++
+	}
+
+	nm.reserved[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Track records a transaction that was accepted by the node with the given nonce
+func (nm *NonceManager) Track(nonce uint64, txHash common.Hash) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	delete(nm.reserved, nonce)
+	nm.inFlight[txHash] = nonce
+}
+
+// Release gives back a nonce whose transaction was never sent, so it is handed out again
+func (nm *NonceManager) Release(nonce uint64) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	delete(nm.reserved, nonce)
+	nm.freeLocked(nonce)
+}
+
+// Reject gives up a nonce the node refused, either as too low or too high or because another
+// pending transaction already uses it. The next Acquire resyncs with the node, which hands
+// the nonce out again only when the node has not consumed it.
+func (nm *NonceManager) Reject(nonce uint64) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	delete(nm.reserved, nonce)
+	nm.stale = true
+
+	nm.logger.Debugw("Nonce rejected by node",
+		"address", nm.address.Hex(),
+		"nonce", nonce,
+	)
+}
+
+// Done marks a mined transaction as no longer in flight
+func (nm *NonceManager) Done(txHash common.Hash) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	delete(nm.inFlight, txHash)
+}
+
+// Dropped forgets a transaction the node discarded. Its nonce was never consumed,
+// so it is handed out again to fill the gap.
+func (nm *NonceManager) Dropped(txHash common.Hash) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	nonce, ok := nm.inFlight[txHash]
+	if !ok {
+		return
+	}
+
+	delete(nm.inFlight, txHash)
+	nm.freeLocked(nonce)
+}
+
+// InFlight returns the number of sent transactions that have not been mined yet
+func (nm *NonceManager) InFlight() int {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	return len(nm.inFlight)
+}
+
+// freeLocked adds a nonce to the free list, or rewinds the next nonce when the free
+// nonces are the last ones handed out. The caller must hold nm.mu.
+func (nm *NonceManager) freeLocked(nonce uint64) {
+	if nonce >= nm.next {
+		return // handed out before the last resync
+	}
+
+	i, found := slices.BinarySearch(nm.free, nonce)
+	if !found {
+		nm.free = slices.Insert(nm.free, i, nonce)
+	}
+
+	nm.rewindLocked()
+}
+
+// rewindLocked moves the free nonces right below the next nonce back into it, so they are
+// handed out as new ones. The caller must hold nm.mu.
+func (nm *NonceManager) rewindLocked() {
+	for len(nm.free) > 0 && nm.free[len(nm.free)-1]+1 == nm.next {
+		nm.free = nm.free[:len(nm.free)-1]
+		nm.next--
+	}
+}
+
+// syncLocked reloads the pending nonce from the node. The nonces from there up to the next one
+// that are neither reserved nor in flight become free, so the lowest rejected nonce the node
+// has not consumed is handed out first. The caller must hold nm.mu.
+func (nm *NonceManager) syncLocked(ctx context.Context) error {
+	pending, err := nm.source.PendingNonceAt(ctx, nm.address)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	nm.logger.Debugw("Nonce synchronized with node",
+		"address", nm.address.Hex(),
+		"previous_nonce", nm.next,
+		"pending_nonce", pending,
+		"reserved", len(nm.reserved),
+		"in_flight", len(nm.inFlight),
+	)
+
+	sent := make(map[uint64]struct{}, len(nm.inFlight))
+	for _, nonce := range nm.inFlight {
+		sent[nonce] = struct{}{}
+	}
+
+	nm.free = nil
+	for nonce := pending; nonce < nm.next; nonce++ {
+		_, reserved := nm.reserved[nonce]
+		_, inFlight := sent[nonce]
+		if !reserved && !inFlight {
+			nm.free = append(nm.free, nonce)
+		}
+	}
+	nm.next = max(nm.next, pending)
+	nm.synced = true
+	nm.stale = false
+	nm.rewindLocked()
+
+	return nil
+}
+
+// RPC errors lose their Go type, so the node's messages are matched instead.
+
+// isNonceError reports whether the node rejected a transaction because its nonce is
+// below or above the account's pending nonce
+func isNonceError(err error) bool {
+	return errorContains(err, "nonce too low", "nonce too high")
+}
+
+// isAlreadyKnown reports whether the node already has this exact transaction in its pool
+func isAlreadyKnown(err error) bool {
+	return errorContains(err, "already known")
+}
+
+// isNonceTaken reports whether another transaction with the same nonce is already in the
+// node's pool
+func isNonceTaken(err error) bool {
+	return errorContains(err, "replacement transaction underpriced")
+}
+
+// errorContains reports whether the error message contains any of the given messages
+func errorContains(err error, messages ...string) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range messages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,346 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"go.uber.org/zap"
+)
+
+// stubNonceSource returns a fixed pending nonce and counts how often it was asked
+type stubNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+	calls   int
+}
+
+func (s *stubNonceSource) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	return s.pending, nil
+}
+
+func (s *stubNonceSource) syncs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func mustAcquire(t *testing.T, nm *NonceManager) uint64 {
+	t.Helper()
+
+	nonce, err := nm.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	return nonce
+}
+
+func TestNonceManagerReusesReleasedNonces(t *testing.T) {
+	source := &stubNonceSource{pending: 5}
+	nm := NewNonceManager(source, common.Address{}, zap.NewNop().Sugar())
+
+	first, second, third := mustAcquire(t, nm), mustAcquire(t, nm), mustAcquire(t, nm)
+	if first != 5 || second != 6 || third != 7 {
+		t.Fatalf("Acquire() = %d, %d, %d, want 5, 6, 7", first, second, third)
+	}
+
+	// A gap below a nonce still reserved is handed out again, lowest first
+	nm.Release(second)
+	nm.Release(first)
+	if got := mustAcquire(t, nm); got != 5 {
+		t.Errorf("Acquire() after release = %d, want 5", got)
+	}
+	if got := mustAcquire(t, nm); got != 6 {
+		t.Errorf("Acquire() after release = %d, want 6", got)
+	}
+	if got := mustAcquire(t, nm); got != 8 {
+		t.Errorf("Acquire() = %d, want 8", got)
+	}
+
+	// A dropped transaction frees its nonce as well
+	hash := common.HexToHash("0x01")
+	nm.Track(third, hash)
+	nm.Dropped(hash)
+	if got := mustAcquire(t, nm); got != 7 {
+		t.Errorf("Acquire() after drop = %d, want 7", got)
+	}
+
+	if got := source.syncs(); got != 1 {
+		t.Errorf("node queried %d times, want 1", got)
+	}
+}
+
+func TestNonceManagerResyncsWhileInFlight(t *testing.T) {
+	source := &stubNonceSource{pending: 5}
+	nm := NewNonceManager(source, common.Address{}, zap.NewNop().Sugar())
+
+	rejected, sent, reserved := mustAcquire(t, nm), mustAcquire(t, nm), mustAcquire(t, nm)
+	hash := common.HexToHash("0x01")
+	nm.Track(sent, hash)
+
+	// Another sender used nonce 5, so the node's pending nonce already counts the one in flight
+	source.pending = 7
+	nm.Reject(rejected)
+	if got := mustAcquire(t, nm); got != 8 {
+		t.Errorf("Acquire() after a nonce too low = %d, want 8", got)
+	}
+	nm.Release(8)
+
+	// The node lost the transaction in flight and refuses the reserved nonce above the gap
+	source.pending = 6
+	nm.Reject(reserved)
+	if got := mustAcquire(t, nm); got != reserved {
+		t.Errorf("Acquire() after a nonce too high = %d, want the rejected nonce %d", got, reserved)
+	}
+	if got := mustAcquire(t, nm); got != 8 {
+		t.Errorf("Acquire() = %d, want 8 while nonce %d is in flight", got, sent)
+	}
+
+	nm.Dropped(hash)
+	if got := mustAcquire(t, nm); got != sent {
+		t.Errorf("Acquire() after drop = %d, want %d", got, sent)
+	}
+	if got := source.syncs(); got != 3 {
+		t.Errorf("node queried %d times, want 3", got)
+	}
+}
+
+// newSimulatedAccount starts a simulated chain with a funded account
+func newSimulatedAccount(t *testing.T) (*ecdsa.PrivateKey, common.Address, *simulated.Backend) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		address: {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	return key, address, backend
+}
+
+func TestNonceManagerConcurrentAcquireRelease(t *testing.T) {
+	key, address, backend := newSimulatedAccount(t)
+	client := backend.Client()
+
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatalf("failed to get chain ID: %v", err)
+	}
+	signer := types.LatestSignerForChainID(chainID)
+
+	nm := NewNonceManager(client, address, zap.NewNop().Sugar())
+
+	var (
+		mu   sync.Mutex
+		sent = make(map[uint64]common.Hash)
+	)
+
+	// sendTransfer sends an empty transfer with the nonce and records it, failing the test
+	// when the nonce was already sent
+	sendTransfer := func(nonce uint64) (common.Hash, error) {
+		tx, err := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1_000_000_000),
+			GasFeeCap: big.NewInt(100_000_000_000),
+			Gas:       21000,
+			To:        &address,
+		}), signer, key)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if err := client.SendTransaction(ctx, tx); err != nil {
+			return common.Hash{}, err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if previous, ok := sent[nonce]; ok {
+			t.Errorf("nonce %d sent twice: %s and %s", nonce, previous.Hex(), tx.Hash().Hex())
+		}
+		sent[nonce] = tx.Hash()
+		return tx.Hash(), nil
+	}
+
+	const workers, iterations = 8, 12
+
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range iterations {
+				nonce, err := nm.Acquire(ctx)
+				if err != nil {
+					t.Errorf("Acquire() error = %v", err)
+					return
+				}
+
+				// Every third transaction fails before reaching the node
+				if (worker+i)%3 == 0 {
+					nm.Release(nonce)
+					continue
+				}
+
+				hash, err := sendTransfer(nonce)
+				if err != nil {
+					nm.Release(nonce)
+					t.Errorf("failed to send transaction with nonce %d: %v", nonce, err)
+					continue
+				}
+				nm.Track(nonce, hash)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Released nonces are handed out again before new ones, closing every gap
+	for {
+		nm.mu.Lock()
+		gaps := len(nm.free)
+		nm.mu.Unlock()
+		if gaps == 0 {
+			break
+		}
+
+		nonce := mustAcquire(t, nm)
+		hash, err := sendTransfer(nonce)
+		if err != nil {
+			t.Fatalf("failed to send transaction with nonce %d: %v", nonce, err)
+		}
+		nm.Track(nonce, hash)
+	}
+
+	backend.Commit()
+
+	mined, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		t.Fatalf("failed to get nonce: %v", err)
+	}
+	if mined != uint64(len(sent)) {
+		t.Errorf("mined nonce = %d, want %d transactions without gaps", mined, len(sent))
+	}
+	if nm.InFlight() != len(sent) {
+		t.Errorf("InFlight() = %d, want %d", nm.InFlight(), len(sent))
+	}
+}
+
+func TestEthereumWriterSendWithNonceConcurrently(t *testing.T) {
+	key, address, backend := newSimulatedAccount(t)
+	client := backend.Client()
+
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatalf("failed to get chain ID: %v", err)
+	}
+
+	ew := &EthereumWriter{
+		backend:       client,
+		privateKey:    key,
+		publicAddress: address,
+		chainID:       chainID,
+		nonces:        NewNonceManager(client, address, zap.NewNop().Sugar()),
+		gas:           DefaultGasConfig(),
+		logger:        zap.NewNop().Sugar(),
+	}
+
+	// transfer signs an empty transfer with the transactor's nonce and fees, as the binding
+	// does for a contract call
+	transfer := func(auth *bind.TransactOpts) (*types.Transaction, error) {
+		gas := auth.GasLimit
+		if gas == 0 {
+			gas = 21000
+		}
+		return auth.Signer(auth.From, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     auth.Nonce.Uint64(),
+			GasTipCap: auth.GasTipCap,
+			GasFeeCap: auth.GasFeeCap,
+			Gas:       gas,
+			To:        &address,
+		}))
+	}
+
+	// Another sender mines nonce 0 once the writer synced, so the first nonce it hands out
+	// is rejected as too low while the other transactions are being sent
+	nm := ew.nonces
+	nm.Release(mustAcquire(t, nm))
+
+	auth, err := ew.createTransactor(ctx, 0)
+	if err != nil {
+		t.Fatalf("createTransactor() error = %v", err)
+	}
+	external, err := transfer(auth)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if err := client.SendTransaction(ctx, external); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	backend.Commit()
+
+	const workers, iterations = 8, 5
+
+	var (
+		mu   sync.Mutex
+		sent = make(map[uint64]common.Hash)
+		wg   sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range iterations {
+				tx, err := ew.sendWithNonce(ctx, transfer)
+				if err != nil {
+					t.Errorf("sendWithNonce() error = %v", err)
+					return
+				}
+
+				mu.Lock()
+				if previous, ok := sent[tx.Nonce()]; ok {
+					t.Errorf("nonce %d sent twice: %s and %s", tx.Nonce(), previous.Hex(), tx.Hash().Hex())
+				}
+				sent[tx.Nonce()] = tx.Hash()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	backend.Commit()
+
+	mined, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		t.Fatalf("failed to get nonce: %v", err)
+	}
+	if want := uint64(workers*iterations) + 1; mined != want {
+		t.Errorf("mined nonce = %d, want %d transactions without gaps", mined, want)
+	}
+	if _, ok := sent[0]; ok {
+		t.Error("sendWithNonce() sent nonce 0, already mined for another sender")
+	}
+	if nm.InFlight() != workers*iterations {
+		t.Errorf("InFlight() = %d, want %d", nm.InFlight(), workers*iterations)
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"contracts/internal/core/domain"
 	"contracts/internal/core/port/driver"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ func NewContractHandler(service driver.ContractService) *ContractHandler {
 
 // GetContracts returns all contracts
 func (h *ContractHandler) GetContracts(c *gin.Context) {
-	contracts, err := h.service.RetrieveContracts(c)
+	contracts, err := h.service.RetrieveContracts(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -33,7 +34,7 @@ func (h *ContractHandler) GetContracts(c *gin.Context) {
 // GetContract returns a contract by id
 func (h *ContractHandler) GetContract(c *gin.Context) {
 	id := c.Param("id")
-	contract, err := h.service.RetrieveContract(c, id)
+	contract, err := h.service.RetrieveContract(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "contract not found"})
 		return
@@ -41,7 +42,7 @@ func (h *ContractHandler) GetContract(c *gin.Context) {
 	c.JSON(http.StatusOK, contract)
 }
 
-// PostContract creates a new contract
+// PostContract submits a new contract and responds with the pending transaction
 func (h *ContractHandler) PostContract(c *gin.Context) {
 	var payload domain.Contract
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -49,13 +50,18 @@ func (h *ContractHandler) PostContract(c *gin.Context) {
 		return
 	}
 
-	contract, err := h.service.CreateContract(c.Request.Context(), payload)
+	submission, err := h.service.CreateContract(c.Request.Context(), payload)
+	if errors.Is(err, domain.ErrMedicineNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, contract)
+	c.Header("Location", "/transactions/"+submission.Transaction.Hash)
+	c.JSON(http.StatusAccepted, submission)
 }
 
 // PutContract updates an existing contract (full replacement)
@@ -74,6 +80,10 @@ func (h *ContractHandler) PutContract(c *gin.Context) {
 	payload.ID = id
 
 	contract, err := h.service.UpdateContract(payload)
+	if errors.Is(err, domain.ErrMedicineNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -113,11 +123,12 @@ func (h *ContractHandler) PostSLA(c *gin.Context) {
 		return
 	}
 
-	sla, err := h.service.CreateSLA(c.Request.Context(), id, payload)
+	submission, err := h.service.CreateSLA(c.Request.Context(), id, payload)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, sla)
+	c.Header("Location", "/transactions/"+submission.Transaction.Hash)
+	c.JSON(http.StatusAccepted, submission)
 }
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driver"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MedicineHandler is a thin HTTP adapter that delegates to the MedicineService. The catalog is
+// read only, it follows the medicine events of the suppliers service.
+type MedicineHandler struct {
+	service driver.MedicineService
+}
+
+// NewMedicineHandler creates a new medicine handler
+func NewMedicineHandler(service driver.MedicineService) *MedicineHandler {
+	return &MedicineHandler{
+		service: service,
+	}
+}
+
+// GetMedicines returns all medicines
+func (h *MedicineHandler) GetMedicines(c *gin.Context) {
+	medicines, err := h.service.RetrieveMedicines()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, medicines)
+}
+
+// GetMedicine returns a medicine by id
+func (h *MedicineHandler) GetMedicine(c *gin.Context) {
+	id := c.Param("id")
+	medicine, err := h.service.RetrieveMedicine(id)
+	if errors.Is(err, domain.ErrMedicineNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "medicine not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, medicine)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"contracts/internal/core/port/driver"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransactionHandler is a thin HTTP adapter that delegates to the TransactionService
+type TransactionHandler struct {
+	service driver.TransactionService
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(service driver.TransactionService) *TransactionHandler {
+	return &TransactionHandler{
+		service: service,
+	}
+}
+
+// GetTransaction returns the tracked state of a transaction by hash
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	hash := c.Param("hash")
+	tx, err := h.service.RetrieveTransaction(hash)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tx)
+}
@@ -0,0 +1,125 @@
+// Package outbox publishes the contract line events stored in the outbox of the contract repository
+package outbox
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"contracts/pkg/logger"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RelayConfig tunes how the relay drains the outbox
+type RelayConfig struct {
+	PollInterval   time.Duration // wait between polls once the outbox is drained
+	BatchSize      int           // events claimed from the outbox per poll
+	PublishTimeout time.Duration // how long the delivery of an event may take
+	MaxBackoff     time.Duration // longest wait after consecutive failures, starting from PollInterval
+}
+
+// Relay publishes the contract line events written to the outbox and removes them once delivered.
+//
+// Each poll claims the oldest waiting event of every contract, so a later event of a contract never
+// overtakes an earlier one, even with several consumers sharing the outbox. A failed event stays in
+// the outbox and blocks only the later events of its contract. When a whole poll fails the relay
+// backs off before retrying. Delivery is at least once: an event published but not removed is
+// published again, with the same event ID for consumers to deduplicate on.
+type Relay struct {
+	outbox    driven.ContractEventOutbox
+	publisher driven.ContractEventPublisher
+	config    RelayConfig
+	logger    *zap.SugaredLogger
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(outbox driven.ContractEventOutbox, publisher driven.ContractEventPublisher, config RelayConfig) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		config:    config,
+		logger:    logger.New("OUTBOX"),
+	}
+}
+
+// Run relays the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Infow("Outbox relay started",
+		"poll_interval", r.config.PollInterval,
+		"batch_size", r.config.BatchSize,
+	)
+
+	wait := r.config.PollInterval
+	for {
+		published, err := r.relay(ctx)
+		switch {
+		case err != nil:
+			r.logger.Warnw("Outbox relay failed, retrying",
+				"error", err,
+				"retry_in", wait,
+			)
+			wait = min(wait*2, r.config.MaxBackoff)
+		case published > 0:
+			// Later events of the contracts just published may be waiting
+			wait = r.config.PollInterval
+			continue
+		default:
+			wait = r.config.PollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// relay publishes one batch of claimed events and returns how many were delivered. It fails
+// when events were claimed but none could be delivered.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	published := 0
+	var failure error
+
+	err := r.outbox.Claim(r.config.BatchSize, func(entries []domain.OutboxEntry) []error {
+		results := make([]error, len(entries))
+		for i := range entries {
+			results[i] = r.publish(ctx, &entries[i])
+			if results[i] != nil {
+				failure = results[i]
+				continue
+			}
+			published++
+		}
+		return results
+	})
+	if err != nil {
+		return published, err
+	}
+
+	if failure != nil && published == 0 {
+		return 0, fmt.Errorf("failed to publish outbox events: %w", failure)
+	}
+	return published, nil
+}
+
+// publish publishes an outbox event, waiting at most PublishTimeout for its delivery
+func (r *Relay) publish(ctx context.Context, entry *domain.OutboxEntry) error {
+	publishCtx, cancel := context.WithTimeout(ctx, r.config.PublishTimeout)
+	defer cancel()
+
+	if err := r.publisher.PublishContractLineEvent(publishCtx, &entry.Event); err != nil {
+		r.logger.Errorw("Failed to publish outbox event",
+			"error", err,
+			"event_id", entry.Event.ID,
+			"event_type", entry.Event.EventType,
+			"contract_id", entry.Event.Data.ContractID,
+			"attempts", entry.Attempts+1,
+		)
+		return err
+	}
+	return nil
+}
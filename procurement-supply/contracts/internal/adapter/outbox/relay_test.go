@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"context"
+	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/core/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubPublisher records the contract IDs of the events published and fails those of failing contracts
+type stubPublisher struct {
+	published []string
+	failing   map[string]bool
+}
+
+func (p *stubPublisher) PublishContractLineEvent(_ context.Context, event *domain.Event[domain.ContractLineChange]) error {
+	if p.failing[event.Data.ContractID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.Data.ContractID)
+	return nil
+}
+
+// enqueue stores an event for each contract ID, creating the contracts on first use
+func enqueue(t *testing.T, repo *memory.ContractRepository, contractIDs ...string) {
+	t.Helper()
+
+	for _, id := range contractIDs {
+		if !repo.Exists(id) {
+			if err := repo.Create(domain.Contract{ID: id}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		err := repo.UpdateLines(id, func(contract *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+			event := domain.NewContractLineEvent(domain.ContractLineChange{ContractID: id}, domain.ContractLinePriceDriftedEvent)
+			return true, []*domain.Event[domain.ContractLineChange]{event}, nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateLines() error = %v", err)
+		}
+	}
+}
+
+func TestRelay(t *testing.T) {
+	repo := memory.NewContractRepository()
+	publisher := &stubPublisher{failing: map[string]bool{"contract-2": true}}
+	relay := NewRelay(repo, publisher, RelayConfig{BatchSize: 10, PublishTimeout: time.Second})
+
+	enqueue(t, repo, "contract-1", "contract-2", "contract-1")
+
+	// Each poll publishes the oldest event of every contract, a failed one blocks its contract only
+	for poll, want := range []int{1, 1, 0} {
+		published, err := relay.relay(context.Background())
+		if published != want {
+			t.Errorf("poll %d published %d events, want %d", poll, published, want)
+		}
+		if (err != nil) != (want == 0) {
+			t.Errorf("poll %d error = %v", poll, err)
+		}
+	}
+
+	if len(publisher.published) != 2 || publisher.published[0] != "contract-1" || publisher.published[1] != "contract-1" {
+		t.Errorf("published %v, want the two events of contract-1", publisher.published)
+	}
+
+	// The failed event is published once the broker accepts it
+	publisher.failing = nil
+	if published, err := relay.relay(context.Background()); published != 1 || err != nil {
+		t.Errorf("relay() = %d, %v, want the failed event published", published, err)
+	}
+}
@@ -5,19 +5,30 @@ import (
 	"contracts/internal/core/domain"
 	"contracts/internal/core/port/driven"
 	"contracts/pkg/logger"
-	"encoding/json"
+	"contracts/pkg/metrics"
+	"errors"
 	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/kafkatrace"
+	"procurement-supply/pkg/events"
+	"procurement-supply/pkg/retry"
+	"strconv"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 // KafkaEventConsumer is the adapter that implements the EventConsumer driver port
 type KafkaEventConsumer struct {
 	consumer     *kafka.Consumer
+	producer     *kafka.Producer
 	eventService driven.EventProcessor
+	decoder      *events.Decoder
 	logger       *zap.SugaredLogger
 	topics       []string
+	retries      *retry.Handler
 }
 
 // Config holds the Kafka consumer configuration
@@ -28,19 +39,51 @@ type Config struct {
 	AutoOffsetReset  string
 }
 
-// NewKafkaEventConsumer creates a new Kafka event consumer adapter
-func NewKafkaEventConsumer(config kafka.ConfigMap, topics []string, eventService driven.EventProcessor) (*KafkaEventConsumer, error) {
+// NewKafkaEventConsumer creates a new Kafka event consumer adapter. Messages are decoded in the
+// format named by their content type header, and those that fail processing are handled according
+// to the retry policy, using a producer on the same brokers. The in-memory retries of the policy
+// must fit in the max.poll.interval.ms of the configuration.
+func NewKafkaEventConsumer(config kafka.ConfigMap, topics []string, eventService driven.EventProcessor, decoder *events.Decoder, policy retry.Policy) (*KafkaEventConsumer, error) {
+	bootstrapServers, err := config.Get("bootstrap.servers", "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid bootstrap.servers: %w", err)
+	}
+
+	maxPollInterval, err := retry.MaxPollInterval(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(maxPollInterval); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
+
 	consumer, err := kafka.NewConsumer(&config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
 	}
 
-	return &KafkaEventConsumer{
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  bootstrapServers,
+		"enable.idempotence": true,
+	})
+	if err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	k := &KafkaEventConsumer{
 		consumer:     consumer,
+		producer:     producer,
 		eventService: eventService,
+		decoder:      decoder,
 		logger:       logger.New("KAFKA-CONSUMER"),
-		topics:       topics,
-	}, nil
+		topics:       policy.Subscriptions(topics),
+		// Keep the retries in the trace of the failed attempt
+		retries: retry.NewHandler(consumer, producer, policy, kafkatrace.Inject),
+	}
+	go k.logProducerEvents()
+
+	return k, nil
 }
 
 // Start begins consuming messages from Kafka
@@ -74,6 +117,13 @@ func (k *KafkaEventConsumer) Start(ctx context.Context) error {
 
 // pollAndProcess polls for a message and processes it
 func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
+	if err := k.retries.ResumeDue(); err != nil {
+		// The partitions were most likely revoked by a rebalance, their new owner starts them unpaused
+		k.logger.Debugw("Failed to resume retry partitions",
+			"error", err,
+		)
+	}
+
 	ev := k.consumer.Poll(100)
 	if ev == nil {
 		return nil
@@ -81,7 +131,7 @@ func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
 
 	switch e := ev.(type) {
 	case *kafka.Message:
-		return k.handleMessage(e)
+		return k.handleMessage(ctx, e)
 
 	case kafka.Error:
 		k.logger.Errorw("Kafka error",
@@ -105,63 +155,178 @@ func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
 	return nil
 }
 
-// handleMessage processes a single Kafka message
-func (k *KafkaEventConsumer) handleMessage(msg *kafka.Message) error {
+// handleMessage processes a single Kafka message within a consumer span that continues the
+// trace found in its headers, and counts the outcome. A message that still fails after the
+// in-memory attempts is forwarded to a retry or dead-letter topic before its offset is committed;
+// when that is not possible the partition is rewound so the message is not skipped.
+func (k *KafkaEventConsumer) handleMessage(ctx context.Context, msg *kafka.Message) error {
+	until, deferred, err := k.retries.Defer(msg)
+	if deferred {
+		k.logger.Debugw("Retry message not due yet, partition paused",
+			"topic", *msg.TopicPartition.Topic,
+			"partition", msg.TopicPartition.Partition,
+			"offset", msg.TopicPartition.Offset,
+			"until", until,
+		)
+		return err
+	}
+	if err != nil {
+		k.logger.Warnw("Failed to pause retry partition, processing message early",
+			"error", err,
+		)
+	}
+
+	topic := *msg.TopicPartition.Topic
+
+	ctx, span := tracing.Tracer().Start(kafkatrace.Extract(ctx, msg), topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(msg.TopicPartition.Partition))),
+			semconv.MessagingKafkaOffset(int(msg.TopicPartition.Offset)),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		),
+	)
+
+	attempts, processErr := k.processMessage(ctx, msg)
+	metrics.KafkaMessageConsumed(topic, processErr)
+
+	outcome, err := k.retries.Settle(ctx, msg, attempts, processErr)
+	k.logOutcome(msg, outcome, processErr, err)
+
+	if processErr != nil {
+		tracing.EndSpan(span, processErr)
+	} else {
+		tracing.EndSpan(span, err)
+	}
+
+	return err
+}
+
+// processMessage parses a single Kafka message and processes it, retrying in memory with
+// exponential backoff. It returns the number of attempts made and the last error.
+func (k *KafkaEventConsumer) processMessage(ctx context.Context, msg *kafka.Message) (int, error) {
 	k.logger.Debugw("Received Kafka message",
 		"topic", *msg.TopicPartition.Topic,
 		"partition", msg.TopicPartition.Partition,
 		"offset", msg.TopicPartition.Offset,
 	)
 
-	// Parse the message
+	// Parse the message, retry topics hold messages encoded for the original topic
 	var event domain.Event[domain.Medicine]
-	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		k.logger.Errorw("Failed to unmarshal message",
+	if err := k.decoder.Decode(events.ContentType(msg), retry.OriginalTopic(msg), msg.Value, &event); err != nil {
+		k.logger.Errorw("Failed to decode message",
 			"error", err,
 			"topic", *msg.TopicPartition.Topic,
+			"content_type", events.ContentType(msg),
 		)
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+		if errors.Is(err, events.ErrSchemaUnavailable) {
+			return 1, err
+		}
+		return 1, fmt.Errorf("%w: %v", retry.ErrMalformedMessage, err)
+	}
+
+	if err := event.Validate(); err != nil {
+		return 1, fmt.Errorf("%w: %v", retry.ErrMalformedMessage, err)
 	}
 
+	// The events raised while processing this one share its correlation ID
+	correlationID := event.CorrelationID
+	if correlationID == "" {
+		correlationID = event.ID
+	}
+	ctx = events.WithCorrelationID(ctx, correlationID)
+
 	k.logger.Infow("Processing event",
+		"event_id", event.ID,
 		"event_type", event.EventType,
+		"schema_version", event.SchemaVersion,
+		"source", event.Source,
+		"correlation_id", correlationID,
 		"medicine_id", event.Data.ID,
 		"medicine_name", event.Data.Name,
+		"trace_id", trace.SpanContextFromContext(ctx).TraceID().String(),
 	)
 
-	// Process the event through the application service
-	if err := k.eventService.ProcessEvent(&event); err != nil {
-		k.logger.Errorw("Error processing event",
+	attempts, err := k.retries.Policy().Do(ctx, func(attempt int) error {
+		// Process the event through the application service
+		err := k.eventService.ProcessEvent(ctx, &event)
+		if err != nil {
+			k.logger.Warnw("Error processing event",
+				"error", err,
+				"event_type", event.EventType,
+				"attempt", attempt,
+			)
+		}
+		return err
+	})
+	if err != nil {
+		k.logger.Errorw("Giving up processing event",
 			"error", err,
 			"event_type", event.EventType,
+			"attempts", attempts,
 		)
-		// Depending on your error handling strategy, you might want to:
-		// - Return the error to stop processing
-		// - Continue processing other messages
-		// - Send to a dead letter queue
-		return err
 	}
 
-	// Commit offset after successful processing
-	if _, err := k.consumer.CommitMessage(msg); err != nil {
-		k.logger.Errorw("Error committing offset",
-			"error", err,
-			"offset", msg.TopicPartition.Offset,
+	return attempts, err
+}
+
+// logOutcome logs and counts what became of a handled message. Rewound messages and failed
+// commits are reported through the error returned to the poll loop.
+func (k *KafkaEventConsumer) logOutcome(msg *kafka.Message, outcome retry.Outcome, processErr, settleErr error) {
+	switch outcome.Action {
+	case retry.Committed:
+		if settleErr == nil {
+			k.logger.Debugw("Successfully handled and committed message",
+				"offset", msg.TopicPartition.Offset,
+			)
+		}
+	case retry.Retried:
+		metrics.KafkaMessageRetried(outcome.OriginalTopic)
+		k.logger.Warnw("Message sent to retry topic",
+			"error", processErr,
+			"topic", outcome.Destination,
+			"original_topic", outcome.OriginalTopic,
+			"attempts", outcome.Attempts,
+			"delay", outcome.Delay,
+		)
+	case retry.DeadLettered:
+		metrics.KafkaMessageDeadLettered(outcome.OriginalTopic)
+		k.logger.Errorw("Message sent to dead-letter topic",
+			"error", processErr,
+			"topic", outcome.Destination,
+			"original_topic", outcome.OriginalTopic,
+			"attempts", outcome.Attempts,
 		)
-		return fmt.Errorf("failed to commit offset: %w", err)
 	}
+}
 
-	k.logger.Debugw("Successfully processed and committed message",
-		"offset", msg.TopicPartition.Offset,
-	)
-
-	return nil
+// logProducerEvents logs the errors reported by the producer until it is closed
+func (k *KafkaEventConsumer) logProducerEvents() {
+	for e := range k.producer.Events() {
+		if err, ok := e.(kafka.Error); ok {
+			k.logger.Errorw("Kafka producer error",
+				"error", err,
+				"error_code", err.Code(),
+			)
+		}
+	}
 }
 
 // Stop gracefully stops the consumer
 func (k *KafkaEventConsumer) Stop() error {
 	k.logger.Info("Closing Kafka consumer...")
 
+	// Wait for forwarded messages still in flight
+	if remaining := k.producer.Flush(5000); remaining > 0 {
+		k.logger.Warnw("Kafka producer closed with undelivered messages",
+			"remaining", remaining,
+		)
+	}
+	k.producer.Close()
+
 	if err := k.consumer.Close(); err != nil {
 		k.logger.Errorw("Error closing consumer",
 			"error", err,
@@ -0,0 +1,120 @@
+package queue
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"contracts/pkg/logger"
+	"contracts/pkg/metrics"
+	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/kafkatrace"
+	"procurement-supply/pkg/events"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// KafkaEventPublisher implements the ContractEventPublisher driven port
+type KafkaEventPublisher struct {
+	producer *kafka.Producer
+	topic    string
+	encoder  events.Encoder
+	logger   *zap.SugaredLogger
+}
+
+// Ensure KafkaEventPublisher implements the driven.ContractEventPublisher interface
+var _ driven.ContractEventPublisher = (*KafkaEventPublisher)(nil)
+
+// NewKafkaEventPublisher creates a new Kafka event publisher writing events with the given encoder
+func NewKafkaEventPublisher(bootstrapServers, topic string, encoder events.Encoder) (*KafkaEventPublisher, error) {
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  bootstrapServers,
+		"enable.idempotence": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	return &KafkaEventPublisher{
+		producer: producer,
+		topic:    topic,
+		encoder:  encoder,
+		logger:   logger.New("KAFKA-PUBLISHER"),
+	}, nil
+}
+
+// PublishContractLineEvent publishes a contract line event to Kafka within a producer span and counts the outcome
+func (k *KafkaEventPublisher) PublishContractLineEvent(ctx context.Context, event *domain.Event[domain.ContractLineChange]) error {
+	ctx, span := tracing.Tracer().Start(ctx, k.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(k.topic),
+			semconv.MessagingKafkaMessageKey(event.Data.ContractID),
+		),
+	)
+
+	err := k.publish(ctx, event)
+	metrics.KafkaMessagePublished(k.topic, err)
+	tracing.EndSpan(span, err)
+
+	return err
+}
+
+// publish encodes a contract line event, carrying the trace context in the message headers, and waits for its delivery
+func (k *KafkaEventPublisher) publish(ctx context.Context, event *domain.Event[domain.ContractLineChange]) error {
+	k.logger.Infow("Publishing event",
+		"event_type", event.EventType,
+		"contract_id", event.Data.ContractID,
+		"medicine_id", event.Data.Line.MedicineID,
+	)
+
+	eventBytes, err := k.encoder.Encode(k.topic, event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	message := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic:     &k.topic,
+			Partition: kafka.PartitionAny,
+		},
+		Value: eventBytes,
+		Key:   []byte(event.Data.ContractID), // Keep the events of a contract in order
+		// Let consumers pick the decoder and deduplicate redelivered events without reading the payload
+		Headers: event.Headers(k.encoder.ContentType()),
+	}
+	kafkatrace.Inject(ctx, message)
+
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := k.producer.Produce(message, deliveryChan); err != nil {
+		return fmt.Errorf("failed to produce message: %w", err)
+	}
+
+	select {
+	case e := <-deliveryChan:
+		m := e.(*kafka.Message)
+		if m.TopicPartition.Error != nil {
+			return fmt.Errorf("delivery failed: %w", m.TopicPartition.Error)
+		}
+
+		k.logger.Debugw("Event delivered",
+			"topic", *m.TopicPartition.Topic,
+			"partition", m.TopicPartition.Partition,
+			"offset", m.TopicPartition.Offset,
+		)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close waits for the messages still in flight and closes the Kafka producer
+func (k *KafkaEventPublisher) Close() {
+	k.producer.Flush(5000)
+	k.producer.Close()
+}
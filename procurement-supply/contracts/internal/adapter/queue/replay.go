@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"contracts/pkg/logger"
+	"fmt"
+	"procurement-supply/pkg/events"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.uber.org/zap"
+)
+
+// CatalogReplayer rebuilds the local medicine catalog by reading the medicine topics again from
+// the earliest offset.
+//
+// It uses a consumer of its own, assigned every partition of the topics instead of joining the
+// service's consumer group, and never commits: the offsets of the group, and the partitions other
+// instances own, are left alone. Retry topics are not read, their messages were published to the
+// original topics first.
+type CatalogReplayer struct {
+	consumer *kafka.Consumer
+	restorer driven.CatalogRestorer
+	decoder  *events.Decoder
+	topics   []string
+	logger   *zap.SugaredLogger
+}
+
+// NewCatalogReplayer creates a replayer of the topics. The group ID is only required by the client,
+// the replayer neither joins the group nor commits offsets to it.
+func NewCatalogReplayer(bootstrapServers, groupID string, topics []string, restorer driven.CatalogRestorer, decoder *events.Decoder) (*CatalogReplayer, error) {
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers":        bootstrapServers,
+		"group.id":                 groupID,
+		"enable.auto.commit":       false,
+		"enable.auto.offset.store": false,
+		"enable.partition.eof":     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
+	}
+
+	return &CatalogReplayer{
+		consumer: consumer,
+		restorer: restorer,
+		decoder:  decoder,
+		topics:   topics,
+		logger:   logger.New("CATALOG-REPLAY"),
+	}, nil
+}
+
+// Replay reads every partition of the topics from the earliest offset up to the end offset it had
+// when the replay started, restoring the catalog from its events, and closes the consumer. Later
+// events are left to the consumer group.
+func (r *CatalogReplayer) Replay(ctx context.Context) error {
+	defer func() {
+		if err := r.consumer.Close(); err != nil {
+			r.logger.Errorw("Error closing replay consumer",
+				"error", err,
+			)
+		}
+	}()
+
+	remaining, err := r.assign()
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for len(remaining) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch e := r.consumer.Poll(100).(type) {
+		case *kafka.Message:
+			if err := r.restore(ctx, e); err != nil {
+				return err
+			}
+			restored++
+
+			key := partitionKey(e.TopicPartition)
+			if end, ok := remaining[key]; ok && int64(e.TopicPartition.Offset)+1 >= end {
+				delete(remaining, key)
+			}
+
+		case kafka.PartitionEOF:
+			delete(remaining, partitionKey(kafka.TopicPartition(e)))
+
+		case kafka.Error:
+			if e.IsFatal() || e.Code() == kafka.ErrAllBrokersDown {
+				return fmt.Errorf("failed to replay the medicine topics: %w", e)
+			}
+			r.logger.Warnw("Kafka error while replaying",
+				"error", e,
+				"error_code", e.Code(),
+			)
+		}
+	}
+
+	r.logger.Infow("Medicine catalog rebuilt",
+		"topics", r.topics,
+		"events", restored,
+	)
+	return nil
+}
+
+// assign assigns every non-empty partition of the topics from the earliest offset, and returns
+// the end offset of each one by partition key
+func (r *CatalogReplayer) assign() (map[string]int64, error) {
+	remaining := make(map[string]int64)
+	partitions := make([]kafka.TopicPartition, 0)
+
+	for _, topic := range r.topics {
+		metadata, err := r.consumer.GetMetadata(&topic, false, 10000)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata of %s: %w", topic, err)
+		}
+
+		for _, partition := range metadata.Topics[topic].Partitions {
+			low, high, err := r.consumer.QueryWatermarkOffsets(topic, partition.ID, 10000)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get offsets of %s/%d: %w", topic, partition.ID, err)
+			}
+			if high <= low {
+				continue
+			}
+
+			tp := kafka.TopicPartition{Topic: &topic, Partition: partition.ID, Offset: kafka.OffsetBeginning}
+			remaining[partitionKey(tp)] = high
+			partitions = append(partitions, tp)
+
+			r.logger.Infow("Replaying partition from the earliest offset",
+				"topic", topic,
+				"partition", partition.ID,
+				"end_offset", high,
+			)
+		}
+	}
+
+	if err := r.consumer.Assign(partitions); err != nil {
+		return nil, fmt.Errorf("failed to assign partitions: %w", err)
+	}
+	return remaining, nil
+}
+
+// restore applies a message to the catalog. Messages that cannot be read are skipped, the
+// consumer group already sent them to the dead-letter topic.
+func (r *CatalogReplayer) restore(ctx context.Context, msg *kafka.Message) error {
+	var event domain.Event[domain.Medicine]
+	err := r.decoder.Decode(events.ContentType(msg), *msg.TopicPartition.Topic, msg.Value, &event)
+	if err == nil {
+		err = event.Validate()
+	}
+	if err != nil {
+		r.logger.Warnw("Skipping unreadable message",
+			"error", err,
+			"topic", *msg.TopicPartition.Topic,
+			"partition", msg.TopicPartition.Partition,
+			"offset", msg.TopicPartition.Offset,
+		)
+		return nil
+	}
+
+	if err := r.restorer.RestoreEvent(ctx, &event); err != nil {
+		return fmt.Errorf("failed to restore event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// partitionKey identifies a partition of a topic
+func partitionKey(tp kafka.TopicPartition) string {
+	return fmt.Sprintf("%s/%d", *tp.Topic, tp.Partition)
+}
@@ -0,0 +1,8 @@
+package queue
+
+import _ "embed"
+
+// ContractLineEventSchema is the Avro schema of the contract line events, used with the avro event format
+//
+//go:embed schemas/contract_line_event.avsc
+var ContractLineEventSchema string
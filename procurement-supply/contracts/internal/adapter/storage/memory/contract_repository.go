@@ -4,22 +4,32 @@ import (
 	"contracts/internal/core/domain"
 	"contracts/internal/core/port/driven"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 )
 
-// ContractRepository is an in-memory implementation of the Repository[string, domain.Contract] port
+// ContractRepository is an in-memory implementation of the ContractRepository and
+// ContractEventOutbox ports
 type ContractRepository struct {
 	mu        sync.RWMutex
 	contracts map[string]domain.Contract
+	outbox    []domain.OutboxEntry // oldest first
+	claimed   map[string]bool      // IDs of the events being published
 }
 
-// Ensure ContractRepository implements the Repository interface
-var _ driven.Repository[string, domain.Contract] = (*ContractRepository)(nil)
+// Ensure ContractRepository implements the ContractRepository and ContractEventOutbox interfaces
+var (
+	_ driven.ContractRepository  = (*ContractRepository)(nil)
+	_ driven.ContractEventOutbox = (*ContractRepository)(nil)
+)
 
 // NewContractRepository creates a new in-memory contract repository
 func NewContractRepository() *ContractRepository {
 	return &ContractRepository{
 		contracts: make(map[string]domain.Contract),
+		outbox:    make([]domain.OutboxEntry, 0),
+		claimed:   make(map[string]bool),
 	}
 }
 
@@ -62,6 +72,21 @@ func (r *ContractRepository) FindAll() ([]domain.Contract, error) {
 	return contracts, nil
 }
 
+// FindByMedicine retrieves the contracts with a line covering the medicine
+func (r *ContractRepository) FindByMedicine(medicineID string) ([]domain.Contract, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contracts := make([]domain.Contract, 0)
+	for _, contract := range r.contracts {
+		if contract.CoversMedicine(medicineID) {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	return contracts, nil
+}
+
 // Update modifies an existing contract
 func (r *ContractRepository) Update(contract domain.Contract) error {
 	r.mu.Lock()
@@ -96,3 +121,114 @@ func (r *ContractRepository) Exists(id string) bool {
 	_, exists := r.contracts[id]
 	return exists
 }
+
+// UpdateLines lets update edit the lines of a copy of the contract and stores them along with
+// its events, holding the repository lock throughout
+func (r *ContractRepository) UpdateLines(id string, update func(contract *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.contracts[id]
+	if !exists {
+		return fmt.Errorf("contract with id %s not found", id)
+	}
+
+	contract := stored
+	contract.Lines = slices.Clone(stored.Lines)
+
+	changed, lineEvents, err := update(&contract)
+	if err != nil || !changed {
+		return err
+	}
+
+	stored.Lines = contract.Lines
+	r.contracts[id] = stored
+
+	for _, event := range lineEvents {
+		r.outbox = append(r.outbox, domain.OutboxEntry{Event: *event, CreatedAt: time.Now().UTC()})
+	}
+	return nil
+}
+
+// UpdateSLAs lets update edit the SLAs of a copy of the contract and stores them, holding the
+// repository lock throughout
+func (r *ContractRepository) UpdateSLAs(id string, update func(contract *domain.Contract) (bool, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.contracts[id]
+	if !exists {
+		return fmt.Errorf("contract with id %s not found", id)
+	}
+
+	// The SLAs are pointers, copy them so that update cannot edit the stored ones
+	contract := stored
+	contract.SLAs = make([]*domain.SLA, len(stored.SLAs))
+	for i, sla := range stored.SLAs {
+		slaCopy := *sla
+		contract.SLAs[i] = &slaCopy
+	}
+
+	changed, err := update(&contract)
+	if err != nil || !changed {
+		return err
+	}
+
+	stored.SLAs = contract.SLAs
+	r.contracts[id] = stored
+	return nil
+}
+
+// Claim passes the oldest unclaimed event of up to limit contracts to publish, releasing the lock
+// while they are published
+func (r *ContractRepository) Claim(limit int, publish func(entries []domain.OutboxEntry) []error) error {
+	r.mu.Lock()
+	entries := make([]domain.OutboxEntry, 0)
+	seen := make(map[string]bool)
+	for _, entry := range r.outbox {
+		if len(entries) == limit {
+			break
+		}
+
+		// Only the oldest event of a contract may be published, even when it is already claimed
+		contractID := entry.Event.Data.ContractID
+		if seen[contractID] {
+			continue
+		}
+		seen[contractID] = true
+
+		if !r.claimed[entry.Event.ID] {
+			r.claimed[entry.Event.ID] = true
+			entries = append(entries, entry)
+		}
+	}
+	r.mu.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	results := publish(entries)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	outcomes := make(map[string]error, len(entries))
+	for i, entry := range entries {
+		outcomes[entry.Event.ID] = results[i]
+		delete(r.claimed, entry.Event.ID)
+	}
+
+	r.outbox = slices.DeleteFunc(r.outbox, func(entry domain.OutboxEntry) bool {
+		err, claimed := outcomes[entry.Event.ID]
+		return claimed && err == nil
+	})
+	for i := range r.outbox {
+		if err := outcomes[r.outbox[i].Event.ID]; err != nil {
+			r.outbox[i].Attempts++
+			r.outbox[i].LastError = err.Error()
+		}
+	}
+
+	return nil
+}
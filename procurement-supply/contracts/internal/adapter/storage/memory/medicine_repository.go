@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"cmp"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// MedicineRepository is an in-memory implementation of the Repository[string, domain.Medicine] port.
+// It holds the local copy of the medicine catalog of the suppliers service.
+type MedicineRepository struct {
+	mu        sync.RWMutex
+	medicines map[string]domain.Medicine
+}
+
+// Ensure MedicineRepository implements the Repository interface
+var _ driven.Repository[string, domain.Medicine] = (*MedicineRepository)(nil)
+
+// NewMedicineRepository creates a new in-memory medicine repository
+func NewMedicineRepository() *MedicineRepository {
+	return &MedicineRepository{
+		medicines: make(map[string]domain.Medicine),
+	}
+}
+
+// Create adds a new medicine to the repository
+func (r *MedicineRepository) Create(medicine domain.Medicine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[medicine.ID]; exists {
+		return fmt.Errorf("medicine with id %s already exists", medicine.ID)
+	}
+
+	r.medicines[medicine.ID] = medicine
+	return nil
+}
+
+// FindByID retrieves a medicine by its ID
+func (r *MedicineRepository) FindByID(id string) (*domain.Medicine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	medicine, exists := r.medicines[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	return &medicine, nil
+}
+
+// FindAll retrieves all medicines, ordered by name
+func (r *MedicineRepository) FindAll() ([]domain.Medicine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	medicines := make([]domain.Medicine, 0, len(r.medicines))
+	for _, medicine := range r.medicines {
+		medicines = append(medicines, medicine)
+	}
+
+	slices.SortFunc(medicines, func(a, b domain.Medicine) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	return medicines, nil
+}
+
+// Update modifies an existing medicine
+func (r *MedicineRepository) Update(medicine domain.Medicine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[medicine.ID]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, medicine.ID)
+	}
+
+	r.medicines[medicine.ID] = medicine
+	return nil
+}
+
+// Delete removes a medicine by its ID
+func (r *MedicineRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[id]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	delete(r.medicines, id)
+	return nil
+}
+
+// Exists checks if a medicine with the given ID exists
+func (r *MedicineRepository) Exists(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.medicines[id]
+	return exists
+}
@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"contracts/internal/adapter/storage/storagetest"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"testing"
+)
+
+func TestContractRepository(t *testing.T) {
+	storagetest.RunContractRepositoryTests(t, func(*testing.T) storagetest.ContractStore {
+		return NewContractRepository()
+	})
+}
+
+func TestCustomerRepository(t *testing.T) {
+	storagetest.RunRepositoryTests(t, func(*testing.T) driven.Repository[string, domain.Customer] {
+		return NewCustomerRepository()
+	}, storagetest.CustomerFixture)
+}
+
+func TestTransactionRepository(t *testing.T) {
+	storagetest.RunTransactionRepositoryTests(t, func(*testing.T) driven.TransactionRepository {
+		return NewTransactionRepository()
+	})
+}
+
+func TestSLARepository(t *testing.T) {
+	storagetest.RunRepositoryTests(t, func(*testing.T) driven.Repository[string, domain.SLA] {
+		return NewSLARepository()
+	}, storagetest.SLAFixture)
+}
+
+func TestMedicineRepository(t *testing.T) {
+	storagetest.RunMedicineRepositoryTests(t, func(*testing.T) driven.Repository[string, domain.Medicine] {
+		return NewMedicineRepository()
+	})
+}
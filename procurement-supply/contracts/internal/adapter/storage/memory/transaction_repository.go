@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"fmt"
+	"sync"
+)
+
+// TransactionRepository is an in-memory implementation of the TransactionRepository port.
+// Transactions are keyed by their hash.
+type TransactionRepository struct {
+	mu           sync.RWMutex
+	transactions map[string]domain.Transaction
+}
+
+// Ensure TransactionRepository implements the TransactionRepository interface
+var _ driven.TransactionRepository = (*TransactionRepository)(nil)
+
+// NewTransactionRepository creates a new in-memory transaction repository
+func NewTransactionRepository() *TransactionRepository {
+	return &TransactionRepository{
+		transactions: make(map[string]domain.Transaction),
+	}
+}
+
+// Create adds a new transaction to the repository
+func (r *TransactionRepository) Create(tx domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.transactions[tx.Hash]; exists {
+		return fmt.Errorf("transaction with hash %s already exists", tx.Hash)
+	}
+
+	r.transactions[tx.Hash] = tx
+	return nil
+}
+
+// FindByID retrieves a transaction by its hash
+func (r *TransactionRepository) FindByID(hash string) (*domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tx, exists := r.transactions[hash]
+	if !exists {
+		return nil, fmt.Errorf("transaction with hash %s not found", hash)
+	}
+
+	return &tx, nil
+}
+
+// FindAll retrieves all transactions
+func (r *TransactionRepository) FindAll() ([]domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transactions := make([]domain.Transaction, 0, len(r.transactions))
+	for _, tx := range r.transactions {
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+// FindPending retrieves the transactions not in a final state
+func (r *TransactionRepository) FindPending() ([]domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transactions := make([]domain.Transaction, 0)
+	for _, tx := range r.transactions {
+		if !tx.IsFinal() {
+			transactions = append(transactions, tx)
+		}
+	}
+
+	return transactions, nil
+}
+
+// HasPending reports whether a transaction of the operation on the contract is not in a final state
+func (r *TransactionRepository) HasPending(operation, contractID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, tx := range r.transactions {
+		if tx.Operation == operation && tx.ContractID == contractID && !tx.IsFinal() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Update modifies an existing transaction
+func (r *TransactionRepository) Update(tx domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.transactions[tx.Hash]; !exists {
+		return fmt.Errorf("transaction with hash %s not found", tx.Hash)
+	}
+
+	r.transactions[tx.Hash] = tx
+	return nil
+}
+
+// Delete removes a transaction by its hash
+func (r *TransactionRepository) Delete(hash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.transactions[hash]; !exists {
+		return fmt.Errorf("transaction with hash %s not found", hash)
+	}
+
+	delete(r.transactions, hash)
+	return nil
+}
+
+// Exists checks if a transaction with the given hash exists
+func (r *TransactionRepository) Exists(hash string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.transactions[hash]
+	return exists
+}
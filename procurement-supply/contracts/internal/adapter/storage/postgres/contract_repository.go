@@ -0,0 +1,442 @@
+package postgres
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ContractRepository is a PostgreSQL implementation of the ContractRepository and ContractEventOutbox
+// ports. The SLAs and lines embedded in a contract are stored as JSONB documents next to the contract
+// row, and the line events in the contract_outbox table.
+type ContractRepository struct {
+	db *sql.DB
+}
+
+// Ensure ContractRepository implements the ContractRepository and ContractEventOutbox interfaces
+var (
+	_ driven.ContractRepository  = (*ContractRepository)(nil)
+	_ driven.ContractEventOutbox = (*ContractRepository)(nil)
+)
+
+// NewContractRepository creates a new PostgreSQL contract repository
+func NewContractRepository(db *sql.DB) *ContractRepository {
+	return &ContractRepository{
+		db: db,
+	}
+}
+
+// Create adds a new contract to the repository
+func (r *ContractRepository) Create(contract domain.Contract) error {
+	slas, err := marshalSLAs(contract.SLAs)
+	if err != nil {
+		return err
+	}
+
+	lines, err := marshalLines(contract.Lines)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO contracts (id, path, customer_id, slas, lines)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING`,
+		contract.ID, contract.Path, contract.CustomerID, slas, lines,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert contract: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("contract with id %s already exists", contract.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a contract by its ID
+func (r *ContractRepository) FindByID(id string) (*domain.Contract, error) {
+	row := r.db.QueryRowContext(context.Background(), `
+		SELECT id, path, customer_id, slas, lines
+		FROM contracts
+		WHERE id = $1`, id,
+	)
+
+	contract, err := scanContract(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("contract with id %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return contract, nil
+}
+
+// FindAll retrieves all contracts
+func (r *ContractRepository) FindAll() ([]domain.Contract, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, path, customer_id, slas, lines
+		FROM contracts
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contracts: %w", err)
+	}
+
+	return scanContracts(rows)
+}
+
+// FindByMedicine retrieves the contracts with a line covering the medicine, through the GIN index
+// on the lines
+func (r *ContractRepository) FindByMedicine(medicineID string) ([]domain.Contract, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, path, customer_id, slas, lines
+		FROM contracts
+		WHERE lines @> jsonb_build_array(jsonb_build_object('medicineId', $1::text))
+		ORDER BY id`, medicineID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contracts: %w", err)
+	}
+
+	return scanContracts(rows)
+}
+
+// scanContracts reads every contract row and closes the rows
+func scanContracts(rows *sql.Rows) ([]domain.Contract, error) {
+	defer rows.Close()
+
+	contracts := make([]domain.Contract, 0)
+	for rows.Next() {
+		contract, err := scanContract(rows)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, *contract)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate contracts: %w", err)
+	}
+
+	return contracts, nil
+}
+
+// Update modifies an existing contract
+func (r *ContractRepository) Update(contract domain.Contract) error {
+	slas, err := marshalSLAs(contract.SLAs)
+	if err != nil {
+		return err
+	}
+
+	lines, err := marshalLines(contract.Lines)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE contracts
+		SET path = $2, customer_id = $3, slas = $4, lines = $5
+		WHERE id = $1`,
+		contract.ID, contract.Path, contract.CustomerID, slas, lines,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update contract: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("contract with id %s not found", contract.ID)
+	}
+
+	return nil
+}
+
+// UpdateLines lets update edit the lines of the contract and stores only them, along with its
+// events, holding a lock on the contract row from the moment it is read until both are committed
+func (r *ContractRepository) UpdateLines(id string, update func(contract *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error)) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin contract lines update: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, path, customer_id, slas, lines
+		FROM contracts
+		WHERE id = $1
+		FOR UPDATE`, id,
+	)
+
+	contract, err := scanContract(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("contract with id %s not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed, lineEvents, err := update(contract)
+	if err != nil || !changed {
+		return err
+	}
+
+	lines, err := marshalLines(contract.Lines)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE contracts SET lines = $2 WHERE id = $1`, id, lines); err != nil {
+		return fmt.Errorf("failed to update contract lines: %w", err)
+	}
+
+	for _, event := range lineEvents {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO contract_outbox (event_id, contract_id, payload)
+			VALUES ($1, $2, $3)`,
+			event.ID, event.Data.ContractID, payload,
+		); err != nil {
+			return fmt.Errorf("failed to enqueue event %s: %w", event.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contract lines update: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateSLAs lets update edit the SLAs of the contract and stores only them, holding a lock on
+// the contract row from the moment it is read until the SLAs are stored
+func (r *ContractRepository) UpdateSLAs(id string, update func(contract *domain.Contract) (bool, error)) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin contract slas update: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, path, customer_id, slas, lines
+		FROM contracts
+		WHERE id = $1
+		FOR UPDATE`, id,
+	)
+
+	contract, err := scanContract(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("contract with id %s not found", id)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed, err := update(contract)
+	if err != nil || !changed {
+		return err
+	}
+
+	slas, err := marshalSLAs(contract.SLAs)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE contracts SET slas = $2 WHERE id = $1`, id, slas); err != nil {
+		return fmt.Errorf("failed to update contract slas: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contract slas update: %w", err)
+	}
+
+	return nil
+}
+
+// Claim locks the oldest waiting event of up to limit contracts, skipping the events other
+// callers hold, and keeps them locked while publish runs. Events behind an earlier one of their
+// contract are never selected, even when the earlier one is locked by another caller.
+func (r *ContractRepository) Claim(limit int, publish func(entries []domain.OutboxEntry) []error) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin outbox claim: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT payload, attempts, last_error, created_at
+		FROM contract_outbox o
+		WHERE NOT EXISTS (
+			SELECT 1 FROM contract_outbox earlier
+			WHERE earlier.contract_id = o.contract_id AND earlier.seq < o.seq
+		)
+		ORDER BY seq
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	entries, err := scanOutboxEntries(rows)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	results := publish(entries)
+
+	for i, entry := range entries {
+		if results[i] == nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM contract_outbox WHERE event_id = $1`, entry.Event.ID)
+		} else {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE contract_outbox
+				SET attempts = attempts + 1, last_error = $2
+				WHERE event_id = $1`,
+				entry.Event.ID, results[i].Error(),
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record outcome of event %s: %w", entry.Event.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit outbox claim: %w", err)
+	}
+
+	return nil
+}
+
+// scanOutboxEntries reads every claimed outbox row and closes the rows
+func scanOutboxEntries(rows *sql.Rows) ([]domain.OutboxEntry, error) {
+	defer rows.Close()
+
+	entries := make([]domain.OutboxEntry, 0)
+	for rows.Next() {
+		var (
+			entry   domain.OutboxEntry
+			payload []byte
+		)
+		if err := rows.Scan(&payload, &entry.Attempts, &entry.LastError, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		if err := json.Unmarshal(payload, &entry.Event); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox event: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox events: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Delete removes a contract by its ID
+func (r *ContractRepository) Delete(id string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM contracts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete contract: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("contract with id %s not found", id)
+	}
+
+	return nil
+}
+
+// Exists checks if a contract with the given ID exists
+func (r *ContractRepository) Exists(id string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM contracts WHERE id = $1)`, id,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanContract reads a contract row, decoding the JSONB SLA and line lists
+func scanContract(row rowScanner) (*domain.Contract, error) {
+	var (
+		contract domain.Contract
+		slas     []byte
+		lines    []byte
+	)
+
+	if err := row.Scan(&contract.ID, &contract.Path, &contract.CustomerID, &slas, &lines); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan contract: %w", err)
+	}
+
+	if err := json.Unmarshal(slas, &contract.SLAs); err != nil {
+		return nil, fmt.Errorf("failed to decode contract slas: %w", err)
+	}
+
+	if err := json.Unmarshal(lines, &contract.Lines); err != nil {
+		return nil, fmt.Errorf("failed to decode contract lines: %w", err)
+	}
+
+	return &contract, nil
+}
+
+// marshalSLAs encodes the SLA list of a contract for the JSONB column
+func marshalSLAs(slas []*domain.SLA) ([]byte, error) {
+	if slas == nil {
+		slas = []*domain.SLA{}
+	}
+
+	data, err := json.Marshal(slas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode contract slas: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalLines encodes the line list of a contract for the JSONB column
+func marshalLines(lines []domain.ContractLine) ([]byte, error) {
+	if lines == nil {
+		lines = []domain.ContractLine{}
+	}
+
+	data, err := json.Marshal(lines)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode contract lines: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// CustomerRepository is a PostgreSQL implementation of the Repository[string, domain.Customer] port
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+// Ensure CustomerRepository implements the Repository interface
+var _ driven.Repository[string, domain.Customer] = (*CustomerRepository)(nil)
+
+// NewCustomerRepository creates a new PostgreSQL customer repository
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{
+		db: db,
+	}
+}
+
+// Create adds a new customer to the repository
+func (r *CustomerRepository) Create(customer domain.Customer) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO customers (id, name, email, phone, preferred_channel)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING`,
+		customer.ID, customer.Name, customer.Email, customer.Phone, customer.PreferredChannel,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert customer: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("customer with id %s already exists", customer.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a customer by its ID
+func (r *CustomerRepository) FindByID(id string) (*domain.Customer, error) {
+	var customer domain.Customer
+
+	err := r.db.QueryRowContext(context.Background(), `
+		SELECT id, name, email, phone, preferred_channel
+		FROM customers
+		WHERE id = $1`, id,
+	).Scan(&customer.ID, &customer.Name, &customer.Email, &customer.Phone, &customer.PreferredChannel)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("customer with id %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query customer: %w", err)
+	}
+
+	return &customer, nil
+}
+
+// FindAll retrieves all customers
+func (r *CustomerRepository) FindAll() ([]domain.Customer, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, name, email, phone, preferred_channel
+		FROM customers
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query customers: %w", err)
+	}
+	defer rows.Close()
+
+	customers := make([]domain.Customer, 0)
+	for rows.Next() {
+		var customer domain.Customer
+		if err := rows.Scan(&customer.ID, &customer.Name, &customer.Email, &customer.Phone, &customer.PreferredChannel); err != nil {
+			return nil, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate customers: %w", err)
+	}
+
+	return customers, nil
+}
+
+// Update modifies an existing customer
+func (r *CustomerRepository) Update(customer domain.Customer) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE customers
+		SET name = $2, email = $3, phone = $4, preferred_channel = $5
+		WHERE id = $1`,
+		customer.ID, customer.Name, customer.Email, customer.Phone, customer.PreferredChannel,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("customer with id %s not found", customer.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a customer by its ID
+func (r *CustomerRepository) Delete(id string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("customer with id %s not found", id)
+	}
+
+	return nil
+}
+
+// Exists checks if a customer with the given ID exists
+func (r *CustomerRepository) Exists(id string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1)`, id,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// MedicineRepository is a PostgreSQL implementation of the Repository[string, domain.Medicine] port.
+// It holds the local copy of the medicine catalog of the suppliers service.
+type MedicineRepository struct {
+	db *sql.DB
+}
+
+// Ensure MedicineRepository implements the Repository interface
+var _ driven.Repository[string, domain.Medicine] = (*MedicineRepository)(nil)
+
+// NewMedicineRepository creates a new PostgreSQL medicine repository
+func NewMedicineRepository(db *sql.DB) *MedicineRepository {
+	return &MedicineRepository{
+		db: db,
+	}
+}
+
+// Create adds a new medicine to the repository
+func (r *MedicineRepository) Create(medicine domain.Medicine) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO medicines (id, name, description, price, strength, category, supplier_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING`,
+		medicine.ID, medicine.Name, medicine.Description, medicine.Price, medicine.Strength,
+		medicine.Category, medicine.SupplierID, medicine.CreatedAt, medicine.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("medicine with id %s already exists", medicine.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a medicine by its ID
+func (r *MedicineRepository) FindByID(id string) (*domain.Medicine, error) {
+	row := r.db.QueryRowContext(context.Background(), `
+		SELECT id, name, description, price, strength, category, supplier_id, created_at, updated_at
+		FROM medicines
+		WHERE id = $1`, id,
+	)
+
+	medicine, err := scanMedicine(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return medicine, nil
+}
+
+// FindAll retrieves all medicines, ordered by name
+func (r *MedicineRepository) FindAll() ([]domain.Medicine, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, name, description, price, strength, category, supplier_id, created_at, updated_at
+		FROM medicines
+		ORDER BY name, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query medicines: %w", err)
+	}
+	defer rows.Close()
+
+	medicines := make([]domain.Medicine, 0)
+	for rows.Next() {
+		medicine, err := scanMedicine(rows)
+		if err != nil {
+			return nil, err
+		}
+		medicines = append(medicines, *medicine)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate medicines: %w", err)
+	}
+
+	return medicines, nil
+}
+
+// Update modifies an existing medicine
+func (r *MedicineRepository) Update(medicine domain.Medicine) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE medicines
+		SET name = $2, description = $3, price = $4, strength = $5, category = $6,
+			supplier_id = $7, created_at = $8, updated_at = $9
+		WHERE id = $1`,
+		medicine.ID, medicine.Name, medicine.Description, medicine.Price, medicine.Strength,
+		medicine.Category, medicine.SupplierID, medicine.CreatedAt, medicine.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, medicine.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a medicine by its ID
+func (r *MedicineRepository) Delete(id string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM medicines WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	return nil
+}
+
+// Exists checks if a medicine with the given ID exists
+func (r *MedicineRepository) Exists(id string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM medicines WHERE id = $1)`, id,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
+
+// scanMedicine reads a medicine row
+func scanMedicine(row rowScanner) (*domain.Medicine, error) {
+	var medicine domain.Medicine
+
+	err := row.Scan(&medicine.ID, &medicine.Name, &medicine.Description, &medicine.Price, &medicine.Strength,
+		&medicine.Category, &medicine.SupplierID, &medicine.CreatedAt, &medicine.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan medicine: %w", err)
+	}
+
+	return &medicine, nil
+}
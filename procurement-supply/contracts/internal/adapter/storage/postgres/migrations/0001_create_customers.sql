@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id    TEXT PRIMARY KEY,
+    name  TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS contracts (
+    id          TEXT PRIMARY KEY,
+    path        TEXT  NOT NULL DEFAULT '',
+    customer_id TEXT  NOT NULL DEFAULT '',
+    slas        JSONB NOT NULL DEFAULT '[]'::jsonb
+);
+
+CREATE INDEX IF NOT EXISTS idx_contracts_customer_id ON contracts (customer_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS slas (
+    id          TEXT PRIMARY KEY,
+    name        TEXT     NOT NULL DEFAULT '',
+    description TEXT     NOT NULL DEFAULT '',
+    target      NUMERIC(78, 0),
+    comparator  SMALLINT NOT NULL DEFAULT 0,
+    status      SMALLINT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS transactions (
+    hash         TEXT PRIMARY KEY,
+    operation    TEXT        NOT NULL,
+    contract_id  TEXT        NOT NULL DEFAULT '',
+    sla_id       TEXT        NOT NULL DEFAULT '',
+    status       TEXT        NOT NULL,
+    block_number BIGINT      NOT NULL DEFAULT 0,
+    gas_used     BIGINT      NOT NULL DEFAULT 0,
+    error        TEXT        NOT NULL DEFAULT '',
+    submitted_at TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status);
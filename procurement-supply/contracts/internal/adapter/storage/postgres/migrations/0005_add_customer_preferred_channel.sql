@@ -0,0 +1 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS preferred_channel TEXT NOT NULL DEFAULT '';
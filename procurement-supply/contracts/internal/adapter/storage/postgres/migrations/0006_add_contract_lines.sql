@@ -0,0 +1 @@
+ALTER TABLE contracts ADD COLUMN IF NOT EXISTS lines JSONB NOT NULL DEFAULT '[]'::jsonb;
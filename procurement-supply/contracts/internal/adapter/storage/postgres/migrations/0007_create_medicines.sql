@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS medicines (
+    id          TEXT PRIMARY KEY,
+    name        TEXT             NOT NULL DEFAULT '',
+    description TEXT             NOT NULL DEFAULT '',
+    price       DOUBLE PRECISION NOT NULL DEFAULT 0,
+    strength    TEXT             NOT NULL DEFAULT '',
+    category    TEXT             NOT NULL DEFAULT '',
+    supplier_id TEXT             NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ      NOT NULL,
+    updated_at  TIMESTAMPTZ      NOT NULL
+);
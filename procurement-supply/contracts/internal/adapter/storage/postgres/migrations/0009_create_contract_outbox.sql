@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS contract_outbox (
+    seq         BIGSERIAL PRIMARY KEY,
+    event_id    TEXT        NOT NULL UNIQUE,
+    contract_id TEXT        NOT NULL,
+    payload     JSONB       NOT NULL,
+    attempts    INTEGER     NOT NULL DEFAULT 0,
+    last_error  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS contract_outbox_contract_id_seq_idx ON contract_outbox (contract_id, seq);
+
+CREATE INDEX IF NOT EXISTS contracts_lines_idx ON contracts USING GIN (lines jsonb_path_ops);
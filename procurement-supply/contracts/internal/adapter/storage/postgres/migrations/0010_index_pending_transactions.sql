@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_pending
+    ON transactions (operation, contract_id)
+    WHERE status IN ('pending', 'unknown');
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// SLARepository is a PostgreSQL implementation of the Repository[string, domain.SLA] port
+type SLARepository struct {
+	db *sql.DB
+}
+
+// Ensure SLARepository implements the Repository interface
+var _ driven.Repository[string, domain.SLA] = (*SLARepository)(nil)
+
+// NewSLARepository creates a new PostgreSQL SLA repository
+func NewSLARepository(db *sql.DB) *SLARepository {
+	return &SLARepository{
+		db: db,
+	}
+}
+
+// Create adds a new SLA to the repository
+func (r *SLARepository) Create(sla domain.SLA) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO slas (id, name, description, target, comparator, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING`,
+		sla.ID, sla.Name, sla.Description, targetValue(sla.Target), int16(sla.Comparator), int16(sla.Status),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert sla: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("sla with id %s already exists", sla.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an SLA by its ID
+func (r *SLARepository) FindByID(id string) (*domain.SLA, error) {
+	row := r.db.QueryRowContext(context.Background(), `
+		SELECT id, name, description, target, comparator, status
+		FROM slas
+		WHERE id = $1`, id,
+	)
+
+	sla, err := scanSLA(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("sla with id %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sla, nil
+}
+
+// FindAll retrieves all SLAs
+func (r *SLARepository) FindAll() ([]domain.SLA, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, name, description, target, comparator, status
+		FROM slas
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slas: %w", err)
+	}
+	defer rows.Close()
+
+	slas := make([]domain.SLA, 0)
+	for rows.Next() {
+		sla, err := scanSLA(rows)
+		if err != nil {
+			return nil, err
+		}
+		slas = append(slas, *sla)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate slas: %w", err)
+	}
+
+	return slas, nil
+}
+
+// Update modifies an existing SLA
+func (r *SLARepository) Update(sla domain.SLA) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE slas
+		SET name = $2, description = $3, target = $4, comparator = $5, status = $6
+		WHERE id = $1`,
+		sla.ID, sla.Name, sla.Description, targetValue(sla.Target), int16(sla.Comparator), int16(sla.Status),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update sla: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("sla with id %s not found", sla.ID)
+	}
+
+	return nil
+}
+
+// Delete removes an SLA by its ID
+func (r *SLARepository) Delete(id string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM slas WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sla: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("sla with id %s not found", id)
+	}
+
+	return nil
+}
+
+// Exists checks if an SLA with the given ID exists
+func (r *SLARepository) Exists(id string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM slas WHERE id = $1)`, id,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
+
+// scanSLA reads an SLA row, converting the NUMERIC target back into a big.Int
+func scanSLA(row rowScanner) (*domain.SLA, error) {
+	var (
+		sla        domain.SLA
+		target     sql.NullString
+		comparator int16
+		status     int16
+	)
+
+	if err := row.Scan(&sla.ID, &sla.Name, &sla.Description, &target, &comparator, &status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan sla: %w", err)
+	}
+
+	if target.Valid {
+		value, ok := new(big.Int).SetString(target.String, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid target %q for sla %s", target.String, sla.ID)
+		}
+		sla.Target = value
+	}
+
+	sla.Comparator = domain.Comparator(comparator)
+	sla.Status = domain.SLAStatus(status)
+
+	return &sla, nil
+}
+
+// targetValue converts an SLA target into a value accepted by the NUMERIC column
+func targetValue(target *big.Int) any {
+	if target == nil {
+		return nil
+	}
+	return target.String()
+}
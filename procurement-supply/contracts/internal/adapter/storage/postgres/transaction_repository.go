@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TransactionRepository is a PostgreSQL implementation of the TransactionRepository port.
+// Transactions are keyed by their hash.
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+// Ensure TransactionRepository implements the TransactionRepository interface
+var _ driven.TransactionRepository = (*TransactionRepository)(nil)
+
+// NewTransactionRepository creates a new PostgreSQL transaction repository
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{
+		db: db,
+	}
+}
+
+// Create adds a new transaction to the repository
+func (r *TransactionRepository) Create(tx domain.Transaction) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO transactions (hash, operation, contract_id, sla_id, status, block_number, gas_used, error, payload, submitted_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (hash) DO NOTHING`,
+		tx.Hash, tx.Operation, tx.ContractID, tx.SLAID, string(tx.Status),
+		int64(tx.BlockNumber), int64(tx.GasUsed), tx.Error, payloadValue(tx.Payload), tx.SubmittedAt, tx.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert transaction: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("transaction with hash %s already exists", tx.Hash)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a transaction by its hash
+func (r *TransactionRepository) FindByID(hash string) (*domain.Transaction, error) {
+	row := r.db.QueryRowContext(context.Background(), `
+		SELECT hash, operation, contract_id, sla_id, status, block_number, gas_used, error, payload, submitted_at, updated_at
+		FROM transactions
+		WHERE hash = $1`, hash,
+	)
+
+	tx, err := scanTransaction(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("transaction with hash %s not found", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// FindAll retrieves all transactions, most recent first
+func (r *TransactionRepository) FindAll() ([]domain.Transaction, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT hash, operation, contract_id, sla_id, status, block_number, gas_used, error, payload, submitted_at, updated_at
+		FROM transactions
+		ORDER BY submitted_at DESC`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+
+	return scanTransactions(rows)
+}
+
+// FindPending retrieves the transactions not in a final state, oldest first
+func (r *TransactionRepository) FindPending() ([]domain.Transaction, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT hash, operation, contract_id, sla_id, status, block_number, gas_used, error, payload, submitted_at, updated_at
+		FROM transactions
+		WHERE status IN ($1, $2)
+		ORDER BY submitted_at`,
+		string(domain.TransactionPending), string(domain.TransactionUnknown),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending transactions: %w", err)
+	}
+
+	return scanTransactions(rows)
+}
+
+// HasPending reports whether a transaction of the operation on the contract is not in a final state
+func (r *TransactionRepository) HasPending(operation, contractID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(), `
+		SELECT EXISTS (
+			SELECT 1 FROM transactions
+			WHERE operation = $1 AND contract_id = $2 AND status IN ($3, $4)
+		)`,
+		operation, contractID, string(domain.TransactionPending), string(domain.TransactionUnknown),
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to query pending transactions: %w", err)
+	}
+
+	return exists, nil
+}
+
+// scanTransactions reads every transaction row and closes the rows
+func scanTransactions(rows *sql.Rows) ([]domain.Transaction, error) {
+	defer rows.Close()
+
+	transactions := make([]domain.Transaction, 0)
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, *tx)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// Update modifies an existing transaction
+func (r *TransactionRepository) Update(tx domain.Transaction) error {
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE transactions
+		SET operation = $2, contract_id = $3, sla_id = $4, status = $5, block_number = $6,
+		    gas_used = $7, error = $8, payload = $9, submitted_at = $10, updated_at = $11
+		WHERE hash = $1`,
+		tx.Hash, tx.Operation, tx.ContractID, tx.SLAID, string(tx.Status),
+		int64(tx.BlockNumber), int64(tx.GasUsed), tx.Error, payloadValue(tx.Payload), tx.SubmittedAt, tx.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("transaction with hash %s not found", tx.Hash)
+	}
+
+	return nil
+}
+
+// Delete removes a transaction by its hash
+func (r *TransactionRepository) Delete(hash string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM transactions WHERE hash = $1`, hash)
+	if err != nil {
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("transaction with hash %s not found", hash)
+	}
+
+	return nil
+}
+
+// Exists checks if a transaction with the given hash exists
+func (r *TransactionRepository) Exists(hash string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM transactions WHERE hash = $1)`, hash,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
+
+// scanTransaction reads a transaction row
+func scanTransaction(row rowScanner) (*domain.Transaction, error) {
+	var (
+		tx          domain.Transaction
+		status      string
+		blockNumber int64
+		gasUsed     int64
+		payload     []byte
+	)
+
+	err := row.Scan(&tx.Hash, &tx.Operation, &tx.ContractID, &tx.SLAID, &status,
+		&blockNumber, &gasUsed, &tx.Error, &payload, &tx.SubmittedAt, &tx.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan transaction: %w", err)
+	}
+
+	tx.Status = domain.TransactionStatus(status)
+	tx.BlockNumber = uint64(blockNumber)
+	tx.GasUsed = uint64(gasUsed)
+	tx.Payload = payload
+
+	return &tx, nil
+}
+
+// payloadValue stores an empty payload as NULL
+func payloadValue(payload []byte) any {
+	if len(payload) == 0 {
+		return nil
+	}
+	return payload
+}
@@ -0,0 +1,836 @@
+// Package storagetest holds the contract test suite every driven.Repository adapter must pass
+package storagetest
+
+import (
+	"cmp"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Fixture describes how the suite builds and changes the entities stored by a repository
+type Fixture[T any] struct {
+	// New returns an entity with the given ID and every field set
+	New func(id string) T
+	// Modify returns the entity with every field but the ID changed
+	Modify func(entity T) T
+	// ID returns the ID of the entity
+	ID func(entity T) string
+}
+
+// ContractFixture builds contracts with embedded SLAs and lines
+var ContractFixture = Fixture[domain.Contract]{
+	New: func(id string) domain.Contract {
+		sla := SLAFixture.New(id + "-sla")
+		return domain.Contract{
+			ID:         id,
+			Path:       "/contracts/" + id,
+			CustomerID: "customer-1",
+			SLAs:       []*domain.SLA{&sla},
+			Lines: []domain.ContractLine{{
+				MedicineID:  "med-1",
+				AgreedPrice: 12.5,
+				Status:      domain.ContractLineActive,
+				UpdatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			}},
+		}
+	},
+	Modify: func(contract domain.Contract) domain.Contract {
+		contract.Path += "/v2"
+		contract.CustomerID = "customer-2"
+		contract.SLAs = []*domain.SLA{}
+		contract.Lines = append(contract.Lines, domain.ContractLine{
+			MedicineID:   "med-2",
+			MedicineName: "Ibuprofen",
+			AgreedPrice:  3,
+			CatalogPrice: 3.3,
+			Drift:        0.1,
+			Status:       domain.ContractLinePriceDrift,
+			UpdatedAt:    time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC),
+		})
+		return contract
+	},
+	ID: func(contract domain.Contract) string { return contract.ID },
+}
+
+// CustomerFixture builds customers
+var CustomerFixture = Fixture[domain.Customer]{
+	New: func(id string) domain.Customer {
+		return domain.Customer{
+			ID:               id,
+			Name:             "Hospital " + id,
+			Email:            id + "@hospital.test",
+			Phone:            "+15550100",
+			PreferredChannel: domain.ChannelEmail,
+		}
+	},
+	Modify: func(customer domain.Customer) domain.Customer {
+		customer.Name += " (renamed)"
+		customer.Email = "billing-" + customer.Email
+		customer.Phone = "+15550199"
+		customer.PreferredChannel = domain.ChannelSMS
+		return customer
+	},
+	ID: func(customer domain.Customer) string { return customer.ID },
+}
+
+// SLAFixture builds SLAs with targets above the range of an int64
+var SLAFixture = Fixture[domain.SLA]{
+	New: func(id string) domain.SLA {
+		target, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+		return domain.SLA{
+			ID:          id,
+			Name:        "Delivery time",
+			Description: "Deliveries within 48 hours",
+			Target:      target,
+			Comparator:  domain.LessOrEqual,
+			Status:      domain.Active,
+		}
+	},
+	Modify: func(sla domain.SLA) domain.SLA {
+		sla.Name += " (revised)"
+		sla.Description = "Deliveries within 24 hours"
+		sla.Target = big.NewInt(24)
+		sla.Comparator = domain.LessThan
+		sla.Status = domain.Violated
+		return sla
+	},
+	ID: func(sla domain.SLA) string { return sla.ID },
+}
+
+// MedicineFixture builds medicines of the local catalog
+var MedicineFixture = Fixture[domain.Medicine]{
+	New: func(id string) domain.Medicine {
+		return domain.Medicine{
+			ID:          id,
+			Name:        "Ibuprofen " + id,
+			Description: "Anti-inflammatory",
+			Price:       3.5,
+			Strength:    "400mg",
+			Category:    "analgesic",
+			SupplierID:  "supplier-1",
+			CreatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			UpdatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	},
+	Modify: func(medicine domain.Medicine) domain.Medicine {
+		medicine.Name += " (renamed)"
+		medicine.Description = "Pain reliever"
+		medicine.Price = 4.25
+		medicine.Strength = "600mg"
+		medicine.Category = "anti-inflammatory"
+		medicine.SupplierID = "supplier-2"
+		medicine.UpdatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+		return medicine
+	},
+	ID: func(medicine domain.Medicine) string { return medicine.ID },
+}
+
+// TransactionFixture builds pending transactions, moved to a final state by Modify
+var TransactionFixture = Fixture[domain.Transaction]{
+	New: func(hash string) domain.Transaction {
+		return domain.Transaction{
+			Hash:        hash,
+			Operation:   domain.OperationAddContract,
+			ContractID:  "contract-1",
+			Status:      domain.TransactionPending,
+			Payload:     json.RawMessage(`{"id":"contract-1"}`),
+			SubmittedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			UpdatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	},
+	Modify: func(tx domain.Transaction) domain.Transaction {
+		tx.Operation = domain.OperationAddSLA
+		tx.ContractID = "contract-2"
+		tx.SLAID = "sla-1"
+		tx.Status = domain.TransactionFailed
+		tx.BlockNumber = 42
+		tx.GasUsed = 21000
+		tx.Error = "transaction reverted (status: 0)"
+		tx.UpdatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+		return tx
+	},
+	ID: func(tx domain.Transaction) string { return tx.Hash },
+}
+
+// RunRepositoryTests runs the repository contract test suite. newRepository must return an
+// empty repository on every call.
+func RunRepositoryTests[T any](t *testing.T, newRepository func(t *testing.T) driven.Repository[string, T], fixture Fixture[T]) {
+	t.Run("create and find", func(t *testing.T) {
+		repo := newRepository(t)
+		entity := fixture.New("entity-1")
+
+		if err := repo.Create(entity); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if !repo.Exists("entity-1") {
+			t.Error("Exists() = false after Create()")
+		}
+
+		found, err := repo.FindByID("entity-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, entity)
+	})
+
+	t.Run("create duplicate", func(t *testing.T) {
+		repo := newRepository(t)
+		entity := fixture.New("entity-1")
+
+		if err := repo.Create(entity); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.Create(fixture.Modify(entity)); err == nil {
+			t.Fatal("Create() of an existing ID error = nil")
+		}
+
+		// The first entity is kept
+		found, err := repo.FindByID("entity-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, entity)
+	})
+
+	t.Run("missing entity", func(t *testing.T) {
+		repo := newRepository(t)
+
+		if repo.Exists("missing") {
+			t.Error("Exists() = true for a missing ID")
+		}
+		if _, err := repo.FindByID("missing"); err == nil {
+			t.Error("FindByID() of a missing ID error = nil")
+		}
+		if err := repo.Update(fixture.New("missing")); err == nil {
+			t.Error("Update() of a missing ID error = nil")
+		}
+		if err := repo.Delete("missing"); err == nil {
+			t.Error("Delete() of a missing ID error = nil")
+		}
+		if repo.Exists("missing") {
+			t.Error("Update() of a missing ID created it")
+		}
+	})
+
+	t.Run("find all", func(t *testing.T) {
+		repo := newRepository(t)
+
+		all, err := repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(all) != 0 {
+			t.Fatalf("FindAll() on an empty repository returned %d entities", len(all))
+		}
+
+		want := []T{fixture.New("entity-1"), fixture.New("entity-2"), fixture.New("entity-3")}
+		for _, entity := range want {
+			if err := repo.Create(entity); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		all, err = repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(all) != len(want) {
+			t.Fatalf("FindAll() returned %d entities, want %d", len(all), len(want))
+		}
+
+		// The order is up to the adapter
+		slices.SortFunc(all, func(a, b T) int {
+			return cmp.Compare(fixture.ID(a), fixture.ID(b))
+		})
+		for i := range want {
+			assertEqual(t, all[i], want[i])
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepository(t)
+		entity := fixture.New("entity-1")
+		other := fixture.New("entity-2")
+
+		for _, e := range []T{entity, other} {
+			if err := repo.Create(e); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		updated := fixture.Modify(entity)
+		if err := repo.Update(updated); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		found, err := repo.FindByID("entity-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, updated)
+
+		// Other entities are left untouched
+		found, err = repo.FindByID("entity-2")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, other)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepository(t)
+
+		for _, id := range []string{"entity-1", "entity-2"} {
+			if err := repo.Create(fixture.New(id)); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		if err := repo.Delete("entity-1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if repo.Exists("entity-1") {
+			t.Error("Exists() = true after Delete()")
+		}
+		if _, err := repo.FindByID("entity-1"); err == nil {
+			t.Error("FindByID() after Delete() error = nil")
+		}
+		if err := repo.Delete("entity-1"); err == nil {
+			t.Error("second Delete() error = nil")
+		}
+		if !repo.Exists("entity-2") {
+			t.Error("Delete() removed another entity")
+		}
+	})
+}
+
+// assertEqual compares entities through their JSON form, which is what the API exposes and
+// ignores representation details such as the monotonic clock reading of a time
+func assertEqual[T any](t *testing.T, got, want T) {
+	t.Helper()
+
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", got, err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", want, err)
+	}
+
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
+
+// ContractStore is a contract repository along with the outbox of the line events it stores
+type ContractStore interface {
+	driven.ContractRepository
+	driven.ContractEventOutbox
+}
+
+// RunContractRepositoryTests runs the repository contract test suite for contracts, along with the
+// tests of FindByMedicine, UpdateLines and the outbox. newRepository must return an empty
+// repository with an empty outbox on every call.
+func RunContractRepositoryTests(t *testing.T, newRepository func(t *testing.T) ContractStore) {
+	RunRepositoryTests(t, func(t *testing.T) driven.Repository[string, domain.Contract] {
+		return newRepository(t)
+	}, ContractFixture)
+
+	t.Run("find by medicine", func(t *testing.T) {
+		repo := newRepository(t)
+		first := ContractFixture.New("contract-1")
+		second := ContractFixture.Modify(ContractFixture.New("contract-2"))
+		for _, contract := range []domain.Contract{first, second} {
+			if err := repo.Create(contract); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		for medicineID, want := range map[string][]domain.Contract{
+			"med-1":   {first, second},
+			"med-2":   {second},
+			"missing": {},
+		} {
+			found, err := repo.FindByMedicine(medicineID)
+			if err != nil {
+				t.Fatalf("FindByMedicine(%s) error = %v", medicineID, err)
+			}
+			slices.SortFunc(found, func(a, b domain.Contract) int { return cmp.Compare(a.ID, b.ID) })
+			assertEqual(t, found, want)
+		}
+	})
+
+	t.Run("update lines stores only the lines", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		want := ContractFixture.Modify(contract)
+		err := repo.UpdateLines("contract-1", func(stored *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+			assertEqual(t, *stored, contract)
+
+			*stored = want
+			stored.ID = "contract-1"
+			return true, nil, nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateLines() error = %v", err)
+		}
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		contract.Lines = want.Lines
+		assertEqual(t, *found, contract)
+	})
+
+	t.Run("update lines without changes", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		errUpdate := errors.New("update failed")
+		for _, result := range []error{nil, errUpdate} {
+			err := repo.UpdateLines("contract-1", func(stored *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+				stored.Lines = nil
+				return result != nil, []*domain.Event[domain.ContractLineChange]{lineEvent(stored, 0)}, result
+			})
+			if !errors.Is(err, result) {
+				t.Errorf("UpdateLines() error = %v, want %v", err, result)
+			}
+		}
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, contract)
+		assertClaimed(t, repo, nil)
+	})
+
+	t.Run("update lines of a missing contract", func(t *testing.T) {
+		repo := newRepository(t)
+
+		err := repo.UpdateLines("missing", func(*domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+			t.Error("update called for a missing contract")
+			return false, nil, nil
+		})
+		if err == nil {
+			t.Error("UpdateLines() of a missing ID error = nil")
+		}
+	})
+
+	t.Run("concurrent line updates", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		contract.Lines = []domain.ContractLine{}
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		// Every update appends a line, none may be lost
+		const updates = 8
+		var wg sync.WaitGroup
+		for i := range updates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := repo.UpdateLines("contract-1", func(stored *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+					stored.Lines = append(stored.Lines, domain.ContractLine{
+						MedicineID:  fmt.Sprintf("med-%d", i),
+						AgreedPrice: 1,
+						Status:      domain.ContractLineActive,
+					})
+					return true, nil, nil
+				})
+				if err != nil {
+					t.Errorf("UpdateLines() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if len(found.Lines) != updates {
+			t.Errorf("contract has %d lines after %d concurrent updates", len(found.Lines), updates)
+		}
+	})
+
+	t.Run("update slas stores only the slas", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		sla := SLAFixture.Modify(SLAFixture.New("sla-2"))
+		err := repo.UpdateSLAs("contract-1", func(stored *domain.Contract) (bool, error) {
+			assertEqual(t, *stored, contract)
+
+			// The stored SLAs are unchanged until the update is stored
+			stored.SLAs[0].Name = "edited"
+			stored.SLAs = append(stored.SLAs, &sla)
+			stored.Lines = nil
+			stored.Path = "/ignored"
+			return true, nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateSLAs() error = %v", err)
+		}
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		first := *contract.SLAs[0]
+		first.Name = "edited"
+		contract.SLAs = []*domain.SLA{&first, &sla}
+		assertEqual(t, *found, contract)
+	})
+
+	t.Run("update slas without changes", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		errUpdate := errors.New("update failed")
+		for _, result := range []error{nil, errUpdate} {
+			err := repo.UpdateSLAs("contract-1", func(stored *domain.Contract) (bool, error) {
+				stored.SLAs[0].Name = "edited"
+				stored.SLAs = nil
+				return result != nil, result
+			})
+			if !errors.Is(err, result) {
+				t.Errorf("UpdateSLAs() error = %v, want %v", err, result)
+			}
+		}
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, contract)
+	})
+
+	t.Run("update slas of a missing contract", func(t *testing.T) {
+		repo := newRepository(t)
+
+		err := repo.UpdateSLAs("missing", func(*domain.Contract) (bool, error) {
+			t.Error("update called for a missing contract")
+			return false, nil
+		})
+		if err == nil {
+			t.Error("UpdateSLAs() of a missing ID error = nil")
+		}
+	})
+
+	t.Run("concurrent sla and line updates", func(t *testing.T) {
+		repo := newRepository(t)
+		contract := ContractFixture.New("contract-1")
+		contract.SLAs = []*domain.SLA{}
+		contract.Lines = []domain.ContractLine{}
+		if err := repo.Create(contract); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		// Every update appends an SLA or a line, none may be lost
+		const updates = 8
+		var wg sync.WaitGroup
+		for i := range updates {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+
+				err := repo.UpdateSLAs("contract-1", func(stored *domain.Contract) (bool, error) {
+					sla := SLAFixture.New(fmt.Sprintf("sla-%d", i))
+					stored.SLAs = append(stored.SLAs, &sla)
+					return true, nil
+				})
+				if err != nil {
+					t.Errorf("UpdateSLAs() error = %v", err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+
+				err := repo.UpdateLines("contract-1", func(stored *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+					stored.Lines = append(stored.Lines, domain.ContractLine{
+						MedicineID:  fmt.Sprintf("med-%d", i),
+						AgreedPrice: 1,
+						Status:      domain.ContractLineActive,
+					})
+					return true, nil, nil
+				})
+				if err != nil {
+					t.Errorf("UpdateLines() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		found, err := repo.FindByID("contract-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if len(found.SLAs) != updates || len(found.Lines) != updates {
+			t.Errorf("contract has %d SLAs and %d lines after %d concurrent updates of each", len(found.SLAs), len(found.Lines), updates)
+		}
+	})
+
+	t.Run("claim the oldest event of each contract", func(t *testing.T) {
+		repo := newRepository(t)
+		first := enqueue(t, repo, "contract-1", 2)
+		second := enqueue(t, repo, "contract-2", 1)
+
+		// A failed event is kept and still blocks the later events of its contract
+		errPublish := errors.New("publish failed")
+		err := repo.Claim(10, func(entries []domain.OutboxEntry) []error {
+			assertEventIDs(t, entries, first[0], second[0])
+			return []error{errPublish, nil}
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+
+		err = repo.Claim(10, func(entries []domain.OutboxEntry) []error {
+			assertEventIDs(t, entries, first[0])
+			if entries[0].Attempts != 1 || entries[0].LastError != errPublish.Error() {
+				t.Errorf("failed entry attempts = %d, last error = %q", entries[0].Attempts, entries[0].LastError)
+			}
+			if entries[0].Event.Data.ContractID != "contract-1" || entries[0].Event.EventType != domain.ContractLinePriceDriftedEvent {
+				t.Errorf("claimed event = %+v", entries[0].Event)
+			}
+			return []error{nil}
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+
+		assertClaimed(t, repo, []string{first[1]})
+		assertClaimed(t, repo, nil)
+	})
+
+	t.Run("claim at most limit events", func(t *testing.T) {
+		repo := newRepository(t)
+		first := enqueue(t, repo, "contract-1", 1)
+		enqueue(t, repo, "contract-2", 1)
+
+		err := repo.Claim(1, func(entries []domain.OutboxEntry) []error {
+			assertEventIDs(t, entries, first[0])
+			return []error{errors.New("publish failed")}
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+	})
+
+	t.Run("concurrent claims", func(t *testing.T) {
+		repo := newRepository(t)
+		first := enqueue(t, repo, "contract-1", 2)
+		second := enqueue(t, repo, "contract-2", 1)
+
+		// While the first claim publishes, a second one gets neither its events nor the events behind them
+		err := repo.Claim(1, func(entries []domain.OutboxEntry) []error {
+			assertEventIDs(t, entries, first[0])
+
+			done := make(chan error)
+			go func() {
+				done <- repo.Claim(10, func(entries []domain.OutboxEntry) []error {
+					assertEventIDs(t, entries, second[0])
+					return []error{errors.New("publish failed")}
+				})
+			}()
+			if err := <-done; err != nil {
+				t.Errorf("concurrent Claim() error = %v", err)
+			}
+
+			return []error{nil}
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+
+		assertClaimed(t, repo, []string{first[1], second[0]})
+	})
+}
+
+// lineEvent returns an event announcing a change of the i-th line of the contract
+func lineEvent(contract *domain.Contract, i int) *domain.Event[domain.ContractLineChange] {
+	line := domain.ContractLine{}
+	if i < len(contract.Lines) {
+		line = contract.Lines[i]
+	}
+
+	event := domain.NewContractLineEvent(domain.ContractLineChange{
+		ContractID: contract.ID,
+		CustomerID: contract.CustomerID,
+		Line:       line,
+	}, domain.ContractLinePriceDriftedEvent)
+	event.CorrelationID = "correlation-1"
+	return event
+}
+
+// enqueue creates the contract and stores the given number of line events for it, one update
+// at a time, and returns their IDs in order
+func enqueue(t *testing.T, repo ContractStore, contractID string, count int) []string {
+	t.Helper()
+
+	if err := repo.Create(ContractFixture.New(contractID)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	ids := make([]string, 0, count)
+	for range count {
+		err := repo.UpdateLines(contractID, func(stored *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+			event := lineEvent(stored, 0)
+			ids = append(ids, event.ID)
+			return true, []*domain.Event[domain.ContractLineChange]{event}, nil
+		})
+		if err != nil {
+			t.Fatalf("UpdateLines() error = %v", err)
+		}
+	}
+
+	return ids
+}
+
+// assertClaimed claims every waiting event, publishing them successfully, and checks their IDs
+func assertClaimed(t *testing.T, repo ContractStore, want []string) {
+	t.Helper()
+
+	var got []string
+	err := repo.Claim(100, func(entries []domain.OutboxEntry) []error {
+		for _, entry := range entries {
+			got = append(got, entry.Event.ID)
+		}
+		return make([]error, len(entries))
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	slices.Sort(got)
+	want = slices.Sorted(slices.Values(want))
+	if !slices.Equal(got, want) {
+		t.Errorf("claimed %v, want %v", got, want)
+	}
+}
+
+// assertEventIDs checks the IDs of the claimed events, in order
+func assertEventIDs(t *testing.T, entries []domain.OutboxEntry, want ...string) {
+	t.Helper()
+
+	got := make([]string, len(entries))
+	for i, entry := range entries {
+		got[i] = entry.Event.ID
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("claimed %v, want %v", got, want)
+	}
+}
+
+// RunMedicineRepositoryTests runs the repository contract test suite for medicines, along with
+// the errors of missing medicines, which must wrap domain.ErrMedicineNotFound
+func RunMedicineRepositoryTests(t *testing.T, newRepository func(t *testing.T) driven.Repository[string, domain.Medicine]) {
+	RunRepositoryTests(t, newRepository, MedicineFixture)
+
+	t.Run("missing medicine error", func(t *testing.T) {
+		repo := newRepository(t)
+
+		if _, err := repo.FindByID("missing"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("FindByID() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+		if err := repo.Update(MedicineFixture.New("missing")); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("Update() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+		if err := repo.Delete("missing"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("Delete() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+	})
+}
+
+// RunTransactionRepositoryTests runs the repository contract test suite for transactions, along
+// with the tests of the pending transaction queries. newRepository must return an empty repository
+// on every call.
+func RunTransactionRepositoryTests(t *testing.T, newRepository func(t *testing.T) driven.TransactionRepository) {
+	RunRepositoryTests(t, func(t *testing.T) driven.Repository[string, domain.Transaction] {
+		return newRepository(t)
+	}, TransactionFixture)
+
+	t.Run("pending transactions", func(t *testing.T) {
+		repo := newRepository(t)
+
+		pending := TransactionFixture.New("0x1")
+		unknown := TransactionFixture.New("0x2")
+		unknown.Operation = domain.OperationAddSLA
+		unknown.Status = domain.TransactionUnknown
+		final := TransactionFixture.Modify(TransactionFixture.New("0x3"))
+		final.ContractID = "contract-1"
+		for _, status := range []domain.TransactionStatus{domain.TransactionMined, domain.TransactionFailed, domain.TransactionDropped} {
+			final.Hash += "0"
+			final.Status = status
+			if err := repo.Create(final); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+		for _, tx := range []domain.Transaction{pending, unknown} {
+			if err := repo.Create(tx); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		found, err := repo.FindPending()
+		if err != nil {
+			t.Fatalf("FindPending() error = %v", err)
+		}
+		slices.SortFunc(found, func(a, b domain.Transaction) int { return cmp.Compare(a.Hash, b.Hash) })
+		assertEqual(t, found, []domain.Transaction{pending, unknown})
+
+		for _, tt := range []struct {
+			operation  string
+			contractID string
+			want       bool
+		}{
+			{domain.OperationAddContract, "contract-1", true},
+			{domain.OperationAddSLA, "contract-1", true},
+			{domain.OperationAddSLA, "contract-2", false},
+		} {
+			got, err := repo.HasPending(tt.operation, tt.contractID)
+			if err != nil {
+				t.Fatalf("HasPending() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPending(%s, %s) = %v, want %v", tt.operation, tt.contractID, got, tt.want)
+			}
+		}
+
+		// Once final, a transaction is no longer pending
+		pending.Status = domain.TransactionMined
+		if err := repo.Update(pending); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if got, err := repo.HasPending(domain.OperationAddContract, "contract-1"); got || err != nil {
+			t.Errorf("HasPending() of a mined transaction = %v, %v", got, err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// TransactionNotifier posts the final state of tracked transactions to a webhook URL
+type TransactionNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// Ensure TransactionNotifier implements the driven.TransactionNotifier interface
+var _ driven.TransactionNotifier = (*TransactionNotifier)(nil)
+
+// NewTransactionNotifier creates a new webhook notifier for the given URL
+func NewTransactionNotifier(url string, timeout time.Duration) *TransactionNotifier {
+	return &TransactionNotifier{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// NotifyTransaction sends the transaction as a JSON body, with the trace context of ctx in the
+// W3C traceparent header. Any non-2xx response is an error.
+func (n *TransactionNotifier) NotifyTransaction(ctx context.Context, tx domain.Transaction) error {
+	body, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"contracts/internal/core/port/driven"
 	"contracts/internal/core/port/driver"
 	"contracts/pkg/logger"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,43 +18,82 @@ import (
 
 // ContractService handles business logic for contract management
 type ContractService struct {
-	repository       driven.Repository[string, domain.Contract]
+	repository       driven.ContractRepository
+	medicines        driven.Repository[string, domain.Medicine] // catalog lines are checked against, if any
 	blockchainWriter driven.BlockchainWriter
 	blockchainReader driven.BlockchainReader
+	transactions     driver.TransactionService
 	logger           *zap.SugaredLogger
 	idSeq            int64
+	registering      map[string]struct{} // contract IDs whose registration is being sent
+	mu               sync.Mutex
 }
 
 // Ensure ContractService implements the driver.ContractService interface
 var _ driver.ContractService = (*ContractService)(nil)
 
-// NewContractService creates a new contract service
-func NewContractService(repository driven.Repository[string, domain.Contract], writer driven.BlockchainWriter, reader driven.BlockchainReader) *ContractService {
-	return &ContractService{
+// NewContractService creates a new contract service. Contract lines are checked against the
+// medicine catalog, unless medicines is nil.
+func NewContractService(
+	repository driven.ContractRepository,
+	medicines driven.Repository[string, domain.Medicine],
+	writer driven.BlockchainWriter,
+	reader driven.BlockchainReader,
+	transactions driver.TransactionService,
+) *ContractService {
+	s := &ContractService{
 		repository:       repository,
+		medicines:        medicines,
 		blockchainWriter: writer,
 		blockchainReader: reader,
+		transactions:     transactions,
 		logger:           logger.New("CONTRACT-SERVICE"),
 		idSeq:            time.Now().UnixNano(),
+		registering:      make(map[string]struct{}),
 	}
+
+	transactions.HandleConfirmed(domain.OperationAddContract, s.confirmContract)
+	transactions.HandleConfirmed(domain.OperationAddSLA, s.confirmSLA)
+
+	return s
 }
 
-// CreateContract creates a new contract and registers it on the blockchain
-func (s *ContractService) CreateContract(ctx context.Context, contract domain.Contract) (*domain.Contract, error) {
+// CreateContract sends the transaction registering a new contract on the blockchain.
+// The contract is only stored in the repository once the transaction is confirmed, so its
+// ID stays reserved from before the transaction is sent until it reaches a final state.
+func (s *ContractService) CreateContract(ctx context.Context, contract domain.Contract) (*domain.Submission[domain.Contract], error) {
 	// Generate ID if empty
 	if contract.ID == "" {
-		s.idSeq++
-		contract.ID = strconv.FormatInt(s.idSeq, 10)
+		contract.ID = s.nextID()
 	}
 
+	if !s.reserve(contract.ID) {
+		return nil, fmt.Errorf("contract with id %s is already being registered", contract.ID)
+	}
+	defer s.unreserve(contract.ID)
+
 	// Check if the contract already exists
 	if s.repository.Exists(contract.ID) {
 		return nil, fmt.Errorf("contract with id %s already exists", contract.ID)
 	}
 
-	// Save to repository
-	if err := s.repository.Create(contract); err != nil {
-		return nil, fmt.Errorf("failed to create contract in repository: %w", err)
+	// Check if a previous registration is still waiting to be mined
+	pending, err := s.transactions.IsPending(domain.OperationAddContract, contract.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, fmt.Errorf("contract with id %s is already being registered", contract.ID)
+	}
+
+	if err := s.checkLines(contract.Lines); err != nil {
+		return nil, err
+	}
+	contract.Lines = normalizeLines(contract.Lines)
+
+	payload, err := json.Marshal(contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode contract: %w", err)
 	}
 
 	// Register on blockchain
@@ -60,28 +102,31 @@ func (s *ContractService) CreateContract(ctx context.Context, contract domain.Co
 		"customer_id", contract.CustomerID,
 		"path", contract.Path,
 	)
-	receipt, err := s.blockchainWriter.AddContract(ctx, contract.ID, contract.Path, contract.CustomerID)
+	txHash, err := s.blockchainWriter.AddContract(ctx, contract.ID, contract.Path, contract.CustomerID)
 	if err != nil {
-		// Rollback repository changes
-		_ = s.repository.Delete(contract.ID)
 		return nil, fmt.Errorf("failed to write contract to blockchain: %w", err)
 	}
 
-	// Check transaction status
-	if receipt.Status != 1 {
-		// Rollback repository changes
-		_ = s.repository.Delete(contract.ID)
-		return nil, fmt.Errorf("blockchain transaction failed (status: %d, tx: %s)", receipt.Status, receipt.TxHash)
+	// Promote the contract into the repository once the transaction is mined
+	tx, err := s.transactions.TrackTransaction(ctx, domain.Transaction{
+		Hash:       txHash,
+		Operation:  domain.OperationAddContract,
+		ContractID: contract.ID,
+		Payload:    payload,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Infow("Contract successfully registered on blockchain",
+	s.logger.Infow("Contract registration submitted to blockchain",
 		"contract_id", contract.ID,
-		"tx_hash", receipt.TxHash,
-		"block_number", receipt.BlockNumber,
-		"gas_used", receipt.GasUsed,
+		"tx_hash", tx.Hash,
 	)
 
-	return &contract, nil
+	return &domain.Submission[domain.Contract]{
+		Data:        contract,
+		Transaction: *tx,
+	}, nil
 }
 
 // RetrieveContract retrieves a contract by ID
@@ -92,6 +137,13 @@ func (s *ContractService) RetrieveContract(ctx context.Context, id string) (*dom
 		return nil, fmt.Errorf("contract not found: %w", err)
 	}
 
+	// Lines are only kept off chain
+	if s.repository.Exists(id) {
+		if stored, err := s.repository.FindByID(id); err == nil {
+			contract.Lines = stored.Lines
+		}
+	}
+
 	return contract, nil
 }
 
@@ -103,6 +155,20 @@ func (s *ContractService) RetrieveContracts(ctx context.Context) ([]*domain.Cont
 		return nil, fmt.Errorf("failed to retrieve contracts: %w", err)
 	}
 
+	// Lines are only kept off chain
+	stored, err := s.repository.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve contract lines: %w", err)
+	}
+
+	lines := make(map[string][]domain.ContractLine, len(stored))
+	for _, contract := range stored {
+		lines[contract.ID] = contract.Lines
+	}
+	for _, contract := range contracts {
+		contract.Lines = lines[contract.ID]
+	}
+
 	return contracts, nil
 }
 
@@ -113,6 +179,11 @@ func (s *ContractService) UpdateContract(contract domain.Contract) (*domain.Cont
 		return nil, fmt.Errorf("contract with id %s not found", contract.ID)
 	}
 
+	if err := s.checkLines(contract.Lines); err != nil {
+		return nil, err
+	}
+	contract.Lines = normalizeLines(contract.Lines)
+
 	// Update in repository
 	if err := s.repository.Update(contract); err != nil {
 		return nil, fmt.Errorf("failed to update contract: %w", err)
@@ -142,7 +213,9 @@ func (s *ContractService) DeleteContract(id string) error {
 	return nil
 }
 
-func (s *ContractService) CreateSLA(ctx context.Context, id string, sla domain.SLA) (*domain.SLA, error) {
+// CreateSLA sends the transaction appending an SLA to a specific contract. Once the
+// transaction is confirmed the SLA is also added to the stored contract, if there is one.
+func (s *ContractService) CreateSLA(ctx context.Context, id string, sla domain.SLA) (*domain.Submission[domain.SLA], error) {
 	// Validate inputs
 	if id == "" {
 		return nil, fmt.Errorf("contract id cannot be empty")
@@ -156,8 +229,12 @@ func (s *ContractService) CreateSLA(ctx context.Context, id string, sla domain.S
 
 	// Generate SLA ID if empty
 	if sla.ID == "" {
-		s.idSeq++
-		sla.ID = strconv.FormatInt(s.idSeq, 10)
+		sla.ID = s.nextID()
+	}
+
+	payload, err := json.Marshal(sla)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sla: %w", err)
 	}
 
 	s.logger.Infow("Appending SLA to contract on blockchain",
@@ -166,23 +243,103 @@ func (s *ContractService) CreateSLA(ctx context.Context, id string, sla domain.S
 		"name", sla.Name,
 	)
 
-	receipt, err := s.blockchainWriter.AddSLA(ctx, id, sla.ID, sla.Name, sla.Description, sla.Target, uint8(sla.Comparator))
+	txHash, err := s.blockchainWriter.AddSLA(ctx, id, sla.ID, sla.Name, sla.Description, sla.Target, uint8(sla.Comparator))
 	if err != nil {
 		return nil, fmt.Errorf("failed to add SLA to blockchain: %w", err)
 	}
-	if receipt.Status != 1 {
-		return nil, fmt.Errorf("blockchain transaction failed (status: %d, tx: %s)", receipt.Status, receipt.TxHash)
+
+	tx, err := s.transactions.TrackTransaction(ctx, domain.Transaction{
+		Hash:       txHash,
+		Operation:  domain.OperationAddSLA,
+		ContractID: id,
+		SLAID:      sla.ID,
+		Payload:    payload,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Infow("SLA successfully appended to contract on blockchain",
+	s.logger.Infow("SLA submitted to blockchain",
 		"contract_id", id,
 		"sla_id", sla.ID,
-		"tx_hash", receipt.TxHash,
-		"block_number", receipt.BlockNumber,
-		"gas_used", receipt.GasUsed,
+		"tx_hash", tx.Hash,
 	)
 
-	return &sla, nil
+	return &domain.Submission[domain.SLA]{
+		Data:        sla,
+		Transaction: *tx,
+	}, nil
+}
+
+// confirmContract stores a contract once the transaction registering it is mined
+func (s *ContractService) confirmContract(tx domain.Transaction) error {
+	var contract domain.Contract
+	if err := json.Unmarshal(tx.Payload, &contract); err != nil {
+		return fmt.Errorf("failed to decode contract: %w", err)
+	}
+
+	return s.repository.Create(contract)
+}
+
+// confirmSLA adds an SLA to the stored contract once the transaction appending it is mined
+func (s *ContractService) confirmSLA(tx domain.Transaction) error {
+	var sla domain.SLA
+	if err := json.Unmarshal(tx.Payload, &sla); err != nil {
+		return fmt.Errorf("failed to decode sla: %w", err)
+	}
+
+	return s.appendSLA(tx.ContractID, sla)
+}
+
+// nextID generates an ID for a contract or SLA submitted without one
+func (s *ContractService) nextID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.idSeq++
+	return strconv.FormatInt(s.idSeq, 10)
+}
+
+// reserve marks a contract ID as being registered, reporting false if it already was
+func (s *ContractService) reserve(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.registering[id]; ok {
+		return false
+	}
+
+	s.registering[id] = struct{}{}
+	return true
+}
+
+// unreserve releases a contract ID once its registration was sent and recorded, or failed
+func (s *ContractService) unreserve(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.registering, id)
+}
+
+// appendSLA adds a confirmed SLA to the stored contract through UpdateSLAs, so that neither the
+// lines reconciled by the consumer nor the SLAs confirmed concurrently are overwritten. Contracts
+// that are only known on chain are left untouched.
+func (s *ContractService) appendSLA(id string, sla domain.SLA) error {
+	if !s.repository.Exists(id) {
+		return nil
+	}
+
+	return s.repository.UpdateSLAs(id, func(contract *domain.Contract) (bool, error) {
+		// A transaction applied again, when resumed after a restart, finds its SLA already stored
+		for _, stored := range contract.SLAs {
+			if stored.ID == sla.ID {
+				return false, nil
+			}
+		}
+
+		contract.SLAs = append(contract.SLAs, &sla)
+		return true, nil
+	})
 }
 
 func (s *ContractService) RetrieveSLAs(ctx context.Context, id string) ([]domain.SLA, error) {
@@ -208,3 +365,34 @@ func (s *ContractService) RetrieveSLAs(ctx context.Context, id string) ([]domain
 
 	return slas, nil
 }
+
+// checkLines rejects the lines of medicines missing from the medicine catalog
+func (s *ContractService) checkLines(lines []domain.ContractLine) error {
+	if s.medicines == nil {
+		return nil
+	}
+
+	for _, line := range lines {
+		if _, err := s.medicines.FindByID(line.MedicineID); err != nil {
+			if errors.Is(err, domain.ErrMedicineNotFound) {
+				return fmt.Errorf("contract line for medicine %q: %w", line.MedicineID, err)
+			}
+			return fmt.Errorf("failed to check contract line for medicine %q: %w", line.MedicineID, err)
+		}
+	}
+	return nil
+}
+
+// normalizeLines marks the lines submitted without a status active and timestamps them
+func normalizeLines(lines []domain.ContractLine) []domain.ContractLine {
+	now := time.Now().UTC()
+	for i := range lines {
+		if lines[i].Status == "" {
+			lines[i].Status = domain.ContractLineActive
+		}
+		if lines[i].UpdatedAt.IsZero() {
+			lines[i].UpdatedAt = now
+		}
+	}
+	return lines
+}
@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/core/domain"
+	"errors"
+	"testing"
+)
+
+func TestContractServiceChecksLinesAgainstCatalog(t *testing.T) {
+	transactions, writer, _ := newTestTransactionService()
+	contracts := memory.NewContractRepository()
+	medicines := memory.NewMedicineRepository()
+	if err := medicines.Create(domain.Medicine{ID: "med-1", Name: "Ibuprofen", Price: 3}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	service := NewContractService(contracts, medicines, writer, nil, transactions)
+
+	known := domain.ContractLine{MedicineID: "med-1", AgreedPrice: 3}
+	unknown := domain.ContractLine{MedicineID: "med-2", AgreedPrice: 5}
+
+	_, err := service.CreateContract(context.Background(), domain.Contract{
+		ID:         "contract-1",
+		Path:       "/contracts/1",
+		CustomerID: "customer-1",
+		Lines:      []domain.ContractLine{known, unknown},
+	})
+	if !errors.Is(err, domain.ErrMedicineNotFound) {
+		t.Fatalf("CreateContract() error = %v, want %v", err, domain.ErrMedicineNotFound)
+	}
+	if len(writer.sent) != 0 {
+		t.Error("CreateContract() sent a transaction for a line of an unknown medicine")
+	}
+
+	stored := domain.Contract{ID: "contract-2", Path: "/contracts/2", CustomerID: "customer-1"}
+	if err := contracts.Create(stored); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	stored.Lines = []domain.ContractLine{known, unknown}
+	if _, err := service.UpdateContract(stored); !errors.Is(err, domain.ErrMedicineNotFound) {
+		t.Fatalf("UpdateContract() error = %v, want %v", err, domain.ErrMedicineNotFound)
+	}
+	if found, _ := contracts.FindByID("contract-2"); len(found.Lines) != 0 {
+		t.Errorf("UpdateContract() stored %d lines, want the contract left untouched", len(found.Lines))
+	}
+
+	stored.Lines = []domain.ContractLine{known}
+	updated, err := service.UpdateContract(stored)
+	if err != nil {
+		t.Fatalf("UpdateContract() error = %v", err)
+	}
+	if len(updated.Lines) != 1 || updated.Lines[0].Status != domain.ContractLineActive {
+		t.Errorf("UpdateContract() lines = %+v, want one active line", updated.Lines)
+	}
+}
+
+func TestContractServiceWithoutCatalogAcceptsAnyLine(t *testing.T) {
+	transactions, writer, _ := newTestTransactionService()
+	contracts := memory.NewContractRepository()
+	service := NewContractService(contracts, nil, writer, nil, transactions)
+
+	stored := domain.Contract{ID: "contract-1", Path: "/contracts/1", CustomerID: "customer-1"}
+	if err := contracts.Create(stored); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	stored.Lines = []domain.ContractLine{{MedicineID: "med-2", AgreedPrice: 5}}
+	if _, err := service.UpdateContract(stored); err != nil {
+		t.Errorf("UpdateContract() error = %v, want lines left unchecked without a catalog", err)
+	}
+}
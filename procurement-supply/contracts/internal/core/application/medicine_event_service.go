@@ -1,71 +1,306 @@
 package application
 
 import (
+	"context"
 	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
 	"contracts/pkg/logger"
 	"fmt"
+	"math"
+	"procurement-supply/pkg/events"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-// MedicineEventService handles the business logic for medicine events
+// MedicineEventService keeps a local copy of the medicine catalog of the suppliers service and
+// reconciles the contract lines with its medicine events, announcing every line it changes through
+// the outbox of the contract repository
 type MedicineEventService struct {
-	logger *zap.SugaredLogger
+	repository     driven.ContractRepository
+	medicines      driven.Repository[string, domain.Medicine]
+	driftThreshold float64
+	logger         *zap.SugaredLogger
 }
 
-// NewMedicineEventService creates a new medicine event service
-func NewMedicineEventService() *MedicineEventService {
+// Ensure MedicineEventService implements the driven.MedicineEventHandler, driven.EventProcessor and
+// driven.CatalogRestorer interfaces
+var (
+	_ driven.MedicineEventHandler = (*MedicineEventService)(nil)
+	_ driven.EventProcessor       = (*MedicineEventService)(nil)
+	_ driven.CatalogRestorer      = (*MedicineEventService)(nil)
+)
+
+// NewMedicineEventService creates a new medicine event service. A line is flagged for
+// renegotiation once its agreed price differs from the catalog price by more than
+// driftThreshold, relative to the agreed price (0.1 means 10%).
+func NewMedicineEventService(
+	repository driven.ContractRepository,
+	medicines driven.Repository[string, domain.Medicine],
+	driftThreshold float64,
+) *MedicineEventService {
 	return &MedicineEventService{
-		logger: logger.New("EVENT-SERVICE"),
+		repository:     repository,
+		medicines:      medicines,
+		driftThreshold: driftThreshold,
+		logger:         logger.New("EVENT-SERVICE"),
 	}
 }
 
-// HandleMedicineUpdated processes medicine update events
-func (s *MedicineEventService) HandleMedicineUpdated(event *domain.Event[domain.Medicine]) error {
-	s.logger.Infow("Processing UPDATED event for medicine",
-		"medicine_id", event.Data.ID,
-		"medicine_name", event.Data.Name)
-
-	// Update the existing contract with new medicine data
-	s.logger.Infow("Finding existing contracts with medicine",
+// HandleMedicineCreated adds the medicine to the local catalog and prices the lines that already cover it
+func (s *MedicineEventService) HandleMedicineCreated(ctx context.Context, event *domain.Event[domain.Medicine]) error {
+	s.logger.Infow("Processing CREATED event for medicine",
 		"medicine_id", event.Data.ID,
+		"medicine_name", event.Data.Name,
+		"price", event.Data.Price,
 	)
 
-	s.logger.Infow("Successfully updated contracts for medicine",
+	// Outdated events, such as a retried event overtaken by a later change, leave the lines alone
+	current, err := s.storeMedicine(event.Data)
+	if err != nil || !current {
+		return err
+	}
+
+	return s.reconcile(ctx, event.Data.ID, s.refreshLine(event.Data))
+}
+
+// HandleMedicineUpdated refreshes the local catalog and the active lines covering the medicine with
+// its new name and price. Lines drifting beyond the threshold are flagged, and flagged lines back
+// within it are active again.
+func (s *MedicineEventService) HandleMedicineUpdated(ctx context.Context, event *domain.Event[domain.Medicine]) error {
+	s.logger.Infow("Processing UPDATED event for medicine",
 		"medicine_id", event.Data.ID,
+		"medicine_name", event.Data.Name,
+		"price", event.Data.Price,
 	)
-	return nil
+
+	// Outdated events, such as a retried event overtaken by a later change, leave the lines alone
+	current, err := s.storeMedicine(event.Data)
+	if err != nil || !current {
+		return err
+	}
+
+	return s.reconcile(ctx, event.Data.ID, s.refreshLine(event.Data))
 }
 
-// HandleMedicineDeleted processes medicine deletion events
-func (s *MedicineEventService) HandleMedicineDeleted(event *domain.Event[domain.Medicine]) error {
+// HandleMedicineDeleted removes the medicine from the local catalog and marks the lines covering it inactive
+func (s *MedicineEventService) HandleMedicineDeleted(ctx context.Context, event *domain.Event[domain.Medicine]) error {
 	s.logger.Infow("Processing DELETED event for medicine",
 		"medicine_id", event.Data.ID,
 	)
 
-	// Find an existing contract for the medicine
-	s.logger.Infow("Finding existing contracts with medicine",
-		"medicine_id", event.Data.ID,
-	)
+	if err := s.removeMedicine(event.Data); err != nil {
+		return err
+	}
 
-	// Delete medicines related to the contract
-	s.logger.Infow("Deleting medicines related to contract",
-		"medicine_id", event.Data.ID,
-	)
+	return s.reconcile(ctx, event.Data.ID, func(line *domain.ContractLine) domain.EventType {
+		if line.Status == domain.ContractLineInactive {
+			return ""
+		}
 
-	return nil
+		line.Status = domain.ContractLineInactive
+		return domain.ContractLineDeactivatedEvent
+	})
 }
 
-// ProcessEvent routes events to the appropriate handler based on an event type
-func (s *MedicineEventService) ProcessEvent(event *domain.Event[domain.Medicine]) error {
+// ProcessEvent routes events to the appropriate handler based on an event type. Event types this
+// service does not handle are skipped, so they are committed instead of retried.
+func (s *MedicineEventService) ProcessEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error {
 	switch event.EventType {
+	case domain.MedicineCreatedEvent:
+		return s.HandleMedicineCreated(ctx, event)
 	case domain.MedicineUpdatedEvent:
-		return s.HandleMedicineUpdated(event)
+		return s.HandleMedicineUpdated(ctx, event)
 	case domain.MedicineDeletedEvent:
-		return s.HandleMedicineDeleted(event)
+		return s.HandleMedicineDeleted(ctx, event)
 	default:
-		s.logger.Warnw("Unknown event type",
-			"event_type", event.EventType)
-		return fmt.Errorf("unknown event type: %s", event.EventType)
+		s.logger.Warnw("Skipping unknown event type",
+			"event_type", event.EventType,
+			"event_id", event.ID,
+		)
+		return nil
+	}
+}
+
+// RestoreEvent applies an event to the local catalog only, leaving the contract lines alone.
+// Created and updated medicines older than the stored copy are ignored, as in ProcessEvent.
+func (s *MedicineEventService) RestoreEvent(_ context.Context, event *domain.Event[domain.Medicine]) error {
+	switch event.EventType {
+	case domain.MedicineCreatedEvent, domain.MedicineUpdatedEvent:
+		_, err := s.storeMedicine(event.Data)
+		return err
+	case domain.MedicineDeletedEvent:
+		return s.removeMedicine(event.Data)
+	default:
+		return nil
+	}
+}
+
+// storeMedicine adds the medicine to the local catalog or replaces the stored copy, unless the stored
+// copy is more recent: a retried event may arrive after a later change of the same medicine. It
+// reports whether the medicine is current, that is, not older than the stored copy.
+func (s *MedicineEventService) storeMedicine(medicine domain.Medicine) (bool, error) {
+	if !s.medicines.Exists(medicine.ID) {
+		if err := s.medicines.Create(medicine); err != nil {
+			return false, fmt.Errorf("failed to store medicine %s: %w", medicine.ID, err)
+		}
+		return true, nil
+	}
+
+	stored, err := s.medicines.FindByID(medicine.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve medicine %s: %w", medicine.ID, err)
+	}
+
+	if medicine.UpdatedAt.Before(stored.UpdatedAt) {
+		s.logger.Infow("Ignoring outdated medicine",
+			"medicine_id", medicine.ID,
+			"updated_at", medicine.UpdatedAt,
+			"stored_updated_at", stored.UpdatedAt,
+		)
+		return false, nil
+	}
+
+	if err := s.medicines.Update(medicine); err != nil {
+		return false, fmt.Errorf("failed to update medicine %s: %w", medicine.ID, err)
+	}
+	return true, nil
+}
+
+// removeMedicine removes the medicine from the local catalog. Redelivered deletions find it already gone.
+func (s *MedicineEventService) removeMedicine(medicine domain.Medicine) error {
+	if !s.medicines.Exists(medicine.ID) {
+		return nil
+	}
+
+	if err := s.medicines.Delete(medicine.ID); err != nil {
+		return fmt.Errorf("failed to delete medicine %s: %w", medicine.ID, err)
+	}
+	return nil
+}
+
+// refreshLine returns the change bringing an active line up to date with the medicine name and price
+func (s *MedicineEventService) refreshLine(medicine domain.Medicine) func(line *domain.ContractLine) domain.EventType {
+	return func(line *domain.ContractLine) domain.EventType {
+		if line.Status == domain.ContractLineInactive {
+			return ""
+		}
+
+		if medicine.Name != "" {
+			line.MedicineName = medicine.Name
+		}
+
+		// Without a price there is nothing to compare the agreed price with
+		if medicine.Price <= 0 {
+			return ""
+		}
+
+		priceChanged := line.CatalogPrice != medicine.Price
+		line.CatalogPrice = medicine.Price
+		line.Drift = priceDrift(line.AgreedPrice, medicine.Price)
+		drifting := math.Abs(line.Drift) > s.driftThreshold
+
+		switch {
+		case drifting && (line.Status != domain.ContractLinePriceDrift || priceChanged):
+			line.Status = domain.ContractLinePriceDrift
+			return domain.ContractLinePriceDriftedEvent
+		case !drifting && line.Status == domain.ContractLinePriceDrift:
+			line.Status = domain.ContractLineActive
+			return domain.ContractLinePriceRestoredEvent
+		default:
+			return ""
+		}
+	}
+}
+
+// reconcile applies change to every line covering the medicine and stores the lines that changed.
+// change edits the line in place and returns the event announcing it, if any. Each contract is
+// changed through UpdateLines, so concurrent updates of the same contract are neither overwritten
+// nor based on stale lines, and its events are stored in the outbox along with the lines, for the
+// relay to publish once committed. Lines that were already stored are left unchanged on
+// redelivery, so nothing is announced twice.
+func (s *MedicineEventService) reconcile(ctx context.Context, medicineID string, change func(line *domain.ContractLine) domain.EventType) error {
+	contracts, err := s.repository.FindByMedicine(medicineID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve contracts of medicine %s: %w", medicineID, err)
+	}
+
+	updated := 0
+	for _, candidate := range contracts {
+		var lineEvents []*domain.Event[domain.ContractLineChange]
+		changed := false
+		err := s.repository.UpdateLines(candidate.ID, func(contract *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error) {
+			lineEvents, changed = s.reconcileLines(ctx, contract, medicineID, change)
+			return changed, lineEvents, nil
+		})
+		if err != nil {
+			// Contracts deleted since they were listed have no lines left to reconcile
+			if !s.repository.Exists(candidate.ID) {
+				continue
+			}
+			return fmt.Errorf("failed to update contract %s: %w", candidate.ID, err)
+		}
+
+		if !changed {
+			continue
+		}
+
+		updated++
+		s.logger.Infow("Contract lines reconciled",
+			"contract_id", candidate.ID,
+			"medicine_id", medicineID,
+			"events", len(lineEvents),
+		)
+	}
+
+	s.logger.Infow("Successfully reconciled contracts for medicine",
+		"medicine_id", medicineID,
+		"contracts_updated", updated,
+	)
+
+	return nil
+}
+
+// reconcileLines applies change to the lines of the contract covering the medicine. It returns
+// the events announcing them and whether any line changed.
+func (s *MedicineEventService) reconcileLines(ctx context.Context, contract *domain.Contract, medicineID string, change func(line *domain.ContractLine) domain.EventType) ([]*domain.Event[domain.ContractLineChange], bool) {
+	lineEvents := make([]*domain.Event[domain.ContractLineChange], 0)
+	changed := false
+
+	for i := range contract.Lines {
+		line := &contract.Lines[i]
+		if line.MedicineID != medicineID {
+			continue
+		}
+
+		before := *line
+		eventType := change(line)
+		if *line == before {
+			continue
+		}
+
+		line.UpdatedAt = time.Now().UTC()
+		changed = true
+
+		if eventType != "" {
+			event := domain.NewContractLineEvent(domain.ContractLineChange{
+				ContractID: contract.ID,
+				CustomerID: contract.CustomerID,
+				Line:       *line,
+			}, eventType)
+			// Correlate the line events with the medicine event causing them
+			event.CorrelationID = events.CorrelationID(ctx)
+			lineEvents = append(lineEvents, event)
+		}
+	}
+
+	return lineEvents, changed
+}
+
+// priceDrift returns how far the catalog price moved away from the agreed price, relative to the latter
+func priceDrift(agreedPrice, catalogPrice float64) float64 {
+	if agreedPrice <= 0 {
+		return 0
 	}
+	return (catalogPrice - agreedPrice) / agreedPrice
 }
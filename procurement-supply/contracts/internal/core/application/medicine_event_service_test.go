@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/core/domain"
+	"procurement-supply/pkg/events"
+	"slices"
+	"testing"
+	"time"
+)
+
+// medicineAt returns the medicine med-1 with the given price, updated minutes after a fixed time
+func medicineAt(price float64, minutes int) domain.Medicine {
+	updatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(minutes) * time.Minute)
+	return domain.Medicine{ID: "med-1", Name: "Ibuprofen", Price: price, UpdatedAt: updatedAt}
+}
+
+// claimEventTypes drains the outbox of the repository and returns the types of its events in order
+func claimEventTypes(t *testing.T, contracts *memory.ContractRepository) []domain.EventType {
+	t.Helper()
+
+	eventTypes := make([]domain.EventType, 0)
+	for {
+		claimed := 0
+		err := contracts.Claim(100, func(entries []domain.OutboxEntry) []error {
+			claimed = len(entries)
+			for _, entry := range entries {
+				eventTypes = append(eventTypes, entry.Event.EventType)
+			}
+			return make([]error, len(entries))
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+		if claimed == 0 {
+			return eventTypes
+		}
+	}
+}
+
+func TestMedicineEventServiceProcessEvent(t *testing.T) {
+	created := domain.NewMedicineEvent(medicineAt(10.5, 0), domain.MedicineCreatedEvent)
+	drifted := domain.NewMedicineEvent(medicineAt(12, 1), domain.MedicineUpdatedEvent)
+	restored := domain.NewMedicineEvent(medicineAt(10.8, 2), domain.MedicineUpdatedEvent)
+	deleted := domain.NewMedicineEvent(medicineAt(10.8, 3), domain.MedicineDeletedEvent)
+
+	tests := []struct {
+		name       string
+		events     []*domain.Event[domain.Medicine]
+		wantStatus domain.ContractLineStatus
+		wantPrice  float64
+		wantEvents []domain.EventType
+	}{
+		{
+			name:       "price within the threshold",
+			events:     []*domain.Event[domain.Medicine]{created},
+			wantStatus: domain.ContractLineActive,
+			wantPrice:  10.5,
+			wantEvents: []domain.EventType{},
+		},
+		{
+			name:       "price drifting beyond the threshold",
+			events:     []*domain.Event[domain.Medicine]{created, drifted},
+			wantStatus: domain.ContractLinePriceDrift,
+			wantPrice:  12,
+			wantEvents: []domain.EventType{domain.ContractLinePriceDriftedEvent},
+		},
+		{
+			name: "drifting price changing again",
+			events: []*domain.Event[domain.Medicine]{
+				drifted,
+				domain.NewMedicineEvent(medicineAt(8, 5), domain.MedicineUpdatedEvent),
+			},
+			wantStatus: domain.ContractLinePriceDrift,
+			wantPrice:  8,
+			wantEvents: []domain.EventType{domain.ContractLinePriceDriftedEvent, domain.ContractLinePriceDriftedEvent},
+		},
+		{
+			name:       "price back within the threshold",
+			events:     []*domain.Event[domain.Medicine]{created, drifted, restored},
+			wantStatus: domain.ContractLineActive,
+			wantPrice:  10.8,
+			wantEvents: []domain.EventType{domain.ContractLinePriceDriftedEvent, domain.ContractLinePriceRestoredEvent},
+		},
+		{
+			name:       "deleted medicine",
+			events:     []*domain.Event[domain.Medicine]{created, deleted},
+			wantStatus: domain.ContractLineInactive,
+			wantPrice:  10.5,
+			wantEvents: []domain.EventType{domain.ContractLineDeactivatedEvent},
+		},
+		{
+			name:       "inactive line left alone",
+			events:     []*domain.Event[domain.Medicine]{deleted, drifted},
+			wantStatus: domain.ContractLineInactive,
+			wantEvents: []domain.EventType{domain.ContractLineDeactivatedEvent},
+		},
+		{
+			name:       "redelivered events",
+			events:     []*domain.Event[domain.Medicine]{drifted, drifted, deleted, deleted},
+			wantStatus: domain.ContractLineInactive,
+			wantPrice:  12,
+			wantEvents: []domain.EventType{domain.ContractLinePriceDriftedEvent, domain.ContractLineDeactivatedEvent},
+		},
+		{
+			name:       "outdated event",
+			events:     []*domain.Event[domain.Medicine]{drifted, created},
+			wantStatus: domain.ContractLinePriceDrift,
+			wantPrice:  12,
+			wantEvents: []domain.EventType{domain.ContractLinePriceDriftedEvent},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contracts := memory.NewContractRepository()
+			service := NewMedicineEventService(contracts, memory.NewMedicineRepository(), 0.1)
+
+			other := domain.ContractLine{MedicineID: "med-2", AgreedPrice: 5, Status: domain.ContractLineActive}
+			err := contracts.Create(domain.Contract{ID: "contract-1", CustomerID: "customer-1", Lines: []domain.ContractLine{
+				{MedicineID: "med-1", AgreedPrice: 10, Status: domain.ContractLineActive},
+				other,
+			}})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := contracts.Create(domain.Contract{ID: "contract-2", Lines: []domain.ContractLine{other}}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			for _, event := range tt.events {
+				if err := service.ProcessEvent(context.Background(), event); err != nil {
+					t.Fatalf("ProcessEvent(%s) error = %v", event.EventType, err)
+				}
+			}
+
+			contract, err := contracts.FindByID("contract-1")
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			line := contract.Lines[0]
+			if line.Status != tt.wantStatus || line.CatalogPrice != tt.wantPrice {
+				t.Errorf("line status = %s, catalog price = %v, want %s, %v", line.Status, line.CatalogPrice, tt.wantStatus, tt.wantPrice)
+			}
+			if contract.Lines[1] != other {
+				t.Errorf("line of another medicine = %+v, want it untouched", contract.Lines[1])
+			}
+
+			if got := claimEventTypes(t, contracts); !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestMedicineEventServiceCorrelatesLineEvents(t *testing.T) {
+	contracts := memory.NewContractRepository()
+	service := NewMedicineEventService(contracts, memory.NewMedicineRepository(), 0.1)
+
+	line := domain.ContractLine{MedicineID: "med-1", AgreedPrice: 10, Status: domain.ContractLineActive}
+	if err := contracts.Create(domain.Contract{ID: "contract-1", CustomerID: "customer-1", Lines: []domain.ContractLine{line}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	event := domain.NewMedicineEvent(medicineAt(12, 0), domain.MedicineUpdatedEvent)
+	event.CorrelationID = "correlation-1"
+	ctx := events.WithCorrelationID(context.Background(), event.CorrelationID)
+	if err := service.ProcessEvent(ctx, event); err != nil {
+		t.Fatalf("ProcessEvent() error = %v", err)
+	}
+
+	err := contracts.Claim(10, func(entries []domain.OutboxEntry) []error {
+		if len(entries) != 1 {
+			t.Fatalf("claimed %d events, want 1", len(entries))
+		}
+
+		change := entries[0].Event.Data
+		if change.ContractID != "contract-1" || change.CustomerID != "customer-1" || change.Line.Drift != 0.2 {
+			t.Errorf("event data = %+v", change)
+		}
+		if entries[0].Event.CorrelationID != "correlation-1" {
+			t.Errorf("event correlation ID = %q, want the one of the medicine event", entries[0].Event.CorrelationID)
+		}
+		return []error{nil}
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+}
+
+func TestMedicineEventServiceRestoreEventOnlyRebuildsCatalog(t *testing.T) {
+	contracts := memory.NewContractRepository()
+	medicines := memory.NewMedicineRepository()
+	service := NewMedicineEventService(contracts, medicines, 0.1)
+
+	line := domain.ContractLine{MedicineID: "med-1", AgreedPrice: 10, Status: domain.ContractLineActive}
+	if err := contracts.Create(domain.Contract{ID: "contract-1", Lines: []domain.ContractLine{line}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Events read in topic order, followed by an older update read again
+	for _, event := range []*domain.Event[domain.Medicine]{
+		domain.NewMedicineEvent(medicineAt(10, 0), domain.MedicineCreatedEvent),
+		domain.NewMedicineEvent(medicineAt(20, 60), domain.MedicineUpdatedEvent),
+		domain.NewMedicineEvent(medicineAt(15, 1), domain.MedicineUpdatedEvent),
+	} {
+		if err := service.RestoreEvent(context.Background(), event); err != nil {
+			t.Fatalf("RestoreEvent(%s) error = %v", event.EventType, err)
+		}
+	}
+
+	stored, err := medicines.FindByID("med-1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if stored.Price != 20 {
+		t.Errorf("restored price = %v, want the latest price 20", stored.Price)
+	}
+
+	contract, err := contracts.FindByID("contract-1")
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if contract.Lines[0] != line {
+		t.Errorf("line = %+v, want it untouched %+v", contract.Lines[0], line)
+	}
+	if eventTypes := claimEventTypes(t, contracts); len(eventTypes) != 0 {
+		t.Errorf("contract line events %v, want none", eventTypes)
+	}
+
+	deleted := domain.NewMedicineEvent(medicineAt(20, 60), domain.MedicineDeletedEvent)
+	if err := service.RestoreEvent(context.Background(), deleted); err != nil {
+		t.Fatalf("RestoreEvent(%s) error = %v", deleted.EventType, err)
+	}
+	if medicines.Exists("med-1") {
+		t.Error("medicine still in the catalog after its deletion was restored")
+	}
+}
@@ -0,0 +1,42 @@
+package application
+
+import (
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"contracts/internal/core/port/driver"
+	"fmt"
+)
+
+// MedicineService serves the local copy of the medicine catalog, kept by the consumer from the
+// medicine events of the suppliers service
+type MedicineService struct {
+	repo driven.Repository[string, domain.Medicine]
+}
+
+// Ensure MedicineService implements the driver.MedicineService interface
+var _ driver.MedicineService = (*MedicineService)(nil)
+
+// NewMedicineService creates a new medicine service
+func NewMedicineService(repo driven.Repository[string, domain.Medicine]) *MedicineService {
+	return &MedicineService{
+		repo: repo,
+	}
+}
+
+// RetrieveMedicine retrieves a medicine by ID
+func (s *MedicineService) RetrieveMedicine(id string) (*domain.Medicine, error) {
+	medicine, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve medicine: %w", err)
+	}
+	return medicine, nil
+}
+
+// RetrieveMedicines retrieves all medicines
+func (s *MedicineService) RetrieveMedicines() ([]domain.Medicine, error) {
+	medicines, err := s.repo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve medicines: %w", err)
+	}
+	return medicines, nil
+}
@@ -0,0 +1,204 @@
+package application
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"contracts/internal/core/port/driver"
+	"contracts/pkg/logger"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TransactionService tracks blockchain transactions in the background so that
+// HTTP requests can return as soon as a transaction is sent
+type TransactionService struct {
+	repository       driven.TransactionRepository
+	blockchainWriter driven.BlockchainWriter
+	notifier         driven.TransactionNotifier
+	timeout          time.Duration
+	handlers         map[string]func(tx domain.Transaction) error
+	logger           *zap.SugaredLogger
+}
+
+// Ensure TransactionService implements the driver.TransactionService interface
+var _ driver.TransactionService = (*TransactionService)(nil)
+
+// NewTransactionService creates a new transaction service. The notifier is optional;
+// timeout is how long a transaction is waited for before it is reported as unknown,
+// while it keeps being followed until it is mined or dropped.
+func NewTransactionService(
+	repository driven.TransactionRepository,
+	writer driven.BlockchainWriter,
+	notifier driven.TransactionNotifier,
+	timeout time.Duration,
+) *TransactionService {
+	return &TransactionService{
+		repository:       repository,
+		blockchainWriter: writer,
+		notifier:         notifier,
+		timeout:          timeout,
+		handlers:         make(map[string]func(tx domain.Transaction) error),
+		logger:           logger.New("TRANSACTION-SERVICE"),
+	}
+}
+
+// HandleConfirmed registers the function applying the payload of the transactions of an
+// operation once they are mined successfully
+func (s *TransactionService) HandleConfirmed(operation string, onConfirmed func(tx domain.Transaction) error) {
+	s.handlers[operation] = onConfirmed
+}
+
+// TrackTransaction records a pending transaction and follows it until it is mined, fails or is dropped.
+// Following the transaction outlives ctx, but keeps its values such as the trace it belongs to.
+func (s *TransactionService) TrackTransaction(ctx context.Context, tx domain.Transaction) (*domain.Transaction, error) {
+	now := time.Now().UTC()
+	tx.Status = domain.TransactionPending
+	tx.SubmittedAt = now
+	tx.UpdatedAt = now
+
+	if err := s.repository.Create(tx); err != nil {
+		return nil, fmt.Errorf("failed to record transaction: %w", err)
+	}
+
+	go s.follow(context.WithoutCancel(ctx), tx)
+
+	return &tx, nil
+}
+
+// Resume follows again the transactions that had not reached a final state when the service
+// stopped, so that their payload is still applied once they are mined
+func (s *TransactionService) Resume(ctx context.Context) error {
+	transactions, err := s.repository.FindPending()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve pending transactions: %w", err)
+	}
+
+	for _, tx := range transactions {
+		s.logger.Infow("Resuming transaction",
+			"tx_hash", tx.Hash,
+			"operation", tx.Operation,
+			"contract_id", tx.ContractID,
+			"status", tx.Status,
+		)
+		go s.follow(ctx, tx)
+	}
+
+	return nil
+}
+
+// IsPending reports whether a transaction of the operation on the contract is still being followed
+func (s *TransactionService) IsPending(operation, contractID string) (bool, error) {
+	pending, err := s.repository.HasPending(operation, contractID)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve pending transactions: %w", err)
+	}
+
+	return pending, nil
+}
+
+// RetrieveTransaction retrieves a tracked transaction by hash
+func (s *TransactionService) RetrieveTransaction(hash string) (*domain.Transaction, error) {
+	tx, err := s.repository.FindByID(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// follow waits for the receipt of a transaction, records its final state and notifies the webhook.
+// A transaction that is not mined within the timeout is recorded as unknown and waited for again,
+// until it is mined or the node reports it dropped.
+func (s *TransactionService) follow(ctx context.Context, tx domain.Transaction) {
+	receipt, err := s.waitForReceipt(ctx, tx)
+	for errors.Is(err, context.DeadlineExceeded) {
+		if tx.Status != domain.TransactionUnknown {
+			s.logger.Warnw("Transaction not mined within the timeout, still waiting",
+				"tx_hash", tx.Hash,
+				"operation", tx.Operation,
+				"timeout", s.timeout,
+			)
+
+			tx.Status = domain.TransactionUnknown
+			tx.Error = err.Error()
+			tx.UpdatedAt = time.Now().UTC()
+			if err := s.repository.Update(tx); err != nil {
+				s.logger.Errorw("Failed to update transaction",
+					"tx_hash", tx.Hash,
+					"error", err,
+				)
+			}
+		}
+
+		receipt, err = s.waitForReceipt(ctx, tx)
+	}
+
+	tx.Error = ""
+	switch {
+	case err == nil && receipt.Status == 1:
+		tx.Status = domain.TransactionMined
+		tx.BlockNumber = receipt.BlockNumber
+		tx.GasUsed = receipt.GasUsed
+
+		if onConfirmed := s.handlers[tx.Operation]; onConfirmed != nil {
+			if err := onConfirmed(tx); err != nil {
+				s.logger.Errorw("Failed to apply confirmed transaction",
+					"tx_hash", tx.Hash,
+					"operation", tx.Operation,
+					"error", err,
+				)
+				tx.Error = err.Error()
+			}
+		}
+	case err == nil:
+		tx.Status = domain.TransactionFailed
+		tx.BlockNumber = receipt.BlockNumber
+		tx.GasUsed = receipt.GasUsed
+		tx.Error = fmt.Sprintf("transaction reverted (status: %d)", receipt.Status)
+	case errors.Is(err, driven.ErrTransactionDropped):
+		tx.Status = domain.TransactionDropped
+		tx.Error = err.Error()
+	default:
+		tx.Status = domain.TransactionFailed
+		tx.Error = err.Error()
+	}
+
+	tx.UpdatedAt = time.Now().UTC()
+	if err := s.repository.Update(tx); err != nil {
+		s.logger.Errorw("Failed to update transaction",
+			"tx_hash", tx.Hash,
+			"error", err,
+		)
+	}
+
+	s.logger.Infow("Transaction reached final state",
+		"tx_hash", tx.Hash,
+		"operation", tx.Operation,
+		"contract_id", tx.ContractID,
+		"status", tx.Status,
+		"block_number", tx.BlockNumber,
+	)
+
+	if s.notifier == nil {
+		return
+	}
+
+	if err := s.notifier.NotifyTransaction(ctx, tx); err != nil {
+		s.logger.Warnw("Failed to notify transaction webhook",
+			"tx_hash", tx.Hash,
+			"error", err,
+		)
+	}
+}
+
+// waitForReceipt waits for the receipt of a transaction for at most the configured timeout
+func (s *TransactionService) waitForReceipt(ctx context.Context, tx domain.Transaction) (*driven.TransactionReceipt, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return s.blockchainWriter.WaitForReceipt(waitCtx, tx.Hash)
+}
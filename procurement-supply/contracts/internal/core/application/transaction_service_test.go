@@ -0,0 +1,212 @@
+package application
+
+import (
+	"context"
+	"contracts/internal/adapter/storage/memory"
+	"contracts/internal/core/domain"
+	"contracts/internal/core/port/driven"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubWriter answers WaitForReceipt from a queue of results, timing out once the queue is empty,
+// and blocks AddContract until release is closed
+type stubWriter struct {
+	driven.BlockchainWriter
+
+	mu       sync.Mutex
+	results  []error // nil is a successful receipt
+	waits    int
+	statuses []domain.TransactionStatus // status of the transaction at each wait
+	repo     driven.Repository[string, domain.Transaction]
+
+	sent    chan string
+	release chan struct{}
+}
+
+func (w *stubWriter) WaitForReceipt(ctx context.Context, txHash string) (*driven.TransactionReceipt, error) {
+	w.mu.Lock()
+	w.waits++
+	if tx, err := w.repo.FindByID(txHash); err == nil {
+		w.statuses = append(w.statuses, tx.Status)
+	}
+	if len(w.results) == 0 {
+		w.mu.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	err := w.results[0]
+	w.results = w.results[1:]
+	w.mu.Unlock()
+
+	if err == context.DeadlineExceeded {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &driven.TransactionReceipt{TxHash: txHash, BlockNumber: 7, Status: 1}, nil
+}
+
+func (w *stubWriter) AddContract(_ context.Context, contractID, _, _ string) (string, error) {
+	w.sent <- contractID
+	<-w.release
+	return "0x" + contractID, nil
+}
+
+// stubTxNotifier announces the transactions reaching a final state
+type stubTxNotifier chan domain.Transaction
+
+func (n stubTxNotifier) NotifyTransaction(_ context.Context, tx domain.Transaction) error {
+	n <- tx
+	return nil
+}
+
+func newTestTransactionService(results ...error) (*TransactionService, *stubWriter, stubTxNotifier) {
+	repo := memory.NewTransactionRepository()
+	writer := &stubWriter{results: results, repo: repo, sent: make(chan string, 2), release: make(chan struct{})}
+	notifier := make(stubTxNotifier, 4)
+
+	return NewTransactionService(repo, writer, notifier, 20*time.Millisecond), writer, notifier
+}
+
+func awaitFinal(t *testing.T, notifier stubTxNotifier) domain.Transaction {
+	t.Helper()
+
+	select {
+	case tx := <-notifier:
+		return tx
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction never reached a final state")
+		return domain.Transaction{}
+	}
+}
+
+func TestTransactionServiceKeepsFollowingAfterTimeout(t *testing.T) {
+	service, writer, notifier := newTestTransactionService(context.DeadlineExceeded, context.DeadlineExceeded, nil)
+
+	var confirmed []domain.Transaction
+	service.HandleConfirmed(domain.OperationAddContract, func(tx domain.Transaction) error {
+		confirmed = append(confirmed, tx)
+		return nil
+	})
+
+	if _, err := service.TrackTransaction(context.Background(), domain.Transaction{
+		Hash:      "0x01",
+		Operation: domain.OperationAddContract,
+	}); err != nil {
+		t.Fatalf("TrackTransaction() error = %v", err)
+	}
+
+	tx := awaitFinal(t, notifier)
+	if tx.Status != domain.TransactionMined || tx.Error != "" {
+		t.Errorf("final transaction = %+v, want mined without error", tx)
+	}
+	if len(confirmed) != 1 {
+		t.Errorf("confirmation handler ran %d times, want 1", len(confirmed))
+	}
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	want := []domain.TransactionStatus{domain.TransactionPending, domain.TransactionUnknown, domain.TransactionUnknown}
+	if len(writer.statuses) != len(want) {
+		t.Fatalf("waited %d times, want %d", len(writer.statuses), len(want))
+	}
+	for i := range want {
+		if writer.statuses[i] != want[i] {
+			t.Errorf("status at wait %d = %s, want %s", i+1, writer.statuses[i], want[i])
+		}
+	}
+}
+
+func TestTransactionServiceDropped(t *testing.T) {
+	service, _, notifier := newTestTransactionService(context.DeadlineExceeded, driven.ErrTransactionDropped)
+
+	if _, err := service.TrackTransaction(context.Background(), domain.Transaction{Hash: "0x01"}); err != nil {
+		t.Fatalf("TrackTransaction() error = %v", err)
+	}
+
+	if tx := awaitFinal(t, notifier); tx.Status != domain.TransactionDropped {
+		t.Errorf("status = %s, want %s", tx.Status, domain.TransactionDropped)
+	}
+}
+
+func TestTransactionServiceResume(t *testing.T) {
+	service, writer, notifier := newTestTransactionService(nil)
+
+	var payload json.RawMessage
+	service.HandleConfirmed(domain.OperationAddSLA, func(tx domain.Transaction) error {
+		payload = tx.Payload
+		return nil
+	})
+
+	for _, tx := range []domain.Transaction{
+		{Hash: "0x01", Operation: domain.OperationAddSLA, Status: domain.TransactionUnknown, Payload: json.RawMessage(`{"id":"sla-1"}`)},
+		{Hash: "0x02", Operation: domain.OperationAddSLA, Status: domain.TransactionMined},
+	} {
+		if err := writer.repo.Create(tx); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if err := service.Resume(context.Background()); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	tx := awaitFinal(t, notifier)
+	if tx.Hash != "0x01" || tx.Status != domain.TransactionMined {
+		t.Errorf("resumed transaction = %s %s, want 0x01 mined", tx.Hash, tx.Status)
+	}
+	if string(payload) != `{"id":"sla-1"}` {
+		t.Errorf("confirmed payload = %s", payload)
+	}
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if writer.waits != 1 {
+		t.Errorf("waited for %d transactions, want only the unfinished one", writer.waits)
+	}
+}
+
+func TestContractServiceReservesIDWhileRegistering(t *testing.T) {
+	transactions, writer, notifier := newTestTransactionService()
+	service := NewContractService(memory.NewContractRepository(), nil, writer, nil, transactions)
+
+	contract := domain.Contract{ID: "contract-1", Path: "/contracts/1", CustomerID: "customer-1"}
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := service.CreateContract(context.Background(), contract)
+		first <- err
+	}()
+	<-writer.sent
+
+	// While the first registration is being sent
+	if _, err := service.CreateContract(context.Background(), contract); err == nil {
+		t.Error("CreateContract() while sending error = nil, want already being registered")
+	}
+
+	close(writer.release)
+	if err := <-first; err != nil {
+		t.Fatalf("CreateContract() error = %v", err)
+	}
+
+	// While the first registration waits to be mined
+	if _, err := service.CreateContract(context.Background(), contract); err == nil {
+		t.Error("CreateContract() while pending error = nil, want already being registered")
+	}
+
+	select {
+	case id := <-writer.sent:
+		t.Errorf("contract %s sent twice", id)
+	default:
+	}
+	select {
+	case tx := <-notifier:
+		t.Errorf("transaction %s reached a final state", tx.Hash)
+	default:
+	}
+}
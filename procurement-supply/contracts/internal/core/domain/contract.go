@@ -1,8 +1,41 @@
 package domain
 
+import "time"
+
+type ContractLineStatus string
+
+const (
+	ContractLineActive     ContractLineStatus = "active"
+	ContractLinePriceDrift ContractLineStatus = "price_drift"
+	ContractLineInactive   ContractLineStatus = "inactive"
+)
+
 type Contract struct {
-	ID         string `json:"id" abi:"id"`
-	Path       string `json:"path" abi:"path"`
-	CustomerID string `json:"customerId" abi:"clientId"`
-	SLAs       []*SLA `json:"slas" abi:"slas"`
+	ID         string         `json:"id" abi:"id"`
+	Path       string         `json:"path" abi:"path"`
+	CustomerID string         `json:"customerId" abi:"clientId"`
+	SLAs       []*SLA         `json:"slas" abi:"slas"`
+	Lines      []ContractLine `json:"lines,omitempty" binding:"omitempty,dive"`
+}
+
+// ContractLine is a medicine covered by a contract at an agreed price. Lines are kept
+// off chain and reconciled with the medicine events published by the suppliers service.
+type ContractLine struct {
+	MedicineID   string             `json:"medicineId" binding:"required"`
+	MedicineName string             `json:"medicineName,omitempty"`
+	AgreedPrice  float64            `json:"agreedPrice" binding:"gt=0"`
+	CatalogPrice float64            `json:"catalogPrice,omitempty"` // latest price announced for the medicine
+	Drift        float64            `json:"drift,omitempty"`        // (catalogPrice - agreedPrice) / agreedPrice
+	Status       ContractLineStatus `json:"status" binding:"omitempty,oneof=active price_drift inactive"`
+	UpdatedAt    time.Time          `json:"updatedAt,omitempty"`
+}
+
+// CoversMedicine reports whether the contract has a line for the medicine, whatever its status
+func (c Contract) CoversMedicine(medicineID string) bool {
+	for _, line := range c.Lines {
+		if line.MedicineID == medicineID {
+			return true
+		}
+	}
+	return false
 }
@@ -1,8 +1,15 @@
 package domain
 
+// Channels a customer can prefer to be notified on
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
 type Customer struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Phone string `json:"phone"`
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	PreferredChannel string `json:"preferredChannel,omitempty" binding:"omitempty,oneof=email sms"`
 }
@@ -1,27 +1,43 @@
 package domain
 
 import (
-	"time"
+	"procurement-supply/pkg/events"
 )
 
-type EventType string
+// EventSource names this service as the source of the events it raises
+const EventSource = "contracts"
 
-type Event[T any] struct {
-	EventType EventType `json:"event_type"`
-	Data      T         `json:"data"`
-	Timestamp time.Time `json:"timestamp"`
-}
+type EventType = events.Type
+
+// Event is a domain event wrapped in the envelope shared with the other services
+type Event[T any] = events.Envelope[T]
 
 const (
+	MedicineCreatedEvent EventType = "medicine.created"
 	MedicineUpdatedEvent EventType = "medicine.updated"
 	MedicineDeletedEvent EventType = "medicine.deleted"
 )
 
+const (
+	ContractLinePriceDriftedEvent  EventType = "contract.line.price_drifted"
+	ContractLinePriceRestoredEvent EventType = "contract.line.price_restored"
+	ContractLineDeactivatedEvent   EventType = "contract.line.deactivated"
+)
+
+// ContractLineChange is the payload of the events announcing that a contract line changed
+// because of a medicine event
+type ContractLineChange struct {
+	ContractID string       `json:"contract_id"`
+	CustomerID string       `json:"customer_id"`
+	Line       ContractLine `json:"line"`
+}
+
+// NewContractLineEvent creates a new contract line event
+func NewContractLineEvent(data ContractLineChange, event EventType) *Event[ContractLineChange] {
+	return events.New(event, EventSource, data)
+}
+
 // NewMedicineEvent creates a new medicine event with validation
 func NewMedicineEvent(data Medicine, event EventType) *Event[Medicine] {
-	return &Event[Medicine]{
-		EventType: event,
-		Data:      data,
-		Timestamp: time.Now(),
-	}
+	return events.New(event, EventSource, data)
 }
@@ -1,6 +1,12 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrMedicineNotFound is returned when a medicine is not in the local catalog
+var ErrMedicineNotFound = errors.New("medicine not found")
 
 type Medicine struct {
 	ID          string    `json:"id"`
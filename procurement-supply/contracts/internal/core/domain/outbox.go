@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// OutboxEntry is a contract line event waiting in the outbox to be published
+type OutboxEntry struct {
+	Event     Event[ContractLineChange]
+	Attempts  int // failed publish attempts so far
+	LastError string
+	CreatedAt time.Time
+}
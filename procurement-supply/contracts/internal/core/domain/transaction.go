@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionPending TransactionStatus = "pending"
+	TransactionUnknown TransactionStatus = "unknown" // not mined within the confirmation timeout, still followed
+	TransactionMined   TransactionStatus = "mined"
+	TransactionFailed  TransactionStatus = "failed"
+	TransactionDropped TransactionStatus = "dropped"
+)
+
+const (
+	OperationAddContract = "add_contract"
+	OperationAddSLA      = "add_sla"
+)
+
+// Transaction tracks a blockchain transaction sent on behalf of a request
+// from the moment it is accepted by the node until it reaches a final state
+type Transaction struct {
+	Hash        string            `json:"hash"`
+	Operation   string            `json:"operation"`
+	ContractID  string            `json:"contractId"`
+	SLAID       string            `json:"slaId,omitempty"`
+	Status      TransactionStatus `json:"status"`
+	BlockNumber uint64            `json:"blockNumber,omitempty"`
+	GasUsed     uint64            `json:"gasUsed,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Payload     json.RawMessage   `json:"-"` // entity applied once the transaction is mined
+	SubmittedAt time.Time         `json:"submittedAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// IsFinal reports whether the transaction will no longer change state
+func (t Transaction) IsFinal() bool {
+	return t.Status != TransactionPending && t.Status != TransactionUnknown
+}
+
+// Submission pairs an entity with the transaction that registers it on the blockchain
+type Submission[T any] struct {
+	Data        T           `json:"data"`
+	Transaction Transaction `json:"transaction"`
+}
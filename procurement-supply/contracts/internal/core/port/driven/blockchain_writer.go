@@ -2,9 +2,13 @@ package driven
 
 import (
 	"context"
+	"errors"
 	"math/big"
 )
 
+// ErrTransactionDropped is returned when a sent transaction is no longer known to the node
+var ErrTransactionDropped = errors.New("transaction dropped")
+
 // TransactionReceipt represents the result of a blockchain transaction
 type TransactionReceipt struct {
 	TxHash      string
@@ -13,19 +17,25 @@ type TransactionReceipt struct {
 	GasUsed     uint64
 }
 
-// BlockchainWriter defines the interface for writing to the blockchain
+// BlockchainWriter defines the interface for writing to the blockchain.
+// State-changing methods return the transaction hash as soon as the node accepts
+// the transaction; WaitForReceipt follows it until it is mined.
 type BlockchainWriter interface {
 	// AddContract adds a new contract to the blockchain
-	AddContract(ctx context.Context, contractID, path, customerID string) (*TransactionReceipt, error)
+	AddContract(ctx context.Context, contractID, path, customerID string) (string, error)
 
 	// AddSLA adds a new SLA to an existing contract
-	AddSLA(ctx context.Context, contractID, slaID, name, description string, target *big.Int, comparator uint8) (*TransactionReceipt, error)
+	AddSLA(ctx context.Context, contractID, slaID, name, description string, target *big.Int, comparator uint8) (string, error)
 
 	// SetSLAStatus updates the status of an SLA
-	SetSLAStatus(ctx context.Context, contractID string, slaIndex uint64, status uint8) (*TransactionReceipt, error)
+	SetSLAStatus(ctx context.Context, contractID string, slaIndex uint64, status uint8) (string, error)
 
 	// CheckSLA checks an SLA against an actual value
-	CheckSLA(ctx context.Context, contractID string, slaIndex uint64, actualValue *big.Int) (*TransactionReceipt, error)
+	CheckSLA(ctx context.Context, contractID string, slaIndex uint64, actualValue *big.Int) (string, error)
+
+	// WaitForReceipt blocks until the transaction is mined and returns its receipt.
+	// It returns ErrTransactionDropped when the node no longer knows the transaction.
+	WaitForReceipt(ctx context.Context, txHash string) (*TransactionReceipt, error)
 
 	// GetAddress returns the address of the account used for transactions
 	GetAddress() string
@@ -0,0 +1,36 @@
+package driven
+
+import "contracts/internal/core/domain"
+
+// ContractRepository is the contract Repository that can also change the lines or the SLAs of a
+// contract without overwriting the rest of it
+type ContractRepository interface {
+	Repository[string, domain.Contract]
+
+	// FindByMedicine retrieves the contracts with a line covering the medicine
+	FindByMedicine(medicineID string) ([]domain.Contract, error)
+
+	// UpdateLines passes a copy of the stored contract to update, which edits its lines in place,
+	// reports whether they changed and returns the events announcing the change. The lines and
+	// the events are then stored together, the events in the outbox. UpdateLines and UpdateSLAs
+	// calls for the contract wait until update returns, so the lines it edits are never stale;
+	// Update does not wait, and overwrites the whole contract. Nothing is stored when update
+	// returns false or an error.
+	UpdateLines(id string, update func(contract *domain.Contract) (bool, []*domain.Event[domain.ContractLineChange], error)) error
+
+	// UpdateSLAs passes a copy of the stored contract to update, which edits its SLAs in place and
+	// reports whether they changed, then stores only the SLAs. It waits for, and is waited for by,
+	// the other UpdateLines and UpdateSLAs calls for the contract, as UpdateLines does. Nothing is
+	// stored when update returns false or an error.
+	UpdateSLAs(id string, update func(contract *domain.Contract) (bool, error)) error
+}
+
+// ContractEventOutbox holds the contract line events stored by ContractRepository.UpdateLines until
+// they are published
+type ContractEventOutbox interface {
+	// Claim passes the oldest waiting event of up to limit contracts to publish, which returns
+	// the outcome of each one. Published events are removed and failed ones are kept with their
+	// error, for a later claim. Later events of a contract are never claimed while an earlier one
+	// is waiting, and an event claimed by one caller is not passed to another until publish returns.
+	Claim(limit int, publish func(entries []domain.OutboxEntry) []error) error
+}
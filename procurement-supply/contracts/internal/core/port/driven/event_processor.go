@@ -1,9 +1,18 @@
 package driven
 
-import "contracts/internal/core/domain"
+import (
+	"context"
+	"contracts/internal/core/domain"
+)
 
 // EventProcessor defines the interface for processing events
 // This allows adapters to depend on an abstraction rather than concrete implementation
 type EventProcessor interface {
-	ProcessEvent(event *domain.Event[domain.Medicine]) error
+	ProcessEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error
+}
+
+// CatalogRestorer applies events to the local medicine catalog only, to rebuild it from the topic
+// without changing contract lines nor publishing events
+type CatalogRestorer interface {
+	RestoreEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error
 }
@@ -0,0 +1,11 @@
+package driven
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+)
+
+// ContractEventPublisher defines the interface for announcing contract changes to downstream consumers
+type ContractEventPublisher interface {
+	PublishContractLineEvent(ctx context.Context, event *domain.Event[domain.ContractLineChange]) error
+}
@@ -0,0 +1,12 @@
+package driven
+
+import (
+	"context"
+	"contracts/internal/core/domain"
+)
+
+// TransactionNotifier defines the interface for announcing that a tracked transaction
+// reached a final state (mined, failed or dropped)
+type TransactionNotifier interface {
+	NotifyTransaction(ctx context.Context, tx domain.Transaction) error
+}
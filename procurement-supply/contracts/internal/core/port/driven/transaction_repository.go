@@ -0,0 +1,15 @@
+package driven
+
+import "contracts/internal/core/domain"
+
+// TransactionRepository is the transaction Repository that can also look up the transactions
+// still being followed, that is, not in a final state
+type TransactionRepository interface {
+	Repository[string, domain.Transaction]
+
+	// FindPending retrieves the transactions not in a final state
+	FindPending() ([]domain.Transaction, error)
+
+	// HasPending reports whether a transaction of the operation on the contract is not in a final state
+	HasPending(operation, contractID string) (bool, error)
+}
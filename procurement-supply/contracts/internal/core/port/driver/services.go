@@ -7,8 +7,9 @@ import (
 
 // ContractService defines the interface for contract business logic
 type ContractService interface {
-	// CreateContract creates a new contract and registers it on the blockchain
-	CreateContract(ctx context.Context, contract domain.Contract) (*domain.Contract, error)
+	// CreateContract sends the transaction registering a new contract on the blockchain.
+	// The contract is stored once the transaction is confirmed.
+	CreateContract(ctx context.Context, contract domain.Contract) (*domain.Submission[domain.Contract], error)
 
 	// RetrieveContract retrieves a contract by ID
 	RetrieveContract(ctx context.Context, id string) (*domain.Contract, error)
@@ -22,8 +23,8 @@ type ContractService interface {
 	// DeleteContract removes a contract
 	DeleteContract(id string) error
 
-	// CreateSLA append an SLA to a specific contract
-	CreateSLA(ctx context.Context, id string, sla domain.SLA) (*domain.SLA, error)
+	// CreateSLA sends the transaction appending an SLA to a specific contract
+	CreateSLA(ctx context.Context, id string, sla domain.SLA) (*domain.Submission[domain.SLA], error)
 
 	// RetrieveSLAs retrieves all SLAs related to a specific contract
 	RetrieveSLAs(ctx context.Context, id string) ([]domain.SLA, error)
@@ -64,3 +65,33 @@ type CustomerService interface {
 	// DeleteCustomer removes a customer
 	DeleteCustomer(id string) error
 }
+
+// TransactionService defines the interface for tracking blockchain transactions
+type TransactionService interface {
+	// HandleConfirmed registers the function applying the payload of the transactions of an
+	// operation once they are mined successfully. Handlers are registered before any
+	// transaction is tracked or resumed.
+	HandleConfirmed(operation string, onConfirmed func(tx domain.Transaction) error)
+
+	// TrackTransaction records a pending transaction and follows it until it is mined, fails or is dropped.
+	// Following the transaction outlives ctx, but keeps its values such as the trace it belongs to.
+	TrackTransaction(ctx context.Context, tx domain.Transaction) (*domain.Transaction, error)
+
+	// Resume follows again the transactions that had not reached a final state when the service stopped
+	Resume(ctx context.Context) error
+
+	// IsPending reports whether a transaction of the operation on the contract is still being followed
+	IsPending(operation, contractID string) (bool, error)
+
+	// RetrieveTransaction retrieves a tracked transaction by hash
+	RetrieveTransaction(hash string) (*domain.Transaction, error)
+}
+
+// MedicineService defines the interface for querying the local copy of the medicine catalog
+type MedicineService interface {
+	// RetrieveMedicine retrieves a medicine by ID
+	RetrieveMedicine(id string) (*domain.Medicine, error)
+
+	// RetrieveMedicines retrieves all medicines
+	RetrieveMedicines() ([]domain.Medicine, error)
+}
@@ -2,20 +2,34 @@ package logger
 
 import (
 	"time"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 const RequestIDKey = "request_id"
 
-// GinLogger returns Gin middleware for structured logging with Zap
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the incoming request IDs that are honoured
+const maxRequestIDLength = 128
+
+// GinLogger returns Gin middleware for structured logging with Zap.
+// The request ID is taken from the incoming X-Request-ID header, or generated when it is missing
+// or invalid, and echoed back in the response. The trace ID of the request span is logged as well.
 func GinLogger(logger *zap.SugaredLogger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Generate request ID
-		requestID := uuid.New().String()
+		// Honour the caller's request ID, or generate one
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
 		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
 
 		// Start timer
 		start := time.Now()
@@ -44,6 +58,11 @@ func GinLogger(logger *zap.SugaredLogger) gin.HandlerFunc {
 			"user_agent", c.Request.UserAgent(),
 		}
 
+		// Add the trace ID when the request is traced
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+			fields = append(fields, "trace_id", spanContext.TraceID().String())
+		}
+
 		// Add error if present
 		if errorMessage != "" {
 			fields = append(fields, "error", errorMessage)
@@ -96,3 +115,17 @@ func GetRequestID(c *gin.Context) string {
 	}
 	return ""
 }
+
+// validRequestID reports whether an incoming request ID is short and printable enough to log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
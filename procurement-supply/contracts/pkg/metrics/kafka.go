@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var kafkaMessagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_messages_consumed_total",
+	Help: "Kafka messages consumed, by topic and result (success or failure).",
+}, []string{"topic", "result"})
+
+var kafkaMessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_messages_published_total",
+	Help: "Kafka messages published, by topic and result (success or failure).",
+}, []string{"topic", "result"})
+
+var kafkaMessagesRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_messages_retried_total",
+	Help: "Kafka messages forwarded to a retry topic, by original topic.",
+}, []string{"topic"})
+
+var kafkaMessagesDeadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_messages_dead_lettered_total",
+	Help: "Kafka messages forwarded to the dead-letter topic, by original topic.",
+}, []string{"topic"})
+
+// KafkaMessageConsumed counts a consumed message, failed when err is not nil
+func KafkaMessageConsumed(topic string, err error) {
+	kafkaMessagesConsumed.WithLabelValues(topic, result(err)).Inc()
+}
+
+// KafkaMessagePublished counts a published message, failed when err is not nil
+func KafkaMessagePublished(topic string, err error) {
+	kafkaMessagesPublished.WithLabelValues(topic, result(err)).Inc()
+}
+
+// KafkaMessageRetried counts a message forwarded to a retry topic
+func KafkaMessageRetried(topic string) {
+	kafkaMessagesRetried.WithLabelValues(topic).Inc()
+}
+
+// KafkaMessageDeadLettered counts a message forwarded to the dead-letter topic
+func KafkaMessageDeadLettered(topic string) {
+	kafkaMessagesDeadLettered.WithLabelValues(topic).Inc()
+}
+
+// result labels the outcome of an operation
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
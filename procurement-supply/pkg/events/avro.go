@@ -0,0 +1,160 @@
+package events
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
+	"github.com/linkedin/goavro/v2"
+)
+
+// magicByte starts every payload in the Confluent wire format, followed by the 4 bytes big-endian
+// ID of the schema in the registry and the Avro binary encoding
+const magicByte = 0
+
+// errMalformedAvro is returned for payloads not in the Confluent wire format
+var errMalformedAvro = errors.New("malformed avro payload")
+
+// ErrSchemaUnavailable is returned when the schema of an Avro payload cannot be fetched from the
+// registry. Unlike malformed payloads, decoding it again later may succeed.
+var ErrSchemaUnavailable = errors.New("avro schema unavailable")
+
+// subject returns the registry subject of the values published to a topic (topic name strategy)
+func subject(topic string) string {
+	return topic + "-value"
+}
+
+// avroEncoder writes events with a single Avro schema, registered for each topic on first use.
+//
+// Events go through their JSON form, which matches the Avro JSON encoding as long as the schema
+// only uses records and primitive types, so the Go types need no Avro specific tags.
+type avroEncoder struct {
+	registry schemaregistry.Client
+	schema   string
+	codec    *goavro.Codec
+	mu       sync.Mutex
+	ids      map[string]int // schema ID by topic
+}
+
+func newAvroEncoder(registry schemaregistry.Client, schema string) (*avroEncoder, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %w", err)
+	}
+
+	return &avroEncoder{
+		registry: registry,
+		schema:   codec.CanonicalSchema(),
+		codec:    codec,
+		ids:      make(map[string]int),
+	}, nil
+}
+
+func (e *avroEncoder) ContentType() string {
+	return ContentTypeAvro
+}
+
+func (e *avroEncoder) Encode(topic string, event any) ([]byte, error) {
+	id, err := e.schemaID(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	textual, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	native, _, err := e.codec.NativeFromTextual(textual)
+	if err != nil {
+		return nil, fmt.Errorf("event does not match the avro schema: %w", err)
+	}
+
+	payload := make([]byte, 5, 5+len(textual))
+	payload[0] = magicByte
+	binary.BigEndian.PutUint32(payload[1:], uint32(id))
+
+	return e.codec.BinaryFromNative(payload, native)
+}
+
+// schemaID registers the schema for a topic, once, and returns its ID
+func (e *avroEncoder) schemaID(topic string) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if id, ok := e.ids[topic]; ok {
+		return id, nil
+	}
+
+	id, err := e.registry.Register(subject(topic), schemaregistry.SchemaInfo{Schema: e.schema}, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register avro schema for %s: %w", topic, err)
+	}
+
+	e.ids[topic] = id
+	return id, nil
+}
+
+// avroDecoder reads events with the schema they were written with, fetched from the registry
+// by ID. Events go through their JSON form, so fields unknown to the Go type are ignored.
+type avroDecoder struct {
+	registry schemaregistry.Client
+	mu       sync.Mutex
+	codecs   map[int]*goavro.Codec // codec by schema ID
+}
+
+func newAvroDecoder(registry schemaregistry.Client) *avroDecoder {
+	return &avroDecoder{
+		registry: registry,
+		codecs:   make(map[int]*goavro.Codec),
+	}
+}
+
+func (d *avroDecoder) decode(topic string, payload []byte, event any) error {
+	if len(payload) < 5 || payload[0] != magicByte {
+		return errMalformedAvro
+	}
+
+	codec, err := d.codec(topic, int(binary.BigEndian.Uint32(payload[1:5])))
+	if err != nil {
+		return err
+	}
+
+	native, _, err := codec.NativeFromBinary(payload[5:])
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformedAvro, err)
+	}
+
+	textual, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformedAvro, err)
+	}
+
+	return json.Unmarshal(textual, event)
+}
+
+// codec returns the codec of a schema, fetching the schema from the registry on first use
+func (d *avroDecoder) codec(topic string, id int) (*goavro.Codec, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if codec, ok := d.codecs[id]; ok {
+		return codec, nil
+	}
+
+	schema, err := d.registry.GetBySubjectAndID(subject(topic), id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch schema %d: %v", ErrSchemaUnavailable, id, err)
+	}
+
+	codec, err := goavro.NewCodec(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %d: %w", id, err)
+	}
+
+	d.codecs[id] = codec
+	return codec, nil
+}
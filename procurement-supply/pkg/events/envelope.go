@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion is the version of the envelope written by this service. Versions only add
+// optional fields, so consumers accept every event up to the version they know. Events written
+// before the envelope was versioned read as version 0.
+const SchemaVersion = 1
+
+// ErrUnsupportedVersion is returned for events written with a newer envelope than this service knows
+var ErrUnsupportedVersion = errors.New("unsupported event schema version")
+
+// Type identifies the kind of an event, such as medicine.updated
+type Type string
+
+// Envelope wraps every event exchanged between the services.
+//
+// Every module publishing or consuming events depends on this module, so all of them agree on
+// the envelope.
+type Envelope[T any] struct {
+	ID            string    `json:"event_id"` // stable across redeliveries, for consumers to deduplicate on
+	EventType     Type      `json:"event_type"`
+	SchemaVersion int       `json:"schema_version"` // version of the envelope, see SchemaVersion
+	Source        string    `json:"source"`         // service that raised the event
+	CorrelationID string    `json:"correlation_id"` // shared by the events caused by the same request
+	Data          T         `json:"data"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// New creates an event of the current schema version with a new ID
+func New[T any](eventType Type, source string, data T) *Envelope[T] {
+	return &Envelope[T]{
+		ID:            uuid.New().String(),
+		EventType:     eventType,
+		SchemaVersion: SchemaVersion,
+		Source:        source,
+		Data:          data,
+		Timestamp:     time.Now().UTC(),
+	}
+}
+
+// Validate rejects events written with a newer envelope than this service knows
+func (e *Envelope[T]) Validate() error {
+	if e.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("%w: %d, up to %d is supported", ErrUnsupportedVersion, e.SchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+// correlationKey is the context key of the correlation ID
+type correlationKey struct{}
+
+// WithCorrelationID returns ctx carrying the correlation ID given to the events raised within it
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlationID)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or an empty string
+func CorrelationID(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationKey{}).(string)
+	return correlationID
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/confluentinc/confluent-kafka-go/schemaregistry"
+)
+
+// Message headers describing an event, so consumers can route and decode it before reading the payload
+const (
+	HeaderEventID       = "event_id"
+	HeaderEventType     = "event_type"
+	HeaderSchemaVersion = "schema_version"
+	HeaderContentType   = "content_type"
+)
+
+// Content types of the event payloads. Messages without a content type header hold JSON.
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeAvro = "application/vnd.apache.avro+binary" // Confluent wire format, schema in the registry
+)
+
+// Formats an Encoder can be created for
+const (
+	FormatJSON = "json"
+	FormatAvro = "avro"
+)
+
+// ErrUnsupportedContentType is returned when decoding a payload in a format this service cannot read
+var ErrUnsupportedContentType = errors.New("unsupported event content type")
+
+// Encoder serializes the events published to a topic in a single format
+type Encoder interface {
+	// ContentType returns the content type header of the encoded payloads
+	ContentType() string
+
+	// Encode serializes an event published to topic
+	Encode(topic string, event any) ([]byte, error)
+}
+
+// NewEncoder creates the encoder of a format. Avro needs a schema registry and the Avro schema
+// of the events, which is registered under the <topic>-value subject of each topic.
+func NewEncoder(format string, registry schemaregistry.Client, avroSchema string) (Encoder, error) {
+	switch format {
+	case "", FormatJSON:
+		return jsonEncoder{}, nil
+	case FormatAvro:
+		if registry == nil {
+			return nil, fmt.Errorf("the %s format needs a schema registry", format)
+		}
+		return newAvroEncoder(registry, avroSchema)
+	default:
+		return nil, fmt.Errorf("unsupported event format: %s", format)
+	}
+}
+
+// NewRegistry creates a schema registry client, or returns nil when url is empty.
+// A mock:// url creates an in-process registry, meant for local testing.
+func NewRegistry(url string) (schemaregistry.Client, error) {
+	if url == "" {
+		return nil, nil
+	}
+
+	registry, err := schemaregistry.NewClient(schemaregistry.NewConfig(url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema registry client: %w", err)
+	}
+	return registry, nil
+}
+
+// NewJSONEncoder creates the encoder writing events as plain JSON
+func NewJSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+// jsonEncoder writes events as plain JSON
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string {
+	return ContentTypeJSON
+}
+
+func (jsonEncoder) Encode(_ string, event any) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Decoder reads events in any supported format, picked from the content type header of each
+// message. Without a registry, Avro events cannot be read.
+type Decoder struct {
+	avro *avroDecoder
+}
+
+// NewDecoder creates a new event decoder, registry may be nil
+func NewDecoder(registry schemaregistry.Client) *Decoder {
+	decoder := &Decoder{}
+	if registry != nil {
+		decoder.avro = newAvroDecoder(registry)
+	}
+	return decoder
+}
+
+// Decode deserializes an event consumed from topic into event
+func (d *Decoder) Decode(contentType, topic string, payload []byte, event any) error {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return json.Unmarshal(payload, event)
+	case ContentTypeAvro:
+		if d.avro == nil {
+			return fmt.Errorf("%w: %s without a schema registry", ErrUnsupportedContentType, contentType)
+		}
+		return d.avro.decode(topic, payload, event)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+}
+
+// Headers returns the message headers describing an event encoded with the given content type
+func (e *Envelope[T]) Headers(contentType string) []kafka.Header {
+	return []kafka.Header{
+		{Key: HeaderEventID, Value: []byte(e.ID)},
+		{Key: HeaderEventType, Value: []byte(e.EventType)},
+		{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(e.SchemaVersion))},
+		{Key: HeaderContentType, Value: []byte(contentType)},
+	}
+}
+
+// ContentType returns the content type header of a message, or an empty string
+func ContentType(msg *kafka.Message) string {
+	for i := len(msg.Headers) - 1; i >= 0; i-- {
+		if msg.Headers[i].Key == HeaderContentType {
+			return string(msg.Headers[i].Value)
+		}
+	}
+	return ""
+}
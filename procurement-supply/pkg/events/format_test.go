@@ -0,0 +1,139 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testItem is the payload of the events used in the tests
+type testItem struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+const testSchema = `{
+  "type": "record",
+  "name": "ItemEvent",
+  "namespace": "medisupply.test",
+  "fields": [
+    {"name": "event_id", "type": "string"},
+    {"name": "event_type", "type": "string"},
+    {"name": "schema_version", "type": "int"},
+    {"name": "source", "type": "string"},
+    {"name": "correlation_id", "type": "string", "default": ""},
+    {
+      "name": "data",
+      "type": {
+        "type": "record",
+        "name": "Item",
+        "fields": [
+          {"name": "id", "type": "string"},
+          {"name": "name", "type": "string"},
+          {"name": "price", "type": "double"}
+        ]
+      }
+    },
+    {"name": "timestamp", "type": "string"}
+  ]
+}`
+
+const testTopic = "item-events"
+
+func newTestEvent() *Envelope[testItem] {
+	event := New[testItem]("item.created", "test", testItem{ID: "item-1", Name: "Ibuprofen", Price: 12.5})
+	event.CorrelationID = "correlation-1"
+	event.Timestamp = event.Timestamp.Truncate(time.Microsecond)
+	return event
+}
+
+func TestAvroRoundTrip(t *testing.T) {
+	registry, err := NewRegistry("mock://events-test")
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	encoder, err := NewEncoder(FormatAvro, registry, testSchema)
+	if err != nil {
+		t.Fatalf("NewEncoder() error = %v", err)
+	}
+	if encoder.ContentType() != ContentTypeAvro {
+		t.Errorf("ContentType() = %s, want %s", encoder.ContentType(), ContentTypeAvro)
+	}
+
+	event := newTestEvent()
+	payload, err := encoder.Encode(testTopic, event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if payload[0] != magicByte {
+		t.Errorf("payload starts with %d, want the magic byte", payload[0])
+	}
+
+	var decoded Envelope[testItem]
+	if err := NewDecoder(registry).Decode(encoder.ContentType(), testTopic, payload, &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if decoded.ID != event.ID || decoded.EventType != event.EventType || decoded.SchemaVersion != event.SchemaVersion ||
+		decoded.Source != event.Source || decoded.CorrelationID != event.CorrelationID || decoded.Data != event.Data ||
+		!decoded.Timestamp.Equal(event.Timestamp) {
+		t.Errorf("Decode() = %+v, want %+v", decoded, *event)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	encoder, err := NewEncoder("", nil, "")
+	if err != nil {
+		t.Fatalf("NewEncoder() error = %v", err)
+	}
+
+	event := newTestEvent()
+	payload, err := encoder.Encode(testTopic, event)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	// Messages without a content type header hold JSON
+	var decoded Envelope[testItem]
+	if err := NewDecoder(nil).Decode("", testTopic, payload, &decoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.ID != event.ID || decoded.Data != event.Data {
+		t.Errorf("Decode() = %+v, want %+v", decoded, *event)
+	}
+}
+
+func TestDecodeAvroErrors(t *testing.T) {
+	registry, err := NewRegistry("mock://events-test-errors")
+	if err != nil {
+		t.Fatalf("NewRegistry() error = %v", err)
+	}
+
+	var event Envelope[testItem]
+
+	if err := NewDecoder(nil).Decode(ContentTypeAvro, testTopic, []byte{magicByte, 0, 0, 0, 1}, &event); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("Decode() without a registry error = %v, want %v", err, ErrUnsupportedContentType)
+	}
+
+	decoder := NewDecoder(registry)
+	if err := decoder.Decode(ContentTypeAvro, testTopic, []byte("{}"), &event); !errors.Is(err, errMalformedAvro) {
+		t.Errorf("Decode() of JSON error = %v, want %v", err, errMalformedAvro)
+	}
+	if err := decoder.Decode(ContentTypeAvro, testTopic, []byte{magicByte, 0, 0, 0, 42, 0}, &event); !errors.Is(err, ErrSchemaUnavailable) {
+		t.Errorf("Decode() with an unknown schema error = %v, want %v", err, ErrSchemaUnavailable)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	event := newTestEvent()
+	if err := event.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	event.SchemaVersion = SchemaVersion + 1
+	if err := event.Validate(); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Validate() of a newer event error = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
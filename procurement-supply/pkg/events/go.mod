@@ -0,0 +1,11 @@
+module procurement-supply/pkg/events
+
+go 1.24.0
+
+require (
+	github.com/confluentinc/confluent-kafka-go v1.9.2
+	github.com/google/uuid v1.6.0
+	github.com/linkedin/goavro/v2 v2.12.0
+)
+
+require github.com/golang/snappy v0.0.4 // indirect
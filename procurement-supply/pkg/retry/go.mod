@@ -0,0 +1,5 @@
+module procurement-supply/pkg/retry
+
+go 1.24.0
+
+require github.com/confluentinc/confluent-kafka-go v1.9.2
@@ -0,0 +1,204 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// seekTimeoutMs bounds how long rewinding a partition may block the poll loop
+const seekTimeoutMs = 5000
+
+// Consumer is the part of a Kafka consumer the Handler drives, implemented by *kafka.Consumer
+type Consumer interface {
+	Pause(partitions []kafka.TopicPartition) error
+	Resume(partitions []kafka.TopicPartition) error
+	Seek(partition kafka.TopicPartition, timeoutMs int) error
+	CommitMessage(msg *kafka.Message) ([]kafka.TopicPartition, error)
+}
+
+// Producer is the part of a Kafka producer the Handler forwards messages with, implemented by
+// *kafka.Producer
+type Producer interface {
+	Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error
+}
+
+// Action is what became of a handled message
+type Action int
+
+const (
+	Committed    Action = iota // processed, its offset committed
+	Retried                    // forwarded to a retry topic, its offset committed
+	DeadLettered               // forwarded to the dead-letter topic, its offset committed
+	Rewound                    // neither processed nor forwarded, its partition rewound to consume it again
+)
+
+// Outcome describes what became of a handled message
+type Outcome struct {
+	Action        Action
+	OriginalTopic string
+	Destination   string        // retry or dead-letter topic the message was forwarded to
+	Attempts      int           // processing attempts across every topic the message went through
+	Delay         time.Duration // delay of the retry topic the message was forwarded to
+}
+
+// pausedPartition is a retry topic partition waiting for its head message to become due
+type pausedPartition struct {
+	partition kafka.TopicPartition
+	until     time.Time
+}
+
+// Handler applies a retry policy to the messages of a consumer. It must be used from the
+// goroutine polling the consumer.
+type Handler struct {
+	consumer Consumer
+	producer Producer
+	policy   Policy
+	inject   func(ctx context.Context, msg *kafka.Message)
+	paused   map[string]pausedPartition
+	now      func() time.Time
+}
+
+// NewHandler creates a new retry handler. inject, when set, writes the trace context of the
+// failed attempt into the headers of each forwarded message.
+func NewHandler(consumer Consumer, producer Producer, policy Policy, inject func(ctx context.Context, msg *kafka.Message)) *Handler {
+	return &Handler{
+		consumer: consumer,
+		producer: producer,
+		policy:   policy,
+		inject:   inject,
+		paused:   make(map[string]pausedPartition),
+		now:      time.Now,
+	}
+}
+
+// Policy returns the retry policy of the handler
+func (h *Handler) Policy() Policy {
+	return h.policy
+}
+
+// Defer pauses the partition of a retry message that is not due yet and rewinds it, so the
+// message is fetched again once ResumeDue resumes the partition. It reports whether the message
+// was deferred, and so must not be processed now, and until when. An error is returned when the
+// partition could not be paused, and the message is processed early, or could not be rewound.
+func (h *Handler) Defer(msg *kafka.Message) (time.Time, bool, error) {
+	notBefore, err := strconv.ParseInt(header(msg, HeaderRetryNotBefore), 10, 64)
+	if err != nil {
+		return time.Time{}, false, nil
+	}
+
+	until := time.UnixMilli(notBefore)
+	if !h.now().Before(until) {
+		return time.Time{}, false, nil
+	}
+
+	partition := kafka.TopicPartition{
+		Topic:     msg.TopicPartition.Topic,
+		Partition: msg.TopicPartition.Partition,
+		Offset:    msg.TopicPartition.Offset,
+	}
+
+	if err := h.consumer.Pause([]kafka.TopicPartition{partition}); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to pause %s [%d]: %w", *partition.Topic, partition.Partition, err)
+	}
+
+	h.paused[fmt.Sprintf("%s/%d", *partition.Topic, partition.Partition)] = pausedPartition{
+		partition: partition,
+		until:     until,
+	}
+
+	return until, true, h.rewind(msg)
+}
+
+// ResumeDue resumes the paused partitions whose head message became due. The partitions that
+// could not be resumed were most likely revoked by a rebalance, and their new owner starts
+// them unpaused; their errors are returned joined.
+func (h *Handler) ResumeDue() error {
+	now := h.now()
+
+	var errs []error
+	for key, paused := range h.paused {
+		if now.Before(paused.until) {
+			continue
+		}
+
+		delete(h.paused, key)
+		if err := h.consumer.Resume([]kafka.TopicPartition{paused.partition}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to resume %s [%d]: %w", *paused.partition.Topic, paused.partition.Partition, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Settle commits the offset of a message once it is processed. A message whose processing failed
+// after attempts in-memory attempts is forwarded to its next retry topic or to the dead-letter
+// topic first. When it is not forwarded, because ctx was cancelled or the producer failed, its
+// partition is rewound so the message is not skipped by the commit of a later one.
+func (h *Handler) Settle(ctx context.Context, msg *kafka.Message, attempts int, processErr error) (Outcome, error) {
+	outcome := Outcome{
+		Action:        Committed,
+		OriginalTopic: OriginalTopic(msg),
+		Attempts:      attempts,
+	}
+
+	if processErr != nil {
+		if ctx.Err() != nil {
+			return h.rewound(outcome, msg, processErr)
+		}
+
+		forwarded, forwardOutcome := h.policy.forward(msg, attempts, processErr, h.now())
+		if h.inject != nil {
+			h.inject(ctx, forwarded)
+		}
+
+		if err := h.produce(ctx, forwarded); err != nil {
+			return h.rewound(outcome, msg, fmt.Errorf("failed to forward message to %s: %w", forwardOutcome.Destination, err))
+		}
+		outcome = forwardOutcome
+	}
+
+	if _, err := h.consumer.CommitMessage(msg); err != nil {
+		return outcome, fmt.Errorf("failed to commit offset: %w", err)
+	}
+
+	return outcome, nil
+}
+
+// rewound rewinds the partition of a message that could not be settled
+func (h *Handler) rewound(outcome Outcome, msg *kafka.Message, cause error) (Outcome, error) {
+	outcome.Action = Rewound
+	return outcome, errors.Join(cause, h.rewind(msg))
+}
+
+// rewind seeks the partition back to the message, so it is consumed again instead of being
+// skipped by the commit of a later message
+func (h *Handler) rewind(msg *kafka.Message) error {
+	if err := h.consumer.Seek(msg.TopicPartition, seekTimeoutMs); err != nil {
+		return fmt.Errorf("failed to rewind %s [%d] to offset %d: %w",
+			*msg.TopicPartition.Topic, msg.TopicPartition.Partition, msg.TopicPartition.Offset, err)
+	}
+	return nil
+}
+
+// produce publishes a message and waits for the broker to acknowledge it
+func (h *Handler) produce(ctx context.Context, msg *kafka.Message) error {
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := h.producer.Produce(msg, deliveryChan); err != nil {
+		return err
+	}
+
+	select {
+	case e := <-deliveryChan:
+		if m, ok := e.(*kafka.Message); ok && m.TopicPartition.Error != nil {
+			return m.TopicPartition.Error
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
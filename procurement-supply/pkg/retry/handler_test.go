@@ -0,0 +1,237 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// fakeConsumer records the calls the Handler makes on a consumer
+type fakeConsumer struct {
+	paused    []kafka.TopicPartition
+	resumed   []kafka.TopicPartition
+	seeks     []kafka.TopicPartition
+	committed []kafka.Offset
+}
+
+func (c *fakeConsumer) Pause(partitions []kafka.TopicPartition) error {
+	c.paused = append(c.paused, partitions...)
+	return nil
+}
+
+func (c *fakeConsumer) Resume(partitions []kafka.TopicPartition) error {
+	c.resumed = append(c.resumed, partitions...)
+	return nil
+}
+
+func (c *fakeConsumer) Seek(partition kafka.TopicPartition, _ int) error {
+	c.seeks = append(c.seeks, partition)
+	return nil
+}
+
+func (c *fakeConsumer) CommitMessage(msg *kafka.Message) ([]kafka.TopicPartition, error) {
+	c.committed = append(c.committed, msg.TopicPartition.Offset)
+	return nil, nil
+}
+
+// fakeProducer acknowledges every message it is given, or fails them all when err is set
+type fakeProducer struct {
+	err      error
+	produced []*kafka.Message
+}
+
+func (p *fakeProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	p.produced = append(p.produced, msg)
+	delivered := *msg
+	delivered.TopicPartition.Error = p.err
+	deliveryChan <- &delivered
+	return nil
+}
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newTestHandler(policy Policy) (*Handler, *fakeConsumer, *fakeProducer) {
+	consumer, producer := &fakeConsumer{}, &fakeProducer{}
+	handler := NewHandler(consumer, producer, policy, nil)
+	handler.now = func() time.Time { return testNow }
+	return handler, consumer, producer
+}
+
+func newMessage(topic string, partition int32, offset kafka.Offset, headers ...kafka.Header) *kafka.Message {
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: partition, Offset: offset},
+		Key:            []byte("key"),
+		Value:          []byte("value"),
+		Headers:        headers,
+	}
+}
+
+// redeliver returns the message as it is consumed from the topic it was forwarded to
+func redeliver(forwarded *kafka.Message, partition int32, offset kafka.Offset) *kafka.Message {
+	return newMessage(*forwarded.TopicPartition.Topic, partition, offset, forwarded.Headers...)
+}
+
+func TestSettleKeepsTheOriginalMessageAcrossStages(t *testing.T) {
+	policy := Policy{RetryDelays: []time.Duration{30 * time.Second, 5 * time.Minute}}
+	handler, consumer, producer := newTestHandler(policy)
+	ctx := context.Background()
+	failure := errors.New("contract service unavailable")
+
+	msg := newMessage("contracts", 2, 41)
+	wants := []Outcome{
+		{Action: Retried, OriginalTopic: "contracts", Destination: "contracts.retry.1", Attempts: 3, Delay: 30 * time.Second},
+		{Action: Retried, OriginalTopic: "contracts", Destination: "contracts.retry.2", Attempts: 6, Delay: 5 * time.Minute},
+		{Action: DeadLettered, OriginalTopic: "contracts", Destination: "contracts.dlq", Attempts: 9},
+	}
+	for stage, want := range wants {
+		outcome, err := handler.Settle(ctx, msg, 3, failure)
+		if err != nil {
+			t.Fatalf("stage %d: Settle() error = %v", stage, err)
+		}
+		if outcome != want {
+			t.Errorf("stage %d: Settle() = %+v, want %+v", stage, outcome, want)
+		}
+
+		forwarded := producer.produced[len(producer.produced)-1]
+		if got := *forwarded.TopicPartition.Topic; got != want.Destination {
+			t.Errorf("stage %d: forwarded to %s, want %s", stage, got, want.Destination)
+		}
+		for key, value := range map[string]string{
+			HeaderOriginalTopic:     "contracts",
+			HeaderOriginalPartition: "2",
+			HeaderOriginalOffset:    "41",
+			HeaderAttempts:          strconv.Itoa(want.Attempts),
+			HeaderError:             failure.Error(),
+		} {
+			if got := header(forwarded, key); got != value {
+				t.Errorf("stage %d: header %s = %q, want %q", stage, key, got, value)
+			}
+		}
+		if want.Action == Retried {
+			notBefore := strconv.FormatInt(testNow.Add(want.Delay).UnixMilli(), 10)
+			if got := header(forwarded, HeaderRetryNotBefore); got != notBefore {
+				t.Errorf("stage %d: header %s = %q, want %q", stage, HeaderRetryNotBefore, got, notBefore)
+			}
+		}
+
+		// The retry topic has partitions and offsets of its own
+		msg = redeliver(forwarded, 0, kafka.Offset(stage))
+	}
+
+	if len(consumer.committed) != len(wants) || len(consumer.seeks) != 0 {
+		t.Errorf("committed %v and rewound %v, want every message committed", consumer.committed, consumer.seeks)
+	}
+}
+
+func TestSettleDeadLettersMalformedMessagesRightAway(t *testing.T) {
+	policy := Policy{RetryDelays: []time.Duration{time.Minute}, DeadLetterTopic: "procurement.dlq"}
+	handler, _, producer := newTestHandler(policy)
+
+	outcome, err := handler.Settle(context.Background(), newMessage("contracts", 0, 7), 1, ErrMalformedMessage)
+	if err != nil {
+		t.Fatalf("Settle() error = %v", err)
+	}
+	if outcome.Action != DeadLettered || outcome.Destination != "procurement.dlq" {
+		t.Errorf("Settle() = %+v, want dead-lettered to procurement.dlq", outcome)
+	}
+	if len(producer.produced) != 1 || header(producer.produced[0], HeaderRetryStage) != "" {
+		t.Errorf("produced %d messages, want one without a retry stage", len(producer.produced))
+	}
+}
+
+func TestSettleCommitsOrRewinds(t *testing.T) {
+	failure := errors.New("contract service unavailable")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		processErr  error
+		produceErr  error
+		wantAction  Action
+		wantErr     bool
+		wantProduce bool
+	}{
+		{name: "processed", ctx: context.Background(), wantAction: Committed},
+		{name: "forwarded", ctx: context.Background(), processErr: failure, wantAction: Retried, wantProduce: true},
+		{name: "forward failed", ctx: context.Background(), processErr: failure, produceErr: errors.New("broker down"), wantAction: Rewound, wantErr: true, wantProduce: true},
+		{name: "shutting down", ctx: cancelled, processErr: context.Canceled, wantAction: Rewound, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, consumer, producer := newTestHandler(Policy{RetryDelays: []time.Duration{time.Minute}})
+			producer.err = tt.produceErr
+			msg := newMessage("contracts", 1, 12)
+
+			outcome, err := handler.Settle(tt.ctx, msg, 1, tt.processErr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Settle() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if outcome.Action != tt.wantAction {
+				t.Errorf("Settle() action = %v, want %v", outcome.Action, tt.wantAction)
+			}
+			if got := len(producer.produced) > 0; got != tt.wantProduce {
+				t.Errorf("produced = %t, want %t", got, tt.wantProduce)
+			}
+
+			if tt.wantAction == Rewound {
+				if len(consumer.committed) != 0 || len(consumer.seeks) != 1 || consumer.seeks[0].Offset != 12 {
+					t.Errorf("committed %v and rewound %v, want only a rewind to offset 12", consumer.committed, consumer.seeks)
+				}
+			} else if !slices.Equal(consumer.committed, []kafka.Offset{12}) || len(consumer.seeks) != 0 {
+				t.Errorf("committed %v and rewound %v, want only offset 12 committed", consumer.committed, consumer.seeks)
+			}
+		})
+	}
+}
+
+func TestDeferPausesThePartitionUntilTheMessageIsDue(t *testing.T) {
+	handler, consumer, _ := newTestHandler(Policy{})
+	due := testNow.Add(30 * time.Second)
+	notBefore := kafka.Header{Key: HeaderRetryNotBefore, Value: []byte(strconv.FormatInt(due.UnixMilli(), 10))}
+
+	// Messages without a due time, or already due, are processed right away
+	for _, msg := range []*kafka.Message{
+		newMessage("contracts", 0, 1),
+		newMessage("contracts.retry.1", 0, 2, kafka.Header{Key: HeaderRetryNotBefore, Value: []byte(strconv.FormatInt(testNow.UnixMilli(), 10))}),
+	} {
+		if _, deferred, err := handler.Defer(msg); deferred || err != nil {
+			t.Errorf("Defer(%s) = %t, %v, want not deferred", *msg.TopicPartition.Topic, deferred, err)
+		}
+	}
+
+	until, deferred, err := handler.Defer(newMessage("contracts.retry.1", 3, 8, notBefore))
+	if err != nil || !deferred || !until.Equal(due) {
+		t.Fatalf("Defer() = %v, %t, %v, want deferred until %v", until, deferred, err, due)
+	}
+	if len(consumer.paused) != 1 || consumer.paused[0].Partition != 3 {
+		t.Errorf("paused %v, want partition 3", consumer.paused)
+	}
+	if len(consumer.seeks) != 1 || consumer.seeks[0].Offset != 8 {
+		t.Errorf("rewound %v, want offset 8", consumer.seeks)
+	}
+
+	// The partition stays paused until the message is due
+	if err := handler.ResumeDue(); err != nil || len(consumer.resumed) != 0 {
+		t.Fatalf("ResumeDue() = %v, resumed %v before the message is due", err, consumer.resumed)
+	}
+
+	handler.now = func() time.Time { return due }
+	if err := handler.ResumeDue(); err != nil {
+		t.Fatalf("ResumeDue() error = %v", err)
+	}
+	if len(consumer.resumed) != 1 || consumer.resumed[0].Partition != 3 {
+		t.Errorf("resumed %v, want partition 3", consumer.resumed)
+	}
+
+	// A resumed partition is resumed only once
+	if err := handler.ResumeDue(); err != nil || len(consumer.resumed) != 1 {
+		t.Errorf("ResumeDue() = %v, resumed %v, want partition 3 once", err, consumer.resumed)
+	}
+}
@@ -0,0 +1,206 @@
+// Package retry handles the Kafka messages a consumer failed to process: they are retried in
+// memory first, then re-published to a chain of delayed retry topics and finally to a dead-letter
+// topic, without ever blocking the partition they came from.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// Headers written on messages forwarded to a retry or dead-letter topic
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderAttempts          = "x-attempts"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+	HeaderRetryStage        = "x-retry-stage"
+	HeaderRetryNotBefore    = "x-retry-not-before"
+)
+
+// defaultMaxPollInterval is the librdkafka default of max.poll.interval.ms
+const defaultMaxPollInterval = 300000 * time.Millisecond
+
+// ErrMalformedMessage marks messages that can never be processed, so they skip the retry topics
+var ErrMalformedMessage = errors.New("malformed message")
+
+// Policy decides what happens to a message whose processing failed. It is retried in memory
+// first, then re-published to each retry topic in turn and finally sent to the dead-letter topic.
+type Policy struct {
+	Attempts        int             // in-memory attempts per delivery (minimum 1)
+	Backoff         time.Duration   // wait before the first in-memory retry, doubled after each attempt
+	RetryDelays     []time.Duration // delay of each retry topic, named <topic>.retry.<n>, tried in order
+	DeadLetterTopic string          // where exhausted messages go, defaults to <topic>.dlq
+}
+
+// Topic returns the name of the nth (1-based) retry topic of a topic
+func Topic(topic string, stage int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, stage)
+}
+
+// Subscriptions returns the topics plus each of their retry topics
+func (p Policy) Subscriptions(topics []string) []string {
+	subscriptions := make([]string, 0, len(topics)*(len(p.RetryDelays)+1))
+	for _, topic := range topics {
+		subscriptions = append(subscriptions, topic)
+		for stage := 1; stage <= len(p.RetryDelays); stage++ {
+			subscriptions = append(subscriptions, Topic(topic, stage))
+		}
+	}
+	return subscriptions
+}
+
+// deadLetterTopic returns the dead-letter topic for messages of the original topic
+func (p Policy) deadLetterTopic(originalTopic string) string {
+	if p.DeadLetterTopic != "" {
+		return p.DeadLetterTopic
+	}
+	return originalTopic + ".dlq"
+}
+
+// InMemoryWait returns how long a delivery that fails every in-memory attempt waits in total
+// between them
+func (p Policy) InMemoryWait() time.Duration {
+	var total time.Duration
+	backoff := p.Backoff
+	for attempt := 1; attempt < max(p.Attempts, 1); attempt++ {
+		total += backoff
+		backoff *= 2
+	}
+	return total
+}
+
+// Validate rejects a policy whose in-memory retries keep the consumer from polling for more than
+// half of maxPollInterval, leaving the other half to the processing attempts themselves. Past
+// max.poll.interval.ms the consumer leaves its group and its partitions are given to another one.
+func (p Policy) Validate(maxPollInterval time.Duration) error {
+	if wait := p.InMemoryWait(); wait > maxPollInterval/2 {
+		return fmt.Errorf("in-memory retries wait %s in total, more than half of max.poll.interval.ms (%s): lower the attempts or the backoff",
+			wait, maxPollInterval)
+	}
+	return nil
+}
+
+// Do calls fn until it succeeds or the in-memory attempts are exhausted, doubling the backoff
+// after each failure. It returns the number of attempts made and the last error.
+func (p Policy) Do(ctx context.Context, fn func(attempt int) error) (int, error) {
+	attempts := max(p.Attempts, 1)
+	backoff := p.Backoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt == attempts {
+			return attempt, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// MaxPollInterval returns the max.poll.interval.ms of a consumer configuration, or the
+// librdkafka default when it is not set
+func MaxPollInterval(config kafka.ConfigMap) (time.Duration, error) {
+	value, ok := config["max.poll.interval.ms"]
+	if !ok {
+		return defaultMaxPollInterval, nil
+	}
+
+	switch ms := value.(type) {
+	case int:
+		return time.Duration(ms) * time.Millisecond, nil
+	case string:
+		parsed, err := strconv.Atoi(ms)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max.poll.interval.ms %q: %w", ms, err)
+		}
+		return time.Duration(parsed) * time.Millisecond, nil
+	default:
+		return 0, fmt.Errorf("invalid max.poll.interval.ms %v", value)
+	}
+}
+
+// OriginalTopic returns the topic a message was first published to, before any retry topic
+func OriginalTopic(msg *kafka.Message) string {
+	return headerOr(msg, HeaderOriginalTopic, *msg.TopicPartition.Topic)
+}
+
+// forward returns the message re-publishing a message that failed processing to its next retry
+// topic, or to the dead-letter topic once the retry topics are exhausted or the message is
+// malformed, and the outcome it stands for. The headers locating the original message are kept
+// from the first failure on.
+func (p Policy) forward(msg *kafka.Message, attempts int, cause error, now time.Time) (*kafka.Message, Outcome) {
+	originalTopic := OriginalTopic(msg)
+	stage, _ := strconv.Atoi(header(msg, HeaderRetryStage))
+	previousAttempts, _ := strconv.Atoi(header(msg, HeaderAttempts))
+
+	forwarded := &kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: append([]kafka.Header(nil), msg.Headers...),
+	}
+	setHeader(forwarded, HeaderOriginalTopic, originalTopic)
+	setHeader(forwarded, HeaderOriginalPartition, headerOr(msg, HeaderOriginalPartition, strconv.Itoa(int(msg.TopicPartition.Partition))))
+	setHeader(forwarded, HeaderOriginalOffset, headerOr(msg, HeaderOriginalOffset, strconv.FormatInt(int64(msg.TopicPartition.Offset), 10)))
+	setHeader(forwarded, HeaderAttempts, strconv.Itoa(previousAttempts+attempts))
+	setHeader(forwarded, HeaderError, cause.Error())
+	setHeader(forwarded, HeaderFailedAt, now.UTC().Format(time.RFC3339Nano))
+
+	outcome := Outcome{
+		OriginalTopic: originalTopic,
+		Attempts:      previousAttempts + attempts,
+	}
+	if errors.Is(cause, ErrMalformedMessage) || stage >= len(p.RetryDelays) {
+		outcome.Action = DeadLettered
+		outcome.Destination = p.deadLetterTopic(originalTopic)
+	} else {
+		outcome.Action = Retried
+		outcome.Destination = Topic(originalTopic, stage+1)
+		outcome.Delay = p.RetryDelays[stage]
+		setHeader(forwarded, HeaderRetryStage, strconv.Itoa(stage+1))
+		setHeader(forwarded, HeaderRetryNotBefore, strconv.FormatInt(now.Add(outcome.Delay).UnixMilli(), 10))
+	}
+	forwarded.TopicPartition = kafka.TopicPartition{Topic: &outcome.Destination, Partition: kafka.PartitionAny}
+
+	return forwarded, outcome
+}
+
+// header returns the value of the last header with the given key, or an empty string
+func header(msg *kafka.Message, key string) string {
+	for i := len(msg.Headers) - 1; i >= 0; i-- {
+		if msg.Headers[i].Key == key {
+			return string(msg.Headers[i].Value)
+		}
+	}
+	return ""
+}
+
+// headerOr returns the value of the header with the given key, or the fallback when it is missing
+func headerOr(msg *kafka.Message, key, fallback string) string {
+	if value := header(msg, key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// setHeader replaces the headers with the given key by a single one holding the value
+func setHeader(msg *kafka.Message, key, value string) {
+	headers := msg.Headers[:0]
+	for _, h := range msg.Headers {
+		if h.Key != key {
+			headers = append(headers, h)
+		}
+	}
+	msg.Headers = append(headers, kafka.Header{Key: key, Value: []byte(value)})
+}
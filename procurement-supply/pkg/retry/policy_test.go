@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestPolicySubscriptions(t *testing.T) {
+	policy := Policy{RetryDelays: []time.Duration{time.Second, time.Minute}}
+
+	got := policy.Subscriptions([]string{"contracts", "slas"})
+	want := []string{"contracts", "contracts.retry.1", "contracts.retry.2", "slas", "slas.retry.1", "slas.retry.2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Subscriptions() = %v, want %v", got, want)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{name: "defaults", policy: Policy{Attempts: 3, Backoff: 200 * time.Millisecond}},
+		{name: "single attempt never waits", policy: Policy{Attempts: 1, Backoff: time.Hour}},
+		{name: "half the poll interval", policy: Policy{Attempts: 3, Backoff: 50 * time.Second}},
+		{name: "past half the poll interval", policy: Policy{Attempts: 3, Backoff: 51 * time.Second}, wantErr: true},
+		{name: "doubling adds up", policy: Policy{Attempts: 10, Backoff: time.Second}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(5 * time.Minute); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxPollInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  kafka.ConfigMap
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", config: kafka.ConfigMap{}, want: 5 * time.Minute},
+		{name: "int", config: kafka.ConfigMap{"max.poll.interval.ms": 60000}, want: time.Minute},
+		{name: "string", config: kafka.ConfigMap{"max.poll.interval.ms": "60000"}, want: time.Minute},
+		{name: "invalid", config: kafka.ConfigMap{"max.poll.interval.ms": "soon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MaxPollInterval(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MaxPollInterval() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MaxPollInterval() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyDo(t *testing.T) {
+	policy := Policy{Attempts: 3, Backoff: time.Millisecond}
+	failure := errors.New("unavailable")
+
+	calls := 0
+	attempts, err := policy.Do(context.Background(), func(int) error {
+		calls++
+		if calls < 2 {
+			return failure
+		}
+		return nil
+	})
+	if attempts != 2 || err != nil {
+		t.Errorf("Do() = %d, %v, want success on attempt 2", attempts, err)
+	}
+
+	attempts, err = policy.Do(context.Background(), func(int) error { return failure })
+	if attempts != 3 || !errors.Is(err, failure) {
+		t.Errorf("Do() = %d, %v, want %v after 3 attempts", attempts, err, failure)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts, err = policy.Do(ctx, func(int) error { return failure })
+	if attempts != 1 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() = %d, %v, want to stop after the first attempt once cancelled", attempts, err)
+	}
+}
@@ -3,12 +3,22 @@ package main
 import (
 	"context"
 	"errors"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
 	"os"
 	"os/signal"
+	"procurement-supply/pkg/events"
+	"procurement-supply/pkg/retry"
 	"purchase-plans/internal/adapter/queue"
+	"purchase-plans/internal/adapter/storage/memory"
+	"purchase-plans/internal/adapter/storage/postgres"
 	"purchase-plans/internal/core/application"
+	"purchase-plans/internal/core/port/driven"
 	"purchase-plans/pkg/logger"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
@@ -22,6 +32,18 @@ func main() {
 	kafkaHost := getEnv("KAFKA_HOST", "localhost:9092")
 	kafkaGroupId := getEnv("KAFKA_GROUP_ID", "purchase-plan-service")
 	kafkaTopics := getEnv("KAFKA_TOPICS", "medicine-events")
+	metricsAddr := getEnv("METRICS_ADDR", ":9090")
+	storageDriver := getEnv("STORAGE_DRIVER", "memory")
+	databaseURL := os.Getenv("DATABASE_URL")
+	schemaRegistryURL := os.Getenv("SCHEMA_REGISTRY_URL")
+
+	// Retry policy for messages that fail processing
+	retryPolicy := retry.Policy{
+		Attempts:        getEnvAsInt(log, "KAFKA_RETRY_ATTEMPTS", 3),
+		Backoff:         getEnvAsDuration(log, "KAFKA_RETRY_BACKOFF", 200*time.Millisecond),
+		RetryDelays:     getEnvAsDurations(log, "KAFKA_RETRY_DELAYS", []time.Duration{30 * time.Second, 5 * time.Minute}),
+		DeadLetterTopic: getEnv("KAFKA_DLQ_TOPIC", ""),
+	}
 
 	// Get configuration from environment variables with defaults
 	config := kafka.ConfigMap{
@@ -29,24 +51,78 @@ func main() {
 		"group.id":           kafkaGroupId,
 		"auto.offset.reset":  "earliest",
 		"enable.auto.commit": false, // Manual commit for better control
+
+		// Retry and dead-letter topics are created by the first message forwarded to them
+		"allow.auto.create.topics": true,
 	}
 
 	log.Info("Configuration loaded:")
 	log.Info("  Kafka host: %s", kafkaHost)
 	log.Info("  Group ID: %s", kafkaGroupId)
 	log.Info("  Topics: %v", kafkaTopics)
+	log.Info("  Retry attempts: %d (backoff %s)", retryPolicy.Attempts, retryPolicy.Backoff)
+	log.Info("  Retry delays: %v", retryPolicy.RetryDelays)
+	log.Info("  DLQ topic: %s", retryPolicy.DeadLetterTopic)
+	log.Info("  Storage driver: %s", storageDriver)
+	log.Info("  Schema registry: %s", schemaRegistryURL)
+	log.Info("  Metrics address: %s", metricsAddr)
+
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "purchase-plans")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("Failed to flush traces: %v", err)
+		}
+	}()
+
+	// Initialize the purchase plan repository shared with the web API (driven adapter)
+	var planRepo driven.PurchasePlanRepository
+
+	switch storageDriver {
+	case "postgres":
+		// The web API applies the schema migrations
+		db, err := postgres.Open(context.Background(), databaseURL)
+		if err != nil {
+			log.Fatal("Failed to connect to PostgreSQL: %v", err)
+		}
+		defer db.Close()
+
+		planRepo = postgres.NewPurchasePlanRepository(db)
+	case "memory":
+		log.Warn("Using in-memory storage, purchase plans managed by the web API are not visible")
+		planRepo = memory.NewPurchasePlanRepository()
+	default:
+		log.Fatal("Unsupported storage driver: %s", storageDriver)
+	}
 
 	// Create application service (business logic layer)
 	log.Info("Initializing application service...")
-	eventService := application.NewMedicineEventService()
+	eventService := application.NewMedicineEventService(planRepo)
+
+	// Events are read in the format named by their content type header, Avro needs the registry
+	schemaRegistry, err := events.NewRegistry(schemaRegistryURL)
+	if err != nil {
+		log.Fatal("Failed to create schema registry client: %v", err)
+	}
 
 	// Create Kafka consumer adapter (infrastructure layer)
 	log.Info("Initializing Kafka consumer...")
-	consumer, err := queue.NewKafkaEventConsumer(config, []string{kafkaTopics}, eventService)
+	consumer, err := queue.NewKafkaEventConsumer(config, []string{kafkaTopics}, eventService, events.NewDecoder(schemaRegistry), retryPolicy)
 	if err != nil {
 		log.Fatal("Failed to create Kafka consumer: %v", err)
 	}
 
+	// Serve Prometheus metrics
+	go func() {
+		log.Info("Starting metrics server on %s", metricsAddr)
+		if err := metrics.ListenAndServe(metricsAddr); err != nil {
+			log.Error("Metrics server stopped: %v", err)
+		}
+	}()
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -79,7 +155,7 @@ func main() {
 		log.Error("Error stopping consumer: %v", err)
 	}
 
-	log.Info("Contracts Service stopped successfully")
+	log.Info("purchase-plans Service stopped successfully")
 }
 
 // getEnv gets an environment variable or returns a default value
@@ -89,3 +165,56 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvAsInt gets an environment variable as a positive integer or returns a default value
+func getEnvAsInt(log *logger.Logger, key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Fatal("Invalid positive integer for %s: %q", key, value)
+	}
+
+	return parsed
+}
+
+// getEnvAsDuration gets an environment variable as a duration or returns a default value
+func getEnvAsDuration(log *logger.Logger, key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatal("Invalid duration for %s: %q", key, value)
+	}
+
+	return duration
+}
+
+// getEnvAsDurations gets an environment variable as a comma-separated list of durations or
+// returns a default value. "none" yields an empty list.
+func getEnvAsDurations(log *logger.Logger, key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if value == "none" {
+		return nil
+	}
+
+	durations := make([]time.Duration, 0)
+	for _, part := range strings.Split(value, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || duration <= 0 {
+			log.Fatal("Invalid duration list for %s: %q", key, value)
+		}
+		durations = append(durations, duration)
+	}
+
+	return durations
+}
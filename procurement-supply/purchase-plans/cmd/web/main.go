@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"os"
+	"purchase-plans/internal/adapter/http"
+	"purchase-plans/internal/adapter/storage/memory"
+	"purchase-plans/internal/adapter/storage/postgres"
+	"purchase-plans/internal/core/application"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
+	"purchase-plans/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Initialize application logger
+	log := logger.New("APP")
+	log.Info("Starting purchase-plans Web API...")
+
+	storageDriver := getEnv("STORAGE_DRIVER", "memory")
+	databaseURL := os.Getenv("DATABASE_URL")
+
+	log.Info("Configuration loaded:")
+	log.Info("  Storage driver: %s", storageDriver)
+
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "purchase-plans-web")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("Failed to flush traces: %v", err)
+		}
+	}()
+
+	// Initialize the purchase plan repository (driven adapter)
+	var planRepo driven.Repository[string, domain.PurchasePlan]
+
+	switch storageDriver {
+	case "postgres":
+		db, err := postgres.Open(context.Background(), databaseURL)
+		if err != nil {
+			log.Fatal("Failed to connect to PostgreSQL: %v", err)
+		}
+		defer db.Close()
+
+		if err := postgres.Migrate(context.Background(), db); err != nil {
+			log.Fatal("Failed to apply database migrations: %v", err)
+		}
+		log.Info("PostgreSQL storage initialized")
+
+		planRepo = postgres.NewPurchasePlanRepository(db)
+	case "memory":
+		planRepo = memory.NewPurchasePlanRepository()
+	default:
+		log.Fatal("Unsupported storage driver: %s", storageDriver)
+	}
+
+	// Setup purchase plan service (core application)
+	log.Info("Initializing purchase plan service...")
+	planService := application.NewPurchasePlanService(planRepo)
+
+	// Setup purchase plan handler (driver adapter)
+	log.Info("Initializing HTTP handlers...")
+	planHandler := http.NewPurchasePlanHandler(planService)
+
+	// Setup router with request tracing and Prometheus request metrics by route template
+	router := gin.Default()
+	router.Use(tracing.GinMiddleware())
+	router.Use(metrics.GinMiddleware())
+
+	// Health check
+	router.GET("/ping", http.PongHandler)
+
+	// Prometheus metrics
+	router.GET("/metrics", metrics.Handler())
+
+	// Purchase plan routes
+	planRoutes := router.Group("/purchase-plans")
+	{
+		planRoutes.GET("", planHandler.GetPurchasePlans)
+		planRoutes.GET("/:id", planHandler.GetPurchasePlan)
+		planRoutes.POST("", planHandler.PostPurchasePlan)
+		planRoutes.PUT("/:id", planHandler.PutPurchasePlan)
+		planRoutes.DELETE("/:id", planHandler.DeletePurchasePlan)
+	}
+
+	log.Info("All components initialized successfully")
+	log.Info("Server starting on :8080")
+
+	if err = router.Run(); err != nil {
+		log.Fatal("Failed to start server: %v", err)
+	}
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
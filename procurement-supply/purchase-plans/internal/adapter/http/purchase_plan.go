@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driver"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PurchasePlanHandler is a thin HTTP adapter that delegates to the PurchasePlanService
+type PurchasePlanHandler struct {
+	service driver.PurchasePlanService
+}
+
+// NewPurchasePlanHandler creates a new purchase plan handler
+func NewPurchasePlanHandler(service driver.PurchasePlanService) *PurchasePlanHandler {
+	return &PurchasePlanHandler{
+		service: service,
+	}
+}
+
+// GetPurchasePlans returns all purchase plans
+func (h *PurchasePlanHandler) GetPurchasePlans(c *gin.Context) {
+	plans, err := h.service.RetrievePurchasePlans(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+// GetPurchasePlan returns a purchase plan by id
+func (h *PurchasePlanHandler) GetPurchasePlan(c *gin.Context) {
+	plan, err := h.service.RetrievePurchasePlan(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// PostPurchasePlan creates a new purchase plan
+func (h *PurchasePlanHandler) PostPurchasePlan(c *gin.Context) {
+	var payload domain.PurchasePlan
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	plan, err := h.service.CreatePurchasePlan(c.Request.Context(), payload)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+// PutPurchasePlan updates an existing purchase plan (full replacement)
+func (h *PurchasePlanHandler) PutPurchasePlan(c *gin.Context) {
+	id := c.Param("id")
+	var payload domain.PurchasePlan
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
+	}
+
+	if payload.ID != "" && payload.ID != id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id in path and body must match (or omit body id)"})
+		return
+	}
+	payload.ID = id
+
+	plan, err := h.service.UpdatePurchasePlan(c.Request.Context(), payload)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// DeletePurchasePlan removes a purchase plan by id
+func (h *PurchasePlanHandler) DeletePurchasePlan(c *gin.Context) {
+	if err := h.service.DeletePurchasePlan(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// statusFor maps a service error to its HTTP status code
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrPurchasePlanNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrPurchasePlanExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
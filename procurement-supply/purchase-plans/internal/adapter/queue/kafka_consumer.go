@@ -2,26 +2,38 @@ package queue
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/kafkatrace"
+	"procurement-supply/pkg/events"
+	"procurement-supply/pkg/retry"
 	"purchase-plans/internal/core/domain"
 	"purchase-plans/pkg/logger"
+	"purchase-plans/pkg/metrics"
+	"strconv"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // KafkaEventConsumer is the adapter that implements the EventConsumer driver port
 type KafkaEventConsumer struct {
 	consumer     *kafka.Consumer
+	producer     *kafka.Producer
 	eventService EventProcessor
+	decoder      *events.Decoder
 	logger       *logger.Logger
 	topics       []string
+	retries      *retry.Handler
 }
 
 // EventProcessor defines the interface for processing events
 // This allows the adapter to depend on an abstraction rather than concrete implementation
 type EventProcessor interface {
-	ProcessEvent(event *domain.Event[domain.Medicine]) error
+	ProcessEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error
 }
 
 // Config holds the Kafka consumer configuration
@@ -32,19 +44,51 @@ type Config struct {
 	AutoOffsetReset  string
 }
 
-// NewKafkaEventConsumer creates a new Kafka event consumer adapter
-func NewKafkaEventConsumer(config kafka.ConfigMap, topics []string, eventService EventProcessor) (*KafkaEventConsumer, error) {
+// NewKafkaEventConsumer creates a new Kafka event consumer adapter. Messages are decoded in the
+// format named by their content type header, and those that fail processing are handled according
+// to the retry policy, using a producer on the same brokers. The in-memory retries of the policy
+// must fit in the max.poll.interval.ms of the configuration.
+func NewKafkaEventConsumer(config kafka.ConfigMap, topics []string, eventService EventProcessor, decoder *events.Decoder, policy retry.Policy) (*KafkaEventConsumer, error) {
+	bootstrapServers, err := config.Get("bootstrap.servers", "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid bootstrap.servers: %w", err)
+	}
+
+	maxPollInterval, err := retry.MaxPollInterval(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(maxPollInterval); err != nil {
+		return nil, fmt.Errorf("invalid retry policy: %w", err)
+	}
+
 	consumer, err := kafka.NewConsumer(&config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka consumer: %w", err)
 	}
 
-	return &KafkaEventConsumer{
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers":  bootstrapServers,
+		"enable.idempotence": true,
+	})
+	if err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	k := &KafkaEventConsumer{
 		consumer:     consumer,
+		producer:     producer,
 		eventService: eventService,
+		decoder:      decoder,
 		logger:       logger.New("KAFKA-CONSUMER"),
-		topics:       topics,
-	}, nil
+		topics:       policy.Subscriptions(topics),
+		// Keep the retries in the trace of the failed attempt
+		retries: retry.NewHandler(consumer, producer, policy, kafkatrace.Inject),
+	}
+	go k.logProducerEvents()
+
+	return k, nil
 }
 
 // Start begins consuming messages from Kafka
@@ -74,6 +118,11 @@ func (k *KafkaEventConsumer) Start(ctx context.Context) error {
 
 // pollAndProcess polls for a message and processes it
 func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
+	if err := k.retries.ResumeDue(); err != nil {
+		// The partitions were most likely revoked by a rebalance, their new owner starts them unpaused
+		k.logger.Debug("Failed to resume retry partitions: %v", err)
+	}
+
 	ev := k.consumer.Poll(100)
 	if ev == nil {
 		return nil
@@ -81,7 +130,7 @@ func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
 
 	switch e := ev.(type) {
 	case *kafka.Message:
-		return k.handleMessage(e)
+		return k.handleMessage(ctx, e)
 
 	case kafka.Error:
 		k.logger.Error("Kafka error: %v (code: %v)", e, e.Code())
@@ -102,49 +151,137 @@ func (k *KafkaEventConsumer) pollAndProcess(ctx context.Context) error {
 	return nil
 }
 
-// handleMessage processes a single Kafka message
-func (k *KafkaEventConsumer) handleMessage(msg *kafka.Message) error {
+// handleMessage processes a single Kafka message within a consumer span that continues the
+// trace found in its headers, and counts the outcome. A message that still fails after the
+// in-memory attempts is forwarded to a retry or dead-letter topic before its offset is committed;
+// when that is not possible the partition is rewound so the message is not skipped.
+func (k *KafkaEventConsumer) handleMessage(ctx context.Context, msg *kafka.Message) error {
+	until, deferred, err := k.retries.Defer(msg)
+	if deferred {
+		k.logger.Debug("Retry message on %s [partition %d] at offset %d not due until %s, partition paused",
+			*msg.TopicPartition.Topic, msg.TopicPartition.Partition, msg.TopicPartition.Offset, until.Format(time.RFC3339))
+		return err
+	}
+	if err != nil {
+		k.logger.Warn("Processing retry message early: %v", err)
+	}
+
+	topic := *msg.TopicPartition.Topic
+
+	ctx, span := tracing.Tracer().Start(kafkatrace.Extract(ctx, msg), topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(msg.TopicPartition.Partition))),
+			semconv.MessagingKafkaOffset(int(msg.TopicPartition.Offset)),
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+		),
+	)
+
+	attempts, processErr := k.processMessage(ctx, msg)
+	metrics.KafkaMessageConsumed(topic, processErr)
+
+	outcome, err := k.retries.Settle(ctx, msg, attempts, processErr)
+	k.logOutcome(msg, outcome, processErr, err)
+
+	if processErr != nil {
+		tracing.EndSpan(span, processErr)
+	} else {
+		tracing.EndSpan(span, err)
+	}
+
+	return err
+}
+
+// processMessage parses a single Kafka message and processes it, retrying in memory with
+// exponential backoff. It returns the number of attempts made and the last error.
+func (k *KafkaEventConsumer) processMessage(ctx context.Context, msg *kafka.Message) (int, error) {
 	k.logger.Debug("Received message on %s [partition %d] at offset %d",
 		*msg.TopicPartition.Topic,
 		msg.TopicPartition.Partition,
 		msg.TopicPartition.Offset)
 
-	// Parse the message
+	// Parse the message, retry topics hold messages encoded for the original topic
 	var event domain.Event[domain.Medicine]
-	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		k.logger.Error("Failed to unmarshal message: %v", err)
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+	if err := k.decoder.Decode(events.ContentType(msg), retry.OriginalTopic(msg), msg.Value, &event); err != nil {
+		k.logger.Error("Failed to decode message (content type %q): %v", events.ContentType(msg), err)
+		if errors.Is(err, events.ErrSchemaUnavailable) {
+			return 1, err
+		}
+		return 1, fmt.Errorf("%w: %v", retry.ErrMalformedMessage, err)
 	}
 
-	k.logger.Info("Processing event: type=%s, medicine_id=%s, medicine_name=%s",
-		event.EventType, event.Data.ID, event.Data.Name)
+	if err := event.Validate(); err != nil {
+		return 1, fmt.Errorf("%w: %v", retry.ErrMalformedMessage, err)
+	}
 
-	// Process the event through the application service
-	if err := k.eventService.ProcessEvent(&event); err != nil {
-		k.logger.Error("Error processing event: %v", err)
-		// Depending on your error handling strategy, you might want to:
-		// - Return the error to stop processing
-		// - Continue processing other messages
-		// - Send to a dead letter queue
-		return err
+	// The events raised while processing this one share its correlation ID
+	correlationID := event.CorrelationID
+	if correlationID == "" {
+		correlationID = event.ID
 	}
+	ctx = events.WithCorrelationID(ctx, correlationID)
+
+	k.logger.Info("Processing event: id=%s, type=%s, schema_version=%d, source=%s, correlation_id=%s, medicine_id=%s, medicine_name=%s, trace_id=%s",
+		event.ID, event.EventType, event.SchemaVersion, event.Source, correlationID,
+		event.Data.ID, event.Data.Name, trace.SpanContextFromContext(ctx).TraceID())
 
-	// Commit offset after successful processing
-	if _, err := k.consumer.CommitMessage(msg); err != nil {
-		k.logger.Error("Error committing offset: %v", err)
-		return fmt.Errorf("failed to commit offset: %w", err)
+	attempts, err := k.retries.Policy().Do(ctx, func(attempt int) error {
+		// Process the event through the application service
+		err := k.eventService.ProcessEvent(ctx, &event)
+		if err != nil {
+			k.logger.Warn("Error processing event (attempt %d): %v", attempt, err)
+		}
+		return err
+	})
+	if err != nil {
+		k.logger.Error("Giving up processing event after %d attempt(s): %v", attempts, err)
 	}
 
-	k.logger.Debug("Successfully processed and committed message at offset %d",
-		msg.TopicPartition.Offset)
+	return attempts, err
+}
 
-	return nil
+// logOutcome logs and counts what became of a handled message. Rewound messages and failed commits are
+// reported through the error returned to the poll loop.
+func (k *KafkaEventConsumer) logOutcome(msg *kafka.Message, outcome retry.Outcome, processErr, settleErr error) {
+	switch outcome.Action {
+	case retry.Committed:
+		if settleErr == nil {
+			k.logger.Debug("Successfully handled and committed message at offset %d",
+				msg.TopicPartition.Offset)
+		}
+	case retry.Retried:
+		metrics.KafkaMessageRetried(outcome.OriginalTopic)
+		k.logger.Warn("Message from %s sent to retry topic %s (delay %s) after %d attempt(s): %v",
+			outcome.OriginalTopic, outcome.Destination, outcome.Delay, outcome.Attempts, processErr)
+	case retry.DeadLettered:
+		metrics.KafkaMessageDeadLettered(outcome.OriginalTopic)
+		k.logger.Error("Message from %s sent to dead-letter topic %s after %d attempt(s): %v",
+			outcome.OriginalTopic, outcome.Destination, outcome.Attempts, processErr)
+	}
+}
+
+// logProducerEvents logs the errors reported by the producer until it is closed
+func (k *KafkaEventConsumer) logProducerEvents() {
+	for e := range k.producer.Events() {
+		if err, ok := e.(kafka.Error); ok {
+			k.logger.Error("Kafka producer error: %v (code: %v)", err, err.Code())
+		}
+	}
 }
 
 // Stop gracefully stops the consumer
 func (k *KafkaEventConsumer) Stop() error {
 	k.logger.Info("Closing Kafka consumer...")
 
+	// Wait for forwarded messages still in flight
+	if remaining := k.producer.Flush(5000); remaining > 0 {
+		k.logger.Warn("Kafka producer closed with %d undelivered message(s)", remaining)
+	}
+	k.producer.Close()
+
 	if err := k.consumer.Close(); err != nil {
 		k.logger.Error("Error closing consumer: %v", err)
 		return fmt.Errorf("failed to close consumer: %w", err)
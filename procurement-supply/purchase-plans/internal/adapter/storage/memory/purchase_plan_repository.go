@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"fmt"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
+	"slices"
+	"sync"
+)
+
+// PurchasePlanRepository is an in-memory implementation of the PurchasePlanRepository port
+type PurchasePlanRepository struct {
+	mu    sync.RWMutex
+	plans map[string]domain.PurchasePlan
+}
+
+// Ensure PurchasePlanRepository implements the PurchasePlanRepository interface
+var _ driven.PurchasePlanRepository = (*PurchasePlanRepository)(nil)
+
+// NewPurchasePlanRepository creates a new in-memory purchase plan repository
+func NewPurchasePlanRepository() *PurchasePlanRepository {
+	return &PurchasePlanRepository{
+		plans: make(map[string]domain.PurchasePlan),
+	}
+}
+
+// Create adds a new purchase plan to the repository
+func (r *PurchasePlanRepository) Create(plan domain.PurchasePlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.plans[plan.ID]; exists {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanExists, plan.ID)
+	}
+
+	r.plans[plan.ID] = plan
+	return nil
+}
+
+// FindByID retrieves a purchase plan by its ID
+func (r *PurchasePlanRepository) FindByID(id string) (*domain.PurchasePlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plan, exists := r.plans[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+
+	return &plan, nil
+}
+
+// FindAll retrieves all purchase plans
+func (r *PurchasePlanRepository) FindAll() ([]domain.PurchasePlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := make([]domain.PurchasePlan, 0, len(r.plans))
+	for _, plan := range r.plans {
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// FindByMedicine retrieves the purchase plans of the medicine
+func (r *PurchasePlanRepository) FindByMedicine(medicineID string) ([]domain.PurchasePlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := make([]domain.PurchasePlan, 0)
+	for _, plan := range r.plans {
+		if plan.MedicineID == medicineID {
+			plans = append(plans, plan)
+		}
+	}
+
+	return plans, nil
+}
+
+// Update modifies an existing purchase plan
+func (r *PurchasePlanRepository) Update(plan domain.PurchasePlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.plans[plan.ID]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, plan.ID)
+	}
+
+	r.plans[plan.ID] = plan
+	return nil
+}
+
+// UpdatePricing lets update edit a copy of the purchase plan and stores its pricing, holding the
+// repository lock throughout
+func (r *PurchasePlanRepository) UpdatePricing(id string, update func(plan *domain.PurchasePlan) (bool, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.plans[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+
+	plan := stored
+	plan.Targets = slices.Clone(stored.Targets)
+
+	changed, err := update(&plan)
+	if err != nil || !changed {
+		return err
+	}
+
+	stored.UnitPrice = plan.UnitPrice
+	stored.EstimatedCost = plan.EstimatedCost
+	stored.Status = plan.Status
+	stored.PriceUpdatedAt = plan.PriceUpdatedAt
+	stored.UpdatedAt = plan.UpdatedAt
+	r.plans[id] = stored
+	return nil
+}
+
+// Delete removes a purchase plan by its ID
+func (r *PurchasePlanRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.plans[id]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+
+	delete(r.plans, id)
+	return nil
+}
+
+// Exists checks if a purchase plan with the given ID exists
+func (r *PurchasePlanRepository) Exists(id string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.plans[id]
+	return exists
+}
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"purchase-plans/internal/adapter/storage/storagetest"
+	"purchase-plans/internal/core/port/driven"
+	"testing"
+)
+
+func TestPurchasePlanRepository(t *testing.T) {
+	storagetest.RunPurchasePlanRepositoryTests(t, func(*testing.T) driven.PurchasePlanRepository {
+		return NewPurchasePlanRepository()
+	})
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS purchase_plans (
+    id             TEXT PRIMARY KEY,
+    medicine_id    TEXT             NOT NULL,
+    supplier_id    TEXT             NOT NULL,
+    targets        JSONB            NOT NULL DEFAULT '[]'::jsonb,
+    budget         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit_price     DOUBLE PRECISION NOT NULL DEFAULT 0,
+    estimated_cost DOUBLE PRECISION NOT NULL DEFAULT 0,
+    status         TEXT             NOT NULL,
+    created_at     TIMESTAMPTZ      NOT NULL,
+    updated_at     TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_plans_medicine_id ON purchase_plans (medicine_id);
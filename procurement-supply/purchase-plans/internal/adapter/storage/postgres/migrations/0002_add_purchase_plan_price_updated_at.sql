@@ -0,0 +1 @@
+ALTER TABLE purchase_plans ADD COLUMN IF NOT EXISTS price_updated_at TIMESTAMPTZ;
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
+	"time"
+)
+
+// PurchasePlanRepository is a PostgreSQL implementation of the PurchasePlanRepository port.
+// The period targets of a plan are stored as a JSONB document next to the plan row.
+type PurchasePlanRepository struct {
+	db *sql.DB
+}
+
+// Ensure PurchasePlanRepository implements the PurchasePlanRepository interface
+var _ driven.PurchasePlanRepository = (*PurchasePlanRepository)(nil)
+
+// NewPurchasePlanRepository creates a new PostgreSQL purchase plan repository
+func NewPurchasePlanRepository(db *sql.DB) *PurchasePlanRepository {
+	return &PurchasePlanRepository{
+		db: db,
+	}
+}
+
+// Create adds a new purchase plan to the repository
+func (r *PurchasePlanRepository) Create(plan domain.PurchasePlan) error {
+	targets, err := marshalTargets(plan.Targets)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(context.Background(), `
+		INSERT INTO purchase_plans (id, medicine_id, supplier_id, targets, budget, unit_price, estimated_cost, status, created_at, updated_at, price_updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO NOTHING`,
+		plan.ID, plan.MedicineID, plan.SupplierID, targets, plan.Budget, plan.UnitPrice,
+		plan.EstimatedCost, string(plan.Status), plan.CreatedAt, plan.UpdatedAt, nullTime(plan.PriceUpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert purchase plan: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanExists, plan.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a purchase plan by its ID
+func (r *PurchasePlanRepository) FindByID(id string) (*domain.PurchasePlan, error) {
+	row := r.db.QueryRowContext(context.Background(), `
+		SELECT id, medicine_id, supplier_id, targets, budget, unit_price, estimated_cost, status, created_at, updated_at, price_updated_at
+		FROM purchase_plans
+		WHERE id = $1`, id,
+	)
+
+	plan, err := scanPurchasePlan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// FindAll retrieves all purchase plans
+func (r *PurchasePlanRepository) FindAll() ([]domain.PurchasePlan, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, medicine_id, supplier_id, targets, budget, unit_price, estimated_cost, status, created_at, updated_at, price_updated_at
+		FROM purchase_plans
+		ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase plans: %w", err)
+	}
+
+	return scanPurchasePlans(rows)
+}
+
+// FindByMedicine retrieves the purchase plans of the medicine
+func (r *PurchasePlanRepository) FindByMedicine(medicineID string) ([]domain.PurchasePlan, error) {
+	rows, err := r.db.QueryContext(context.Background(), `
+		SELECT id, medicine_id, supplier_id, targets, budget, unit_price, estimated_cost, status, created_at, updated_at, price_updated_at
+		FROM purchase_plans
+		WHERE medicine_id = $1
+		ORDER BY id`, medicineID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query purchase plans: %w", err)
+	}
+
+	return scanPurchasePlans(rows)
+}
+
+// scanPurchasePlans reads every purchase plan row and closes the rows
+func scanPurchasePlans(rows *sql.Rows) ([]domain.PurchasePlan, error) {
+	defer rows.Close()
+
+	plans := make([]domain.PurchasePlan, 0)
+	for rows.Next() {
+		plan, err := scanPurchasePlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate purchase plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+// Update modifies an existing purchase plan
+func (r *PurchasePlanRepository) Update(plan domain.PurchasePlan) error {
+	targets, err := marshalTargets(plan.Targets)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(context.Background(), `
+		UPDATE purchase_plans
+		SET medicine_id = $2, supplier_id = $3, targets = $4, budget = $5, unit_price = $6,
+			estimated_cost = $7, status = $8, updated_at = $9, price_updated_at = $10
+		WHERE id = $1`,
+		plan.ID, plan.MedicineID, plan.SupplierID, targets, plan.Budget, plan.UnitPrice,
+		plan.EstimatedCost, string(plan.Status), plan.UpdatedAt, nullTime(plan.PriceUpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update purchase plan: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, plan.ID)
+	}
+
+	return nil
+}
+
+// UpdatePricing lets update edit the purchase plan and stores only its pricing, holding a lock on
+// the plan row from the moment it is read until the pricing is stored
+func (r *PurchasePlanRepository) UpdatePricing(id string, update func(plan *domain.PurchasePlan) (bool, error)) error {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin purchase plan pricing update: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	row := tx.QueryRowContext(ctx, `
+		SELECT id, medicine_id, supplier_id, targets, budget, unit_price, estimated_cost, status, created_at, updated_at, price_updated_at
+		FROM purchase_plans
+		WHERE id = $1
+		FOR UPDATE`, id,
+	)
+
+	plan, err := scanPurchasePlan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed, err := update(plan)
+	if err != nil || !changed {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE purchase_plans
+		SET unit_price = $2, estimated_cost = $3, status = $4, price_updated_at = $5, updated_at = $6
+		WHERE id = $1`,
+		id, plan.UnitPrice, plan.EstimatedCost, string(plan.Status), nullTime(plan.PriceUpdatedAt), plan.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to update purchase plan pricing: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purchase plan pricing update: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a purchase plan by its ID
+func (r *PurchasePlanRepository) Delete(id string) error {
+	result, err := r.db.ExecContext(context.Background(), `DELETE FROM purchase_plans WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete purchase plan: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrPurchasePlanNotFound, id)
+	}
+
+	return nil
+}
+
+// Exists checks if a purchase plan with the given ID exists
+func (r *PurchasePlanRepository) Exists(id string) bool {
+	var exists bool
+	err := r.db.QueryRowContext(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM purchase_plans WHERE id = $1)`, id,
+	).Scan(&exists)
+
+	return err == nil && exists
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanPurchasePlan reads a purchase plan row, decoding the JSONB target list
+func scanPurchasePlan(row rowScanner) (*domain.PurchasePlan, error) {
+	var (
+		plan           domain.PurchasePlan
+		status         string
+		targets        []byte
+		priceUpdatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&plan.ID, &plan.MedicineID, &plan.SupplierID, &targets, &plan.Budget, &plan.UnitPrice,
+		&plan.EstimatedCost, &status, &plan.CreatedAt, &plan.UpdatedAt, &priceUpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan purchase plan: %w", err)
+	}
+	plan.Status = domain.PurchasePlanStatus(status)
+	plan.PriceUpdatedAt = priceUpdatedAt.Time
+
+	if err := json.Unmarshal(targets, &plan.Targets); err != nil {
+		return nil, fmt.Errorf("failed to decode purchase plan targets: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// marshalTargets encodes the period targets of a purchase plan for the JSONB column
+func marshalTargets(targets []domain.PeriodTarget) ([]byte, error) {
+	if targets == nil {
+		targets = []domain.PeriodTarget{}
+	}
+
+	data, err := json.Marshal(targets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode purchase plan targets: %w", err)
+	}
+
+	return data, nil
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
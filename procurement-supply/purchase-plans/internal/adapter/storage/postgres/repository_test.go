@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"purchase-plans/internal/adapter/storage/storagetest"
+	"purchase-plans/internal/core/port/driven"
+	"testing"
+)
+
+// testDB is the migrated database at TEST_DATABASE_URL shared by the tests, nil when unset
+var testDB *sql.DB
+
+// TestMain runs the tests against the database at TEST_DATABASE_URL. Without it they are skipped.
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		return m.Run()
+	}
+
+	db, err := Open(context.Background(), dsn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	if err := Migrate(context.Background(), db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	testDB = db
+	return m.Run()
+}
+
+// emptyDB returns the test database with the given tables emptied
+func emptyDB(t *testing.T, tables ...string) *sql.DB {
+	t.Helper()
+
+	if testDB == nil {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	for _, table := range tables {
+		if _, err := testDB.Exec("TRUNCATE " + table); err != nil {
+			t.Fatalf("failed to empty %s: %v", table, err)
+		}
+	}
+
+	return testDB
+}
+
+func TestPurchasePlanRepository(t *testing.T) {
+	storagetest.RunPurchasePlanRepositoryTests(t, func(t *testing.T) driven.PurchasePlanRepository {
+		return NewPurchasePlanRepository(emptyDB(t, "purchase_plans"))
+	})
+}
@@ -0,0 +1,348 @@
+// Package storagetest holds the test suite every driven.PurchasePlanRepository adapter must pass
+package storagetest
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// NewPlan returns a purchase plan of the medicine with the given ID and every field set
+func NewPlan(id, medicineID string) domain.PurchasePlan {
+	return domain.PurchasePlan{
+		ID:         id,
+		MedicineID: medicineID,
+		SupplierID: "supplier-1",
+		Targets: []domain.PeriodTarget{
+			{Period: "2025-01", Quantity: 10},
+			{Period: "2025-02", Quantity: 20},
+		},
+		Budget:         1000,
+		UnitPrice:      12.5,
+		EstimatedCost:  375,
+		Status:         domain.PurchasePlanActive,
+		CreatedAt:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		PriceUpdatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// modifyPlan returns the plan with every field but the ID changed
+func modifyPlan(plan domain.PurchasePlan) domain.PurchasePlan {
+	plan.MedicineID += "-replaced"
+	plan.SupplierID = "supplier-2"
+	plan.Targets = []domain.PeriodTarget{{Period: "2025-03", Quantity: 5}}
+	plan.Budget = 10
+	plan.UnitPrice = 3
+	plan.EstimatedCost = 15
+	plan.Status = domain.PurchasePlanOverBudget
+	plan.CreatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	plan.UpdatedAt = time.Date(2025, 2, 3, 4, 5, 6, 0, time.UTC)
+	plan.PriceUpdatedAt = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	return plan
+}
+
+// RunPurchasePlanRepositoryTests runs the repository test suite. newRepository must return an
+// empty repository on every call.
+func RunPurchasePlanRepositoryTests(t *testing.T, newRepository func(t *testing.T) driven.PurchasePlanRepository) {
+	t.Run("create and find", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if !repo.Exists("plan-1") {
+			t.Error("Exists() = false after Create()")
+		}
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, plan)
+	})
+
+	t.Run("plan without price update time", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+		plan.PriceUpdatedAt = time.Time{}
+
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if !found.PriceUpdatedAt.IsZero() {
+			t.Errorf("PriceUpdatedAt = %s, want zero", found.PriceUpdatedAt)
+		}
+	})
+
+	t.Run("create duplicate", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.Create(modifyPlan(plan)); !errors.Is(err, domain.ErrPurchasePlanExists) {
+			t.Fatalf("Create() of an existing ID error = %v, want %v", err, domain.ErrPurchasePlanExists)
+		}
+
+		// The first plan is kept
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, plan)
+	})
+
+	t.Run("missing plan", func(t *testing.T) {
+		repo := newRepository(t)
+
+		if repo.Exists("missing") {
+			t.Error("Exists() = true for a missing ID")
+		}
+		if _, err := repo.FindByID("missing"); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			t.Errorf("FindByID() of a missing ID error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+		}
+		if err := repo.Update(NewPlan("missing", "med-1")); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			t.Errorf("Update() of a missing ID error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+		}
+		if err := repo.Delete("missing"); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			t.Errorf("Delete() of a missing ID error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+		}
+		err := repo.UpdatePricing("missing", func(*domain.PurchasePlan) (bool, error) {
+			t.Error("update called for a missing plan")
+			return false, nil
+		})
+		if !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			t.Errorf("UpdatePricing() of a missing ID error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+		}
+		if repo.Exists("missing") {
+			t.Error("Update() of a missing ID created it")
+		}
+	})
+
+	t.Run("find all and by medicine", func(t *testing.T) {
+		repo := newRepository(t)
+
+		all, err := repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(all) != 0 {
+			t.Fatalf("FindAll() on an empty repository returned %d plans", len(all))
+		}
+
+		plans := []domain.PurchasePlan{NewPlan("plan-1", "med-1"), NewPlan("plan-2", "med-2"), NewPlan("plan-3", "med-1")}
+		for _, plan := range plans {
+			if err := repo.Create(plan); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		all, err = repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		assertPlans(t, all, plans)
+
+		for medicineID, want := range map[string][]domain.PurchasePlan{
+			"med-1":   {plans[0], plans[2]},
+			"med-2":   {plans[1]},
+			"missing": {},
+		} {
+			found, err := repo.FindByMedicine(medicineID)
+			if err != nil {
+				t.Fatalf("FindByMedicine(%s) error = %v", medicineID, err)
+			}
+			assertPlans(t, found, want)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+		other := NewPlan("plan-2", "med-1")
+
+		for _, p := range []domain.PurchasePlan{plan, other} {
+			if err := repo.Create(p); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		updated := modifyPlan(plan)
+		if err := repo.Update(updated); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, updated)
+
+		// Other plans are left untouched
+		found, err = repo.FindByID("plan-2")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, other)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		repo := newRepository(t)
+
+		for _, id := range []string{"plan-1", "plan-2"} {
+			if err := repo.Create(NewPlan(id, "med-1")); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		if err := repo.Delete("plan-1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if repo.Exists("plan-1") {
+			t.Error("Exists() = true after Delete()")
+		}
+		if err := repo.Delete("plan-1"); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			t.Errorf("second Delete() error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+		}
+		if !repo.Exists("plan-2") {
+			t.Error("Delete() removed another plan")
+		}
+	})
+
+	t.Run("update pricing stores only the pricing", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		modified := modifyPlan(plan)
+		err := repo.UpdatePricing("plan-1", func(stored *domain.PurchasePlan) (bool, error) {
+			assertEqual(t, *stored, plan)
+
+			// The stored targets are unchanged until the update is stored
+			stored.Targets[0].Quantity = 99
+			*stored = modified
+			return true, nil
+		})
+		if err != nil {
+			t.Fatalf("UpdatePricing() error = %v", err)
+		}
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		want := plan
+		want.UnitPrice = modified.UnitPrice
+		want.EstimatedCost = modified.EstimatedCost
+		want.Status = modified.Status
+		want.PriceUpdatedAt = modified.PriceUpdatedAt
+		want.UpdatedAt = modified.UpdatedAt
+		assertEqual(t, *found, want)
+	})
+
+	t.Run("update pricing without changes", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		errUpdate := errors.New("update failed")
+		for _, result := range []error{nil, errUpdate} {
+			err := repo.UpdatePricing("plan-1", func(stored *domain.PurchasePlan) (bool, error) {
+				stored.UnitPrice = 1
+				return result != nil, result
+			})
+			if !errors.Is(err, result) {
+				t.Errorf("UpdatePricing() error = %v, want %v", err, result)
+			}
+		}
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertEqual(t, *found, plan)
+	})
+
+	t.Run("concurrent pricing updates", func(t *testing.T) {
+		repo := newRepository(t)
+		plan := NewPlan("plan-1", "med-1")
+		plan.UnitPrice = 0
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		// Every update raises the price by one, none may be lost
+		const updates = 8
+		var wg sync.WaitGroup
+		for range updates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				err := repo.UpdatePricing("plan-1", func(stored *domain.PurchasePlan) (bool, error) {
+					stored.UnitPrice++
+					return true, nil
+				})
+				if err != nil {
+					t.Errorf("UpdatePricing() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		found, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found.UnitPrice != updates {
+			t.Errorf("unit price is %v after %d concurrent raises", found.UnitPrice, updates)
+		}
+	})
+}
+
+// assertPlans compares the plans regardless of their order, which is up to the adapter
+func assertPlans(t *testing.T, got, want []domain.PurchasePlan) {
+	t.Helper()
+
+	byID := func(a, b domain.PurchasePlan) int { return cmp.Compare(a.ID, b.ID) }
+	got = slices.SortedFunc(slices.Values(got), byID)
+	want = slices.SortedFunc(slices.Values(want), byID)
+	assertEqual(t, got, want)
+}
+
+// assertEqual compares plans through their JSON form, which is what the API exposes and ignores
+// representation details such as the location or the monotonic clock reading of a time
+func assertEqual[T any](t *testing.T, got, want T) {
+	t.Helper()
+
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", got, err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("failed to encode %T: %v", want, err)
+	}
+
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
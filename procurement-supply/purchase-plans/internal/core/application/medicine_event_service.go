@@ -1,57 +1,172 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
 	"purchase-plans/pkg/logger"
+	"time"
 )
 
-// MedicineEventService handles the business logic for medicine events
+// MedicineEventService keeps the purchase plans in line with the medicine events published by
+// the suppliers service
 type MedicineEventService struct {
-	logger *logger.Logger
+	repository driven.PurchasePlanRepository
+	logger     *logger.Logger
 }
 
+// Ensure MedicineEventService implements the driven.MedicineEventHandler interface
+var _ driven.MedicineEventHandler = (*MedicineEventService)(nil)
+
 // NewMedicineEventService creates a new medicine event service
-func NewMedicineEventService() *MedicineEventService {
+func NewMedicineEventService(repository driven.PurchasePlanRepository) *MedicineEventService {
 	return &MedicineEventService{
-		logger: logger.New("EVENT-SERVICE"),
+		repository: repository,
+		logger:     logger.New("EVENT-SERVICE"),
 	}
 }
 
-// HandleMedicineUpdated processes medicine update events
-func (s *MedicineEventService) HandleMedicineUpdated(event *domain.Event[domain.Medicine]) error {
-	s.logger.Info("Processing UPDATED event for medicine: %s (ID: %s)",
-		event.Data.Name, event.Data.ID)
+// HandleMedicineCreated suggests a draft purchase plan for a new medicine, bought from its supplier
+// at its catalog price. Medicines that already have a plan get no suggestion.
+func (s *MedicineEventService) HandleMedicineCreated(_ context.Context, event *domain.Event[domain.Medicine]) error {
+	s.logger.Info("Processing CREATED event for medicine: %s (ID: %s, price: %.2f)",
+		event.Data.Name, event.Data.ID, event.Data.Price)
+
+	plans, err := s.repository.FindByMedicine(event.Data.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve purchase plans of medicine %s: %w", event.Data.ID, err)
+	}
+	if len(plans) > 0 {
+		s.logger.Info("Medicine %s already has %d purchase plan(s), no draft suggested", event.Data.ID, len(plans))
+		return nil
+	}
 
-	// Update the existing contract with new medicine data
-	s.logger.Info("Finding existing purchase plans with medicine ID: %s", event.Data.ID)
+	now := time.Now().UTC()
+	draft := domain.PurchasePlan{
+		// Derived from the medicine, so a redelivered event finds the draft it already created
+		ID:             "draft-" + event.Data.ID,
+		MedicineID:     event.Data.ID,
+		SupplierID:     event.Data.SupplierID,
+		Targets:        []domain.PeriodTarget{},
+		UnitPrice:      max(event.Data.Price, 0),
+		Status:         domain.PurchasePlanDraft,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		PriceUpdatedAt: event.Data.UpdatedAt,
+	}
+	draft.Recalculate()
+
+	if err := s.repository.Create(draft); err != nil {
+		if errors.Is(err, domain.ErrPurchasePlanExists) {
+			return nil
+		}
+		return fmt.Errorf("failed to create draft purchase plan %s: %w", draft.ID, err)
+	}
 
-	s.logger.Info("Successfully updated purchase plans for medicine: %s", event.Data)
+	s.logger.Info("Draft purchase plan %s suggested for medicine: %s", draft.ID, event.Data.ID)
 	return nil
 }
 
-// HandleMedicineDeleted processes medicine deletion events
-func (s *MedicineEventService) HandleMedicineDeleted(event *domain.Event[domain.Medicine]) error {
-	s.logger.Info("Processing DELETED event for medicine ID: %s", event.Data.ID)
+// HandleMedicineUpdated recomputes the cost of the purchase plans of the medicine with its new price.
+// Plans whose price comes from a later version of the medicine, as when a retried event arrives
+// after a later change, are left alone.
+func (s *MedicineEventService) HandleMedicineUpdated(_ context.Context, event *domain.Event[domain.Medicine]) error {
+	s.logger.Info("Processing UPDATED event for medicine: %s (ID: %s, price: %.2f)",
+		event.Data.Name, event.Data.ID, event.Data.Price)
 
-	// Find existing contract for the medicine
-	s.logger.Info("Finding existing purchase plans with medicine ID: %s", event.Data.ID)
+	// Without a price there is nothing to recompute
+	if event.Data.Price <= 0 {
+		return nil
+	}
 
-	// Delete medicines related to the contract
-	s.logger.Info("Deleting medicines related to purchase plans: %s", event.Data.ID)
+	return s.updatePlans(event.Data.ID, func(plan *domain.PurchasePlan) bool {
+		if event.Data.UpdatedAt.Before(plan.PriceUpdatedAt) {
+			s.logger.Info("Ignoring outdated price for purchase plan %s: updated_at=%s, price_updated_at=%s",
+				plan.ID, event.Data.UpdatedAt, plan.PriceUpdatedAt)
+			return false
+		}
 
-	return nil
+		if plan.UnitPrice == event.Data.Price {
+			return false
+		}
+
+		plan.UnitPrice = event.Data.Price
+		plan.PriceUpdatedAt = event.Data.UpdatedAt
+		plan.Recalculate()
+		return true
+	})
 }
 
-// ProcessEvent routes events to the appropriate handler based on event type
-func (s *MedicineEventService) ProcessEvent(event *domain.Event[domain.Medicine]) error {
+// HandleMedicineDeleted flags the purchase plans of the medicine as no longer available
+func (s *MedicineEventService) HandleMedicineDeleted(_ context.Context, event *domain.Event[domain.Medicine]) error {
+	s.logger.Info("Processing DELETED event for medicine ID: %s", event.Data.ID)
+
+	return s.updatePlans(event.Data.ID, func(plan *domain.PurchasePlan) bool {
+		if plan.Status == domain.PurchasePlanMedicineUnavailable {
+			return false
+		}
+
+		plan.Status = domain.PurchasePlanMedicineUnavailable
+		return true
+	})
+}
+
+// ProcessEvent routes events to the appropriate handler based on event type. Event types this
+// service does not handle are skipped, so they are committed instead of retried.
+func (s *MedicineEventService) ProcessEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error {
 	switch event.EventType {
+	case domain.MedicineCreatedEvent:
+		return s.HandleMedicineCreated(ctx, event)
 	case domain.MedicineUpdatedEvent:
-		return s.HandleMedicineUpdated(event)
+		return s.HandleMedicineUpdated(ctx, event)
 	case domain.MedicineDeletedEvent:
-		return s.HandleMedicineDeleted(event)
+		return s.HandleMedicineDeleted(ctx, event)
 	default:
-		s.logger.Warn("Unknown event type: %s", event.EventType)
-		return fmt.Errorf("unknown event type: %s", event.EventType)
+		s.logger.Warn("Skipping unknown event type: %s (event_id: %s)", event.EventType, event.ID)
+		return nil
+	}
+}
+
+// updatePlans applies change to the purchase plans of the medicine and stores the pricing of the
+// ones it changed. Each plan is changed through UpdatePricing, so concurrent changes to its targets
+// or budget are not overwritten and change never works on a stale plan. Plans that already reflect
+// the event are left untouched, so redelivered events are harmless.
+func (s *MedicineEventService) updatePlans(medicineID string, change func(plan *domain.PurchasePlan) bool) error {
+	plans, err := s.repository.FindByMedicine(medicineID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve purchase plans of medicine %s: %w", medicineID, err)
 	}
+
+	updated := 0
+	for _, candidate := range plans {
+		var plan domain.PurchasePlan
+		err := s.repository.UpdatePricing(candidate.ID, func(stored *domain.PurchasePlan) (bool, error) {
+			if !change(stored) {
+				return false, nil
+			}
+
+			stored.UpdatedAt = time.Now().UTC()
+			plan = *stored
+			return true, nil
+		})
+		if errors.Is(err, domain.ErrPurchasePlanNotFound) {
+			// Plans deleted since they were listed have nothing left to update
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update purchase plan %s: %w", candidate.ID, err)
+		}
+		if plan.ID == "" {
+			continue
+		}
+
+		updated++
+		s.logger.Info("Purchase plan %s updated: estimated_cost=%.2f, status=%s",
+			plan.ID, plan.EstimatedCost, plan.Status)
+	}
+
+	s.logger.Info("Successfully updated %d purchase plan(s) for medicine: %s", updated, medicineID)
+	return nil
 }
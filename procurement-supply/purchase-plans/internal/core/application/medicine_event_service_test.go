@@ -0,0 +1,228 @@
+package application
+
+import (
+	"context"
+	"procurement-supply/pkg/events"
+	"purchase-plans/internal/adapter/storage/memory"
+	"purchase-plans/internal/core/domain"
+	"testing"
+	"time"
+)
+
+// catalogTime is the catalog update time of the medicine the stored plans were priced from
+var catalogTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// medicineEvent returns an event of the medicine med-1 with the given price, updated minutes after catalogTime
+func medicineEvent(eventType domain.EventType, price float64, minutes int) *domain.Event[domain.Medicine] {
+	return events.New(eventType, "suppliers", domain.Medicine{
+		ID:         "med-1",
+		Name:       "Ibuprofen",
+		Price:      price,
+		SupplierID: "supplier-1",
+		UpdatedAt:  catalogTime.Add(time.Duration(minutes) * time.Minute),
+	})
+}
+
+// newPlanRepository returns a repository with an active plan of med-1 and one of med-2
+func newPlanRepository(t *testing.T) *memory.PurchasePlanRepository {
+	t.Helper()
+
+	repo := memory.NewPurchasePlanRepository()
+	for _, plan := range []domain.PurchasePlan{
+		{ID: "plan-1", MedicineID: "med-1", SupplierID: "supplier-1", Budget: 100, UnitPrice: 5, PriceUpdatedAt: catalogTime},
+		{ID: "plan-2", MedicineID: "med-2", SupplierID: "supplier-1", Budget: 100, UnitPrice: 5, PriceUpdatedAt: catalogTime},
+	} {
+		plan.Targets = []domain.PeriodTarget{{Period: "2026-01", Quantity: 10}}
+		plan.Status = domain.PurchasePlanActive
+		plan.Recalculate()
+		if err := repo.Create(plan); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	return repo
+}
+
+func TestMedicineEventServiceUpdatesPlans(t *testing.T) {
+	tests := []struct {
+		name       string
+		events     []*domain.Event[domain.Medicine]
+		wantPrice  float64
+		wantCost   float64
+		wantStatus domain.PurchasePlanStatus
+		wantAt     int // minutes after catalogTime of the price kept
+	}{
+		{
+			name:       "new price within budget",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent(domain.MedicineUpdatedEvent, 8, 1)},
+			wantPrice:  8,
+			wantCost:   80,
+			wantStatus: domain.PurchasePlanActive,
+			wantAt:     1,
+		},
+		{
+			name:       "new price over budget",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent(domain.MedicineUpdatedEvent, 12, 1)},
+			wantPrice:  12,
+			wantCost:   120,
+			wantStatus: domain.PurchasePlanOverBudget,
+			wantAt:     1,
+		},
+		{
+			name: "outdated price",
+			events: []*domain.Event[domain.Medicine]{
+				medicineEvent(domain.MedicineUpdatedEvent, 12, 2),
+				medicineEvent(domain.MedicineUpdatedEvent, 8, 1),
+			},
+			wantPrice:  12,
+			wantCost:   120,
+			wantStatus: domain.PurchasePlanOverBudget,
+			wantAt:     2,
+		},
+		{
+			name:       "price older than the plan",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent(domain.MedicineUpdatedEvent, 8, -1)},
+			wantPrice:  5,
+			wantCost:   50,
+			wantStatus: domain.PurchasePlanActive,
+		},
+		{
+			name:       "update without a price",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent(domain.MedicineUpdatedEvent, 0, 1)},
+			wantPrice:  5,
+			wantCost:   50,
+			wantStatus: domain.PurchasePlanActive,
+		},
+		{
+			name:       "deleted medicine",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent(domain.MedicineDeletedEvent, 5, 1)},
+			wantPrice:  5,
+			wantCost:   50,
+			wantStatus: domain.PurchasePlanMedicineUnavailable,
+		},
+		{
+			name: "price of a deleted medicine",
+			events: []*domain.Event[domain.Medicine]{
+				medicineEvent(domain.MedicineDeletedEvent, 5, 1),
+				medicineEvent(domain.MedicineUpdatedEvent, 12, 2),
+			},
+			wantPrice:  12,
+			wantCost:   120,
+			wantStatus: domain.PurchasePlanMedicineUnavailable,
+			wantAt:     2,
+		},
+		{
+			name:       "unknown event type",
+			events:     []*domain.Event[domain.Medicine]{medicineEvent("medicine.archived", 12, 1)},
+			wantPrice:  5,
+			wantCost:   50,
+			wantStatus: domain.PurchasePlanActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newPlanRepository(t)
+			service := NewMedicineEventService(repo)
+
+			for _, event := range tt.events {
+				if err := service.ProcessEvent(context.Background(), event); err != nil {
+					t.Fatalf("ProcessEvent(%s) error = %v", event.EventType, err)
+				}
+			}
+
+			plan, err := repo.FindByID("plan-1")
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if plan.UnitPrice != tt.wantPrice || plan.EstimatedCost != tt.wantCost || plan.Status != tt.wantStatus {
+				t.Errorf("plan = price %v, cost %v, status %s, want %v, %v, %s",
+					plan.UnitPrice, plan.EstimatedCost, plan.Status, tt.wantPrice, tt.wantCost, tt.wantStatus)
+			}
+			if wantAt := catalogTime.Add(time.Duration(tt.wantAt) * time.Minute); !plan.PriceUpdatedAt.Equal(wantAt) {
+				t.Errorf("price updated at %s, want %s", plan.PriceUpdatedAt, wantAt)
+			}
+			if len(plan.Targets) != 1 || plan.Budget != 100 {
+				t.Errorf("plan targets %v and budget %v changed", plan.Targets, plan.Budget)
+			}
+
+			other, err := repo.FindByID("plan-2")
+			if err != nil {
+				t.Fatalf("FindByID() error = %v", err)
+			}
+			if other.UnitPrice != 5 || other.Status != domain.PurchasePlanActive {
+				t.Errorf("plan of another medicine = %+v, want it untouched", other)
+			}
+		})
+	}
+}
+
+func TestMedicineEventServiceRedeliveryLeavesPlansAlone(t *testing.T) {
+	repo := newPlanRepository(t)
+	service := NewMedicineEventService(repo)
+
+	for _, event := range []*domain.Event[domain.Medicine]{
+		medicineEvent(domain.MedicineUpdatedEvent, 8, 1),
+		medicineEvent(domain.MedicineDeletedEvent, 8, 1),
+	} {
+		if err := service.ProcessEvent(context.Background(), event); err != nil {
+			t.Fatalf("ProcessEvent(%s) error = %v", event.EventType, err)
+		}
+
+		first, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+
+		if err := service.ProcessEvent(context.Background(), event); err != nil {
+			t.Fatalf("ProcessEvent(%s) redelivered error = %v", event.EventType, err)
+		}
+
+		again, err := repo.FindByID("plan-1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if !again.UpdatedAt.Equal(first.UpdatedAt) || again.Status != first.Status {
+			t.Errorf("redelivered %s changed the plan from %+v to %+v", event.EventType, first, again)
+		}
+	}
+}
+
+func TestMedicineEventServiceSuggestsDraft(t *testing.T) {
+	repo := memory.NewPurchasePlanRepository()
+	service := NewMedicineEventService(repo)
+
+	created := medicineEvent(domain.MedicineCreatedEvent, 4, 0)
+	for range 2 {
+		if err := service.ProcessEvent(context.Background(), created); err != nil {
+			t.Fatalf("ProcessEvent() error = %v", err)
+		}
+	}
+
+	plans, err := repo.FindByMedicine("med-1")
+	if err != nil {
+		t.Fatalf("FindByMedicine() error = %v", err)
+	}
+	if len(plans) != 1 {
+		t.Fatalf("medicine has %d plans after a redelivered creation, want 1", len(plans))
+	}
+
+	draft := plans[0]
+	if draft.ID != "draft-med-1" || draft.SupplierID != "supplier-1" || draft.UnitPrice != 4 ||
+		draft.Status != domain.PurchasePlanDraft || !draft.PriceUpdatedAt.Equal(catalogTime) {
+		t.Errorf("draft = %+v", draft)
+	}
+}
+
+func TestMedicineEventServiceNoDraftForPlannedMedicine(t *testing.T) {
+	repo := newPlanRepository(t)
+	service := NewMedicineEventService(repo)
+
+	if err := service.ProcessEvent(context.Background(), medicineEvent(domain.MedicineCreatedEvent, 4, 0)); err != nil {
+		t.Fatalf("ProcessEvent() error = %v", err)
+	}
+
+	if repo.Exists("draft-med-1") {
+		t.Error("draft suggested for a medicine that already has a plan")
+	}
+}
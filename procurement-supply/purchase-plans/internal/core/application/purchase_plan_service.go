@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"purchase-plans/internal/core/domain"
+	"purchase-plans/internal/core/port/driven"
+	"purchase-plans/internal/core/port/driver"
+	"purchase-plans/pkg/logger"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PurchasePlanService handles business logic for purchase plan management
+type PurchasePlanService struct {
+	repository driven.Repository[string, domain.PurchasePlan]
+	logger     *logger.Logger
+	idSeq      int64
+	mu         sync.Mutex
+}
+
+// Ensure PurchasePlanService implements the driver.PurchasePlanService interface
+var _ driver.PurchasePlanService = (*PurchasePlanService)(nil)
+
+// NewPurchasePlanService creates a new purchase plan service
+func NewPurchasePlanService(repository driven.Repository[string, domain.PurchasePlan]) *PurchasePlanService {
+	return &PurchasePlanService{
+		repository: repository,
+		logger:     logger.New("PURCHASE-PLAN-SERVICE"),
+		idSeq:      time.Now().UnixNano(),
+	}
+}
+
+// CreatePurchasePlan creates a new purchase plan, estimating its cost from the unit price
+func (s *PurchasePlanService) CreatePurchasePlan(_ context.Context, plan domain.PurchasePlan) (*domain.PurchasePlan, error) {
+	// Generate ID if empty
+	if plan.ID == "" {
+		s.mu.Lock()
+		s.idSeq++
+		plan.ID = strconv.FormatInt(s.idSeq, 10)
+		s.mu.Unlock()
+	}
+
+	now := time.Now().UTC()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+	plan.Status = domain.PurchasePlanActive
+	plan.Recalculate()
+
+	if err := s.repository.Create(plan); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Purchase plan created: id=%s, medicine_id=%s, estimated_cost=%.2f, status=%s",
+		plan.ID, plan.MedicineID, plan.EstimatedCost, plan.Status)
+
+	return &plan, nil
+}
+
+// RetrievePurchasePlan retrieves a purchase plan by ID
+func (s *PurchasePlanService) RetrievePurchasePlan(_ context.Context, id string) (*domain.PurchasePlan, error) {
+	return s.repository.FindByID(id)
+}
+
+// RetrievePurchasePlans retrieves all purchase plans
+func (s *PurchasePlanService) RetrievePurchasePlans(_ context.Context) ([]domain.PurchasePlan, error) {
+	return s.repository.FindAll()
+}
+
+// UpdatePurchasePlan replaces a purchase plan and recalculates its cost. The creation date, the
+// catalog time of the price and the unavailability of the medicine are kept from the stored plan,
+// and drafts are confirmed.
+func (s *PurchasePlanService) UpdatePurchasePlan(_ context.Context, plan domain.PurchasePlan) (*domain.PurchasePlan, error) {
+	stored, err := s.repository.FindByID(plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.CreatedAt = stored.CreatedAt
+	plan.PriceUpdatedAt = stored.PriceUpdatedAt
+	plan.UpdatedAt = time.Now().UTC()
+	plan.Status = domain.PurchasePlanActive
+	if stored.Status == domain.PurchasePlanMedicineUnavailable && stored.MedicineID == plan.MedicineID {
+		plan.Status = domain.PurchasePlanMedicineUnavailable
+	}
+	plan.Recalculate()
+
+	if err := s.repository.Update(plan); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Purchase plan updated: id=%s, estimated_cost=%.2f, status=%s",
+		plan.ID, plan.EstimatedCost, plan.Status)
+
+	return &plan, nil
+}
+
+// DeletePurchasePlan removes a purchase plan
+func (s *PurchasePlanService) DeletePurchasePlan(_ context.Context, id string) error {
+	if err := s.repository.Delete(id); err != nil {
+		return err
+	}
+
+	s.logger.Info("Purchase plan deleted: id=%s", id)
+	return nil
+}
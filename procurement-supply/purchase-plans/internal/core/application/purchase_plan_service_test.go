@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"purchase-plans/internal/adapter/storage/memory"
+	"purchase-plans/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func newTestPlan() domain.PurchasePlan {
+	return domain.PurchasePlan{
+		MedicineID: "med-1",
+		SupplierID: "supplier-1",
+		Targets:    []domain.PeriodTarget{{Period: "2026-01", Quantity: 10}},
+		Budget:     100,
+		UnitPrice:  12,
+	}
+}
+
+func TestPurchasePlanServiceCreate(t *testing.T) {
+	service := NewPurchasePlanService(memory.NewPurchasePlanRepository())
+
+	plan := newTestPlan()
+	plan.Status = domain.PurchasePlanDraft
+	created, err := service.CreatePurchasePlan(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("CreatePurchasePlan() error = %v", err)
+	}
+
+	// Plans created through the API are never drafts, and their status follows the budget
+	if created.ID == "" || created.EstimatedCost != 120 || created.Status != domain.PurchasePlanOverBudget {
+		t.Errorf("created plan = %+v", created)
+	}
+	if created.CreatedAt.IsZero() || !created.UpdatedAt.Equal(created.CreatedAt) {
+		t.Errorf("created at %s, updated at %s", created.CreatedAt, created.UpdatedAt)
+	}
+
+	other, err := service.CreatePurchasePlan(context.Background(), newTestPlan())
+	if err != nil {
+		t.Fatalf("CreatePurchasePlan() error = %v", err)
+	}
+	if other.ID == created.ID {
+		t.Errorf("two plans created with ID %s", created.ID)
+	}
+
+	duplicate := newTestPlan()
+	duplicate.ID = created.ID
+	if _, err := service.CreatePurchasePlan(context.Background(), duplicate); !errors.Is(err, domain.ErrPurchasePlanExists) {
+		t.Errorf("CreatePurchasePlan() with a used ID error = %v, want %v", err, domain.ErrPurchasePlanExists)
+	}
+}
+
+func TestPurchasePlanServiceUpdate(t *testing.T) {
+	catalogTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		stored     domain.PurchasePlanStatus
+		medicineID string
+		wantStatus domain.PurchasePlanStatus
+	}{
+		{"draft confirmed", domain.PurchasePlanDraft, "med-1", domain.PurchasePlanActive},
+		{"over budget within budget again", domain.PurchasePlanOverBudget, "med-1", domain.PurchasePlanActive},
+		{"unavailable medicine kept", domain.PurchasePlanMedicineUnavailable, "med-1", domain.PurchasePlanMedicineUnavailable},
+		{"unavailable medicine replaced", domain.PurchasePlanMedicineUnavailable, "med-2", domain.PurchasePlanActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewPurchasePlanRepository()
+			service := NewPurchasePlanService(repo)
+
+			stored := newTestPlan()
+			stored.ID = "plan-1"
+			stored.Status = tt.stored
+			stored.CreatedAt = createdAt
+			stored.PriceUpdatedAt = catalogTime
+			if err := repo.Create(stored); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			plan := newTestPlan()
+			plan.ID = "plan-1"
+			plan.MedicineID = tt.medicineID
+			plan.UnitPrice = 8
+			updated, err := service.UpdatePurchasePlan(context.Background(), plan)
+			if err != nil {
+				t.Fatalf("UpdatePurchasePlan() error = %v", err)
+			}
+
+			if updated.Status != tt.wantStatus || updated.EstimatedCost != 80 {
+				t.Errorf("updated plan status %s, cost %v, want %s, 80", updated.Status, updated.EstimatedCost, tt.wantStatus)
+			}
+			if !updated.CreatedAt.Equal(createdAt) || !updated.PriceUpdatedAt.Equal(catalogTime) {
+				t.Errorf("updated plan created at %s, price updated at %s, want the stored ones", updated.CreatedAt, updated.PriceUpdatedAt)
+			}
+		})
+	}
+}
+
+func TestPurchasePlanServiceUpdateMissing(t *testing.T) {
+	service := NewPurchasePlanService(memory.NewPurchasePlanRepository())
+
+	plan := newTestPlan()
+	plan.ID = "missing"
+	if _, err := service.UpdatePurchasePlan(context.Background(), plan); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+		t.Errorf("UpdatePurchasePlan() of a missing plan error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+	}
+	if err := service.DeletePurchasePlan(context.Background(), "missing"); !errors.Is(err, domain.ErrPurchasePlanNotFound) {
+		t.Errorf("DeletePurchasePlan() of a missing plan error = %v, want %v", err, domain.ErrPurchasePlanNotFound)
+	}
+}
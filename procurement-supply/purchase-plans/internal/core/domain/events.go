@@ -1,18 +1,16 @@
 package domain
 
 import (
-	"time"
+	"procurement-supply/pkg/events"
 )
 
-type EventType string
+type EventType = events.Type
 
-type Event[T any] struct {
-	EventType EventType `json:"event_type"`
-	Data      T         `json:"data"`
-	Timestamp time.Time `json:"timestamp"`
-}
+// Event is a domain event wrapped in the envelope shared with the other services
+type Event[T any] = events.Envelope[T]
 
 const (
+	MedicineCreatedEvent EventType = "medicine.created"
 	MedicineUpdatedEvent EventType = "medicine.updated"
 	MedicineDeletedEvent EventType = "medicine.deleted"
 )
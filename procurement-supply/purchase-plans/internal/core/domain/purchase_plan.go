@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPurchasePlanNotFound is returned when no purchase plan has the requested ID
+	ErrPurchasePlanNotFound = errors.New("purchase plan not found")
+
+	// ErrPurchasePlanExists is returned when creating a purchase plan with an ID already in use
+	ErrPurchasePlanExists = errors.New("purchase plan already exists")
+)
+
+type PurchasePlanStatus string
+
+const (
+	PurchasePlanDraft               PurchasePlanStatus = "draft" // suggested for a new medicine, not yet confirmed
+	PurchasePlanActive              PurchasePlanStatus = "active"
+	PurchasePlanOverBudget          PurchasePlanStatus = "over_budget"
+	PurchasePlanMedicineUnavailable PurchasePlanStatus = "medicine_unavailable"
+)
+
+// PeriodTarget is the quantity of the medicine to buy in a period, such as a month ("2025-01")
+type PeriodTarget struct {
+	Period   string `json:"period" binding:"required"`
+	Quantity int    `json:"quantity" binding:"gt=0"`
+}
+
+// PurchasePlan schedules the purchase of a medicine from a supplier over several periods within a budget
+type PurchasePlan struct {
+	ID             string             `json:"id"`
+	MedicineID     string             `json:"medicine_id" binding:"required"`
+	SupplierID     string             `json:"supplier_id" binding:"required"`
+	Targets        []PeriodTarget     `json:"targets" binding:"required,min=1,dive"`
+	Budget         float64            `json:"budget" binding:"gte=0"`
+	UnitPrice      float64            `json:"unit_price" binding:"gte=0"` // latest price known for the medicine
+	EstimatedCost  float64            `json:"estimated_cost"`
+	Status         PurchasePlanStatus `json:"status"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	PriceUpdatedAt time.Time          `json:"price_updated_at,omitzero"` // catalog update time of the medicine the unit price comes from
+}
+
+// TotalQuantity returns the quantity to buy across all periods
+func (p PurchasePlan) TotalQuantity() int {
+	total := 0
+	for _, target := range p.Targets {
+		total += target.Quantity
+	}
+	return total
+}
+
+// Recalculate refreshes the estimated cost from the unit price and the targets, and the status
+// from the budget. Drafts and plans whose medicine is no longer available keep their status.
+func (p *PurchasePlan) Recalculate() {
+	p.EstimatedCost = p.UnitPrice * float64(p.TotalQuantity())
+
+	if p.Status == PurchasePlanDraft || p.Status == PurchasePlanMedicineUnavailable {
+		return
+	}
+
+	// A budget of zero means the plan has no budget
+	if p.Budget > 0 && p.EstimatedCost > p.Budget {
+		p.Status = PurchasePlanOverBudget
+	} else {
+		p.Status = PurchasePlanActive
+	}
+}
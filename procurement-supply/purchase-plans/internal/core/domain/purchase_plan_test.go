@@ -0,0 +1,46 @@
+package domain
+
+import "testing"
+
+func TestPurchasePlanRecalculate(t *testing.T) {
+	targets := []PeriodTarget{{Period: "2025-01", Quantity: 10}, {Period: "2025-02", Quantity: 30}}
+
+	tests := []struct {
+		name       string
+		status     PurchasePlanStatus
+		budget     float64
+		unitPrice  float64
+		wantCost   float64
+		wantStatus PurchasePlanStatus
+	}{
+		{"within budget", PurchasePlanActive, 100, 2.5, 100, PurchasePlanActive},
+		{"over budget", PurchasePlanActive, 100, 3, 120, PurchasePlanOverBudget},
+		{"back within budget", PurchasePlanOverBudget, 100, 2, 80, PurchasePlanActive},
+		{"without budget", PurchasePlanOverBudget, 0, 1000, 40000, PurchasePlanActive},
+		{"draft over budget", PurchasePlanDraft, 100, 3, 120, PurchasePlanDraft},
+		{"unavailable medicine", PurchasePlanMedicineUnavailable, 100, 2, 80, PurchasePlanMedicineUnavailable},
+		{"unavailable medicine over budget", PurchasePlanMedicineUnavailable, 100, 3, 120, PurchasePlanMedicineUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := PurchasePlan{Targets: targets, Budget: tt.budget, UnitPrice: tt.unitPrice, Status: tt.status}
+			plan.Recalculate()
+
+			if plan.EstimatedCost != tt.wantCost || plan.Status != tt.wantStatus {
+				t.Errorf("Recalculate() = cost %v, status %s, want %v, %s", plan.EstimatedCost, plan.Status, tt.wantCost, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPurchasePlanTotalQuantity(t *testing.T) {
+	plan := PurchasePlan{Targets: []PeriodTarget{{Period: "2025-01", Quantity: 10}, {Period: "2025-02", Quantity: 5}}}
+	if got := plan.TotalQuantity(); got != 15 {
+		t.Errorf("TotalQuantity() = %d, want 15", got)
+	}
+
+	if got := (PurchasePlan{}).TotalQuantity(); got != 0 {
+		t.Errorf("TotalQuantity() without targets = %d, want 0", got)
+	}
+}
@@ -1,11 +1,14 @@
 package driven
 
-import "purchase-plans/internal/core/domain"
+import (
+	"context"
+	"purchase-plans/internal/core/domain"
+)
 
 // MedicineEventHandler represents the driven port for handling medicine events
 // This interface defines what external services our application needs
 type MedicineEventHandler interface {
-	HandleMedicineCreated(event *domain.Event[domain.Medicine]) error
-	HandleMedicineUpdated(event *domain.Event[domain.Medicine]) error
-	HandleMedicineDeleted(event *domain.Event[domain.Medicine]) error
+	HandleMedicineCreated(ctx context.Context, event *domain.Event[domain.Medicine]) error
+	HandleMedicineUpdated(ctx context.Context, event *domain.Event[domain.Medicine]) error
+	HandleMedicineDeleted(ctx context.Context, event *domain.Event[domain.Medicine]) error
 }
@@ -0,0 +1,18 @@
+package driven
+
+import "purchase-plans/internal/core/domain"
+
+// PurchasePlanRepository is the purchase plan Repository that can also look up the plans of a
+// medicine and change their pricing without overwriting the rest of them
+type PurchasePlanRepository interface {
+	Repository[string, domain.PurchasePlan]
+
+	// FindByMedicine retrieves the purchase plans of the medicine
+	FindByMedicine(medicineID string) ([]domain.PurchasePlan, error)
+
+	// UpdatePricing passes a copy of the stored plan to update, which edits it in place and reports
+	// whether it changed, then stores only its unit price, estimated cost, status, price update
+	// time and update time. Other UpdatePricing calls for the plan wait until update returns, so the
+	// plan it edits is never stale. Nothing is stored when update returns false or an error.
+	UpdatePricing(id string, update func(plan *domain.PurchasePlan) (bool, error)) error
+}
@@ -0,0 +1,24 @@
+package driver
+
+import (
+	"context"
+	"purchase-plans/internal/core/domain"
+)
+
+// PurchasePlanService defines the interface for purchase plan business logic
+type PurchasePlanService interface {
+	// CreatePurchasePlan creates a new purchase plan
+	CreatePurchasePlan(ctx context.Context, plan domain.PurchasePlan) (*domain.PurchasePlan, error)
+
+	// RetrievePurchasePlan retrieves a purchase plan by ID
+	RetrievePurchasePlan(ctx context.Context, id string) (*domain.PurchasePlan, error)
+
+	// RetrievePurchasePlans retrieves all purchase plans
+	RetrievePurchasePlans(ctx context.Context) ([]domain.PurchasePlan, error)
+
+	// UpdatePurchasePlan updates an existing purchase plan (full replacement)
+	UpdatePurchasePlan(ctx context.Context, plan domain.PurchasePlan) (*domain.PurchasePlan, error)
+
+	// DeletePurchasePlan removes a purchase plan
+	DeletePurchasePlan(ctx context.Context, id string) error
+}
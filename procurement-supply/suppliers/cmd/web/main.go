@@ -1,11 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
+	httpmetrics "medisupply-infrastructure/pkg/telemetry/metrics"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	nethttp "net/http"
 	"os"
+	"os/signal"
+	"procurement-supply/pkg/events"
+	"strconv"
 	"suppliers/internal/adapter/http"
+	"suppliers/internal/adapter/outbox"
 	"suppliers/internal/adapter/queue"
+	"suppliers/internal/adapter/storage/memory"
+	"suppliers/internal/adapter/storage/postgres"
 	"suppliers/internal/core/application"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
 	"suppliers/pkg/logger"
+	"suppliers/pkg/metrics"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,22 +34,111 @@ func main() {
 	// Get Env variables
 	kafkaHost := os.Getenv("KAFKA_HOST")
 	kafkaTopic := os.Getenv("KAFKA_TOPIC")
+	storageDriver := getEnv("STORAGE_DRIVER", "memory")
+	databaseURL := os.Getenv("DATABASE_URL")
+	eventFormat := getEnv("EVENT_FORMAT", events.FormatJSON)
+	schemaRegistryURL := os.Getenv("SCHEMA_REGISTRY_URL")
+	relayEnabled := getEnvAsBool(log, "OUTBOX_RELAY_ENABLED", true)
+	relayConfig := outbox.RelayConfig{
+		PollInterval:   getEnvAsDuration(log, "OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:      getEnvAsInt(log, "OUTBOX_BATCH_SIZE", 100),
+		PublishTimeout: getEnvAsDuration(log, "OUTBOX_PUBLISH_TIMEOUT", 30*time.Second),
+		MaxBackoff:     getEnvAsDuration(log, "OUTBOX_MAX_BACKOFF", time.Minute),
+		MaxAttempts:    getEnvAsNonNegativeInt(log, "OUTBOX_MAX_ATTEMPTS", 10),
+		Synchronous:    getEnvAsBool(log, "OUTBOX_SYNC_PUBLISH", false),
+	}
+
+	// Setup OpenTelemetry tracing (exporter chosen by OTEL_TRACES_EXPORTER)
+	shutdownTracing, err := tracing.Init(context.Background(), "suppliers")
+	if err != nil {
+		log.Fatal("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("Failed to flush traces: %v", err)
+		}
+	}()
 
-	// Setup router
+	// Setup router with request tracing and Prometheus request metrics by route template
 	router := gin.Default()
+	router.Use(tracing.GinMiddleware())
+	router.Use(httpmetrics.GinMiddleware())
+	router.Use(http.CorrelationMiddleware())
 
 	// Setup Kafka event publisher (driven adapter)
 	log.Info("Initializing Kafka event publisher...")
-	kafkaPublisher, err := queue.NewKafkaEventPublisher(kafkaHost, kafkaTopic)
+	publisherConfig := queue.DefaultPublisherConfig()
+	publisherConfig.LingerMs = getEnvAsInt(log, "KAFKA_LINGER_MS", publisherConfig.LingerMs)
+	publisherConfig.BatchSize = getEnvAsInt(log, "KAFKA_BATCH_SIZE", publisherConfig.BatchSize)
+	publisherConfig.FlushTimeout = getEnvAsDuration(log, "KAFKA_FLUSH_TIMEOUT", publisherConfig.FlushTimeout)
+	publisherConfig.OnDeliveryFailure = func(event *domain.Event[domain.Medicine], err error) {
+		metrics.KafkaDeliveryFailed(kafkaTopic, string(event.EventType))
+	}
+
+	// Setup the event format, negotiated with the consumers through the content_type header
+	schemaRegistry, err := events.NewRegistry(schemaRegistryURL)
+	if err != nil {
+		log.Fatal("Failed to create schema registry client: %v", err)
+	}
+	publisherConfig.Encoder, err = events.NewEncoder(eventFormat, schemaRegistry, queue.MedicineEventSchema)
+	if err != nil {
+		log.Fatal("Failed to create event encoder: %v", err)
+	}
+	log.Info("Publishing events as %s", publisherConfig.Encoder.ContentType())
+
+	kafkaPublisher, err := queue.NewKafkaEventPublisher(kafkaHost, kafkaTopic, publisherConfig)
 	if err != nil {
 		log.Fatal("Failed to create Kafka publisher: %v", err)
 	}
 	defer kafkaPublisher.Close()
 	log.Info("Kafka event publisher initialized successfully")
 
+	// Setup medicine repository and event outbox (driven adapters)
+	var medicineRepo driven.MedicineRepository
+	var eventOutbox driven.Outbox
+	var transactor driven.Transactor
+
+	switch storageDriver {
+	case "postgres":
+		db, err := postgres.Open(context.Background(), databaseURL)
+		if err != nil {
+			log.Fatal("Failed to connect to PostgreSQL: %v", err)
+		}
+		defer db.Close()
+
+		if err := postgres.Migrate(context.Background(), db); err != nil {
+			log.Fatal("Failed to apply database migrations: %v", err)
+		}
+		log.Info("PostgreSQL storage initialized")
+
+		medicineRepo = postgres.NewMedicineRepository(db)
+		eventOutbox = postgres.NewOutbox(db)
+		transactor = postgres.NewTransactor(db)
+	case "memory":
+		medicineRepo = memory.NewMedicineRepository()
+		eventOutbox = memory.NewOutbox()
+		transactor = memory.NewTransactor()
+	default:
+		log.Fatal("Unsupported storage driver: %s", storageDriver)
+	}
+
 	// Setup medicine service (core application)
 	log.Info("Initializing medicine service...")
-	medicineService := application.NewMedicineService(kafkaPublisher)
+	medicineService := application.NewMedicineService(medicineRepo, eventOutbox, transactor)
+
+	// Publish the outbox events. Replicas sharing a database claim distinct events.
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	relayDone := make(chan struct{})
+	if relayEnabled {
+		go func() {
+			defer close(relayDone)
+			outbox.NewRelay(eventOutbox, kafkaPublisher, relayConfig).Run(relayCtx)
+		}()
+	} else {
+		close(relayDone)
+		log.Warn("Outbox relay disabled, events are left in the outbox")
+	}
 
 	// Setup medicine handler (driver adapter)
 	log.Info("Initializing HTTP handlers...")
@@ -42,6 +147,9 @@ func main() {
 	// Health check
 	router.GET("/ping", http.PongHandler)
 
+	// Prometheus metrics
+	router.GET("/metrics", httpmetrics.Handler())
+
 	// Medicine routes
 	medicineRoutes := router.Group("/medicines")
 	{
@@ -55,7 +163,105 @@ func main() {
 	log.Info("All components initialized successfully")
 	log.Info("Server starting on :8080")
 
-	if err = router.Run(); err != nil {
-		log.Fatal("Failed to start server: %v", err)
+	server := &nethttp.Server{
+		Addr:              ":8080",
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info("Shutdown signal received, stopping service...")
+	case err := <-serverErr:
+		log.Error("Server stopped: %v", err)
+	}
+
+	// Stop taking requests, then let the relay finish its batch, so that the deferred Close
+	// flushes every event it queued
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+		log.Error("Failed to shut down the server: %v", err)
 	}
+
+	stopRelay()
+	<-relayDone
+
+	log.Info("Medicine Supplier Service stopped")
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsInt gets an environment variable as a positive integer or returns a default value
+func getEnvAsInt(log *logger.Logger, key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Fatal("Invalid positive integer for %s: %q", key, value)
+	}
+
+	return parsed
+}
+
+// getEnvAsNonNegativeInt gets an environment variable as an integer that may be 0 or returns a default value
+func getEnvAsNonNegativeInt(log *logger.Logger, key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		log.Fatal("Invalid non-negative integer for %s: %q", key, value)
+	}
+
+	return parsed
+}
+
+// getEnvAsDuration gets an environment variable as a duration or returns a default value
+func getEnvAsDuration(log *logger.Logger, key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatal("Invalid duration for %s: %q", key, value)
+	}
+
+	return duration
+}
+
+// getEnvAsBool gets an environment variable as a boolean or returns a default value
+func getEnvAsBool(log *logger.Logger, key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatal("Invalid boolean for %s: %q", key, value)
+	}
+
+	return parsed
 }
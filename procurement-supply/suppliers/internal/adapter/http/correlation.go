@@ -0,0 +1,26 @@
+package http
+
+import (
+	"procurement-supply/pkg/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderCorrelationID carries the correlation ID of a request, echoed back in the response
+const HeaderCorrelationID = "X-Correlation-ID"
+
+// CorrelationMiddleware returns Gin middleware that gives every request a correlation ID, taken
+// from the X-Correlation-ID header or generated. The events raised by the request carry it.
+func CorrelationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationID := c.GetHeader(HeaderCorrelationID)
+		if correlationID == "" {
+			correlationID = uuid.New().String()
+		}
+
+		c.Header(HeaderCorrelationID, correlationID)
+		c.Request = c.Request.WithContext(events.WithCorrelationID(c.Request.Context(), correlationID))
+		c.Next()
+	}
+}
@@ -1,7 +1,11 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"suppliers/internal/core/domain"
 	"suppliers/internal/core/port/driver"
 
@@ -16,15 +20,23 @@ func NewMedicineHandler(service driver.MedicineService) *MedicineHandler {
 	return &MedicineHandler{service: service}
 }
 
+// GetMedicines lists the medicines matching the category, supplier_id and name query parameters,
+// sorted by sort (a field, prefixed with "-" for descending order) and paginated with limit and
+// offset. The number of matches across all pages is returned in the X-Total-Count header.
 func (h *MedicineHandler) GetMedicines(c *gin.Context) {
+	query, err := parseMedicineQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	medicines, err := h.service.RetrieveMedicines()
-
+	medicines, total, err := h.service.RetrieveMedicines(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	c.Header("X-Total-Count", strconv.Itoa(total))
 	c.JSON(http.StatusOK, medicines)
 }
 
@@ -32,9 +44,9 @@ func (h *MedicineHandler) GetMedicine(c *gin.Context) {
 
 	id := c.Param("id")
 
-	medicine, err := h.service.RetrieveMedicine(id)
+	medicine, err := h.service.RetrieveMedicine(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
 		return
 
 	}
@@ -52,7 +64,7 @@ func (h *MedicineHandler) PostMedicine(c *gin.Context) {
 	}
 
 	// Create the medicine using the service
-	createdMedicine, err := h.service.CreateMedicine(&medicine)
+	createdMedicine, err := h.service.CreateMedicine(c.Request.Context(), &medicine)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create medicine: " + err.Error()})
 		return
@@ -72,9 +84,9 @@ func (h *MedicineHandler) PutMedicine(c *gin.Context) {
 	}
 
 	// Update the medicine using the service
-	updatedMedicine, err := h.service.UpdateMedicine(id, &medicine)
+	updatedMedicine, err := h.service.UpdateMedicine(c.Request.Context(), id, &medicine)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update medicine: " + err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": "Failed to update medicine: " + err.Error()})
 		return
 	}
 
@@ -85,11 +97,51 @@ func (h *MedicineHandler) DeleteMedicine(c *gin.Context) {
 	id := c.Param("id")
 
 	// Delete the medicine using the service
-	err := h.service.DeleteMedicine(id)
+	err := h.service.DeleteMedicine(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete medicine: " + err.Error()})
+		c.JSON(statusFor(err), gin.H{"error": "Failed to delete medicine: " + err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Medicine deleted successfully"})
 }
+
+// parseMedicineQuery reads the filters, sort order and page of a medicine listing from the query string
+func parseMedicineQuery(c *gin.Context) (domain.MedicineQuery, error) {
+	query := domain.MedicineQuery{
+		Category:   c.Query("category"),
+		SupplierID: c.Query("supplier_id"),
+		Name:       c.Query("name"),
+	}
+
+	sort := c.Query("sort")
+	query.Descending = strings.HasPrefix(sort, "-")
+	query.SortBy = strings.TrimPrefix(sort, "-")
+
+	for param, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s: %q is not a number", param, value)
+		}
+		*target = parsed
+	}
+
+	return query, nil
+}
+
+// statusFor maps a service error to its HTTP status code
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrMedicineNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidQuery):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"suppliers/internal/core/domain"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseMedicineQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    domain.MedicineQuery
+		wantErr bool
+	}{
+		{
+			name:  "no parameters",
+			query: "",
+			want:  domain.MedicineQuery{},
+		},
+		{
+			name:  "filters",
+			query: "category=analgesic&supplier_id=supplier-1&name=asp",
+			want:  domain.MedicineQuery{Category: "analgesic", SupplierID: "supplier-1", Name: "asp"},
+		},
+		{
+			name:  "ascending sort",
+			query: "sort=price",
+			want:  domain.MedicineQuery{SortBy: domain.SortByPrice},
+		},
+		{
+			name:  "descending sort",
+			query: "sort=-created_at",
+			want:  domain.MedicineQuery{SortBy: domain.SortByCreatedAt, Descending: true},
+		},
+		{
+			name:  "unsupported sort is left to validation",
+			query: "sort=-supplier_id",
+			want:  domain.MedicineQuery{SortBy: "supplier_id", Descending: true},
+		},
+		{
+			name:  "page",
+			query: "limit=10&offset=30",
+			want:  domain.MedicineQuery{Limit: 10, Offset: 30},
+		},
+		{
+			name:    "limit is not a number",
+			query:   "limit=ten",
+			wantErr: true,
+		},
+		{
+			name:    "offset is not a number",
+			query:   "offset=1.5",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/medicines?"+tt.query, nil)
+
+			query, err := parseMedicineQuery(c)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseMedicineQuery() = %+v, want an error", query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMedicineQuery() error = %v", err)
+			}
+			if query != tt.want {
+				t.Errorf("parseMedicineQuery() = %+v, want %+v", query, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "medicine not found",
+			err:  fmt.Errorf("%w: m1", domain.ErrMedicineNotFound),
+			want: http.StatusNotFound,
+		},
+		{
+			name: "invalid query",
+			err:  fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidQuery, "supplier_id"),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "storage failure",
+			err:  errors.New("connection refused"),
+			want: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusFor(tt.err); got != tt.want {
+				t.Errorf("statusFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
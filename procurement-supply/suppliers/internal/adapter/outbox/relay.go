@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+	"suppliers/pkg/logger"
+	"time"
+)
+
+// RelayConfig tunes how the relay drains the outbox
+type RelayConfig struct {
+	PollInterval   time.Duration // wait between polls once the outbox is drained
+	BatchSize      int           // events read from the outbox per poll
+	PublishTimeout time.Duration // how long the delivery of a batch, or of an event when publishing synchronously, may take
+	MaxBackoff     time.Duration // longest wait after consecutive failures, starting from PollInterval
+	MaxAttempts    int           // failed publishes after which an event is parked, 0 to never park
+	Synchronous    bool          // publish one event at a time, waiting for each acknowledgement
+}
+
+// Relay publishes the events written to the outbox and removes them once delivered.
+//
+// Each poll claims the oldest waiting event of every medicine, so a later event of a medicine
+// never overtakes an earlier one, even with several relays sharing the outbox. The claimed events
+// are produced together, and removed once every delivery report of the batch came back, unless the
+// relay is synchronous and waits for each event before publishing the next one. A failed event
+// stays in the outbox and blocks only the later events of its medicine. It is parked once it failed
+// MaxAttempts times in polls that delivered other events, so that a broker outage, which fails
+// every event, parks none of them. An event the broker rejected for good, with an error wrapping
+// domain.ErrEventRejected, is parked on its first failure. When a whole poll fails the relay backs
+// off before retrying.
+// Delivery is at least once: an event published but not removed is published again, with the
+// same event ID for consumers to deduplicate on.
+type Relay struct {
+	outbox    driven.Outbox
+	publisher driven.EventPublisher
+	config    RelayConfig
+	logger    *logger.Logger
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(outbox driven.Outbox, publisher driven.EventPublisher, config RelayConfig) *Relay {
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		config:    config,
+		logger:    logger.New("OUTBOX"),
+	}
+}
+
+// Run relays the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started: poll_interval=%s, batch_size=%d", r.config.PollInterval, r.config.BatchSize)
+
+	wait := r.config.PollInterval
+	for {
+		published, err := r.relay(ctx)
+		switch {
+		case err != nil:
+			r.logger.Warn("Outbox relay failed, retrying in %s: %v", wait, err)
+			wait = min(wait*2, r.config.MaxBackoff)
+		case published > 0:
+			// Later events of the medicines just published may be waiting
+			wait = r.config.PollInterval
+			continue
+		default:
+			wait = r.config.PollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// relay publishes one batch of claimed events and returns how many were delivered. It fails
+// when events were claimed but none could be delivered.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	published := 0
+	err := r.outbox.Claim(ctx, r.config.BatchSize, func(ctx context.Context, entries []domain.OutboxEntry) error {
+		failed := make([]failedEntry, 0)
+
+		var results []error
+		if r.config.Synchronous {
+			results = r.publishEach(ctx, entries)
+		} else {
+			results = r.publishBatch(ctx, entries)
+		}
+
+		for i, entry := range entries {
+			if err := results[i]; err != nil {
+				r.logger.Error("Failed to publish outbox event: event_id=%s, type=%s, attempts=%d: %v",
+					entry.Event.ID, entry.Event.EventType, entry.Attempts+1, err)
+				failed = append(failed, failedEntry{entry: entry, err: err})
+				continue
+			}
+
+			if err := r.outbox.MarkPublished(ctx, entry.Event.ID); err != nil {
+				return err
+			}
+			published++
+		}
+
+		return r.recordFailures(ctx, failed, published > 0)
+	})
+
+	return published, err
+}
+
+// publishBatch queues every event first, letting the producer send them in as few requests as it
+// can, and then waits for their delivery reports. It returns the outcome of each event.
+func (r *Relay) publishBatch(ctx context.Context, entries []domain.OutboxEntry) []error {
+	deliveryCtx, cancel := context.WithTimeout(ctx, r.config.PublishTimeout)
+	defer cancel()
+
+	pending := make([]<-chan error, len(entries))
+	for i := range entries {
+		// Publish within the trace of the request that raised the event
+		pending[i] = r.publisher.PublishMedicineEventAsync(tracing.ExtractMap(deliveryCtx, entries[i].TraceContext), &entries[i].Event)
+	}
+
+	results := make([]error, len(entries))
+	for i := range pending {
+		results[i] = awaitDelivery(deliveryCtx, pending[i])
+	}
+	return results
+}
+
+// publishEach publishes the events one at a time, waiting for each acknowledgement. It returns
+// the outcome of each event.
+func (r *Relay) publishEach(ctx context.Context, entries []domain.OutboxEntry) []error {
+	results := make([]error, len(entries))
+	for i := range entries {
+		// Publish within the trace of the request that raised the event
+		publishCtx, cancel := context.WithTimeout(tracing.ExtractMap(ctx, entries[i].TraceContext), r.config.PublishTimeout)
+		results[i] = r.publisher.PublishMedicineEvent(publishCtx, &entries[i].Event)
+		cancel()
+	}
+	return results
+}
+
+// awaitDelivery waits for the delivery report of an event. A report already received wins over
+// the deadline, so that a delivered event is not published again.
+func awaitDelivery(ctx context.Context, result <-chan error) error {
+	select {
+	case err := <-result:
+		return err
+	default:
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("delivery not confirmed: %w", ctx.Err())
+	}
+}
+
+// failedEntry is an outbox event whose publish failed
+type failedEntry struct {
+	entry domain.OutboxEntry
+	err   error
+}
+
+// recordFailures records the failed publishes of a poll. Rejected events are parked right away.
+// When other events were delivered, the events that reached MaxAttempts are parked too; when none
+// were, the poll failed as a whole, unless every failed event was rejected and parked.
+func (r *Relay) recordFailures(ctx context.Context, failed []failedEntry, delivered bool) error {
+	var retried []failedEntry
+	for _, f := range failed {
+		attempts := f.entry.Attempts + 1
+		rejected := errors.Is(f.err, domain.ErrEventRejected)
+
+		if rejected || (delivered && r.config.MaxAttempts > 0 && attempts >= r.config.MaxAttempts) {
+			if err := r.outbox.Park(ctx, f.entry.Event.ID, f.err); err != nil {
+				return err
+			}
+			r.logger.Error("Outbox event parked: event_id=%s, type=%s, attempts=%d: %v",
+				f.entry.Event.ID, f.entry.Event.EventType, attempts, f.err)
+			continue
+		}
+
+		if err := r.outbox.MarkFailed(ctx, f.entry.Event.ID, f.err); err != nil {
+			r.logger.Error("Failed to record publish failure: event_id=%s: %v", f.entry.Event.ID, err)
+		}
+		retried = append(retried, f)
+	}
+
+	if len(retried) > 0 && !delivered {
+		return fmt.Errorf("failed to publish %d outbox events: %w", len(retried), retried[0].err)
+	}
+	return nil
+}
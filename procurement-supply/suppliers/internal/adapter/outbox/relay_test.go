@@ -0,0 +1,242 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"suppliers/internal/adapter/storage/memory"
+	"suppliers/internal/core/domain"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubPublisher records the events it publishes and fails the ones it is told to
+type stubPublisher struct {
+	mu        sync.Mutex
+	failAll   error            // every publish fails with it when set
+	failures  map[string]error // publish errors by event ID
+	published []string         // IDs of the events published, in order
+}
+
+func newStubPublisher() *stubPublisher {
+	return &stubPublisher{failures: make(map[string]error)}
+}
+
+func (p *stubPublisher) PublishMedicineEvent(_ context.Context, event *domain.Event[domain.Medicine]) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failAll != nil {
+		return p.failAll
+	}
+	if err := p.failures[event.ID]; err != nil {
+		return err
+	}
+
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func (p *stubPublisher) PublishMedicineEventAsync(ctx context.Context, event *domain.Event[domain.Medicine]) <-chan error {
+	result := make(chan error, 1)
+	result <- p.PublishMedicineEvent(ctx, event)
+	return result
+}
+
+func (p *stubPublisher) Close() {}
+
+// takePublished returns and forgets the IDs of the events published so far
+func (p *stubPublisher) takePublished() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	published := p.published
+	p.published = nil
+	return published
+}
+
+// enqueue adds an update event of a medicine to the outbox and returns its ID
+func enqueue(t *testing.T, outbox *memory.Outbox, medicineID string) string {
+	t.Helper()
+
+	event := domain.NewMedicineEvent(domain.Medicine{ID: medicineID, Name: "Medicine " + medicineID}, domain.MedicineUpdatedEvent)
+	if err := outbox.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	return event.ID
+}
+
+// waiting returns the events the next poll would claim
+func waiting(t *testing.T, outbox *memory.Outbox) []domain.OutboxEntry {
+	t.Helper()
+
+	var claimed []domain.OutboxEntry
+	err := outbox.Claim(context.Background(), 100, func(_ context.Context, entries []domain.OutboxEntry) error {
+		claimed = entries
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	return claimed
+}
+
+func testConfig(maxAttempts int, synchronous bool) RelayConfig {
+	return RelayConfig{
+		PollInterval:   time.Millisecond,
+		BatchSize:      100,
+		PublishTimeout: time.Second,
+		MaxBackoff:     time.Millisecond,
+		MaxAttempts:    maxAttempts,
+		Synchronous:    synchronous,
+	}
+}
+
+func TestRelayPublishesTheEventsOfAMedicineInOrder(t *testing.T) {
+	for _, synchronous := range []bool{false, true} {
+		t.Run(fmt.Sprintf("synchronous=%t", synchronous), func(t *testing.T) {
+			outbox := memory.NewOutbox()
+			publisher := newStubPublisher()
+			relay := NewRelay(outbox, publisher, testConfig(3, synchronous))
+
+			first := enqueue(t, outbox, "m1")
+			second := enqueue(t, outbox, "m1")
+			other := enqueue(t, outbox, "m2")
+
+			// Each poll claims only the oldest event of every medicine
+			polls := [][]string{{first, other}, {second}, nil}
+			for i, want := range polls {
+				published, err := relay.relay(context.Background())
+				if err != nil {
+					t.Fatalf("poll %d: relay() error = %v", i, err)
+				}
+				if published != len(want) {
+					t.Errorf("poll %d: relay() = %d, want %d", i, published, len(want))
+				}
+				if got := publisher.takePublished(); !slices.Equal(got, want) {
+					t.Errorf("poll %d: published %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRelayParksAnEventAfterMaxAttempts(t *testing.T) {
+	outbox := memory.NewOutbox()
+	publisher := newStubPublisher()
+	relay := NewRelay(outbox, publisher, testConfig(3, false))
+
+	failing := enqueue(t, outbox, "m1")
+	next := enqueue(t, outbox, "m1")
+	publisher.failures[failing] = errors.New("broker unavailable for this partition")
+
+	// Other medicines are delivered meanwhile, so the failures are the event's own
+	for i := range 3 {
+		delivered := enqueue(t, outbox, fmt.Sprintf("other-%d", i))
+		if _, err := relay.relay(context.Background()); err != nil {
+			t.Fatalf("poll %d: relay() error = %v", i, err)
+		}
+		if got := publisher.takePublished(); !slices.Equal(got, []string{delivered}) {
+			t.Errorf("poll %d: published %v, want %v", i, got, []string{delivered})
+		}
+	}
+
+	// The parked event no longer holds back the next event of its medicine
+	entries := waiting(t, outbox)
+	if len(entries) != 1 || entries[0].Event.ID != next {
+		t.Fatalf("waiting events = %v, want only %s", entries, next)
+	}
+
+	if _, err := relay.relay(context.Background()); err != nil {
+		t.Fatalf("relay() error = %v", err)
+	}
+	if got := publisher.takePublished(); !slices.Equal(got, []string{next}) {
+		t.Errorf("published %v, want %v", got, []string{next})
+	}
+}
+
+func TestRelayParksARejectedEventRightAway(t *testing.T) {
+	outbox := memory.NewOutbox()
+	publisher := newStubPublisher()
+	relay := NewRelay(outbox, publisher, testConfig(10, false))
+
+	rejected := enqueue(t, outbox, "m1")
+	next := enqueue(t, outbox, "m1")
+	publisher.failures[rejected] = fmt.Errorf("delivery failed: %w: message too large", domain.ErrEventRejected)
+
+	// Parking the only claimed event is progress, not a failed poll
+	if _, err := relay.relay(context.Background()); err != nil {
+		t.Fatalf("relay() error = %v", err)
+	}
+
+	entries := waiting(t, outbox)
+	if len(entries) != 1 || entries[0].Event.ID != next {
+		t.Fatalf("waiting events = %v, want only %s", entries, next)
+	}
+}
+
+func TestRelayParksNothingDuringAnOutage(t *testing.T) {
+	outbox := memory.NewOutbox()
+	publisher := newStubPublisher()
+	relay := NewRelay(outbox, publisher, testConfig(2, false))
+
+	first := enqueue(t, outbox, "m1")
+	other := enqueue(t, outbox, "m2")
+	publisher.failAll = errors.New("all brokers down")
+
+	const polls = 5
+	for i := range polls {
+		if _, err := relay.relay(context.Background()); err == nil {
+			t.Fatalf("poll %d: relay() succeeded while every publish fails", i)
+		}
+	}
+
+	entries := waiting(t, outbox)
+	if len(entries) != 2 {
+		t.Fatalf("%d events waiting, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Attempts != polls {
+			t.Errorf("event %s attempts = %d, want %d", entry.Event.ID, entry.Attempts, polls)
+		}
+	}
+
+	// Once the broker is back every event is published
+	publisher.failAll = nil
+	published, err := relay.relay(context.Background())
+	if err != nil {
+		t.Fatalf("relay() error = %v", err)
+	}
+	if published != 2 {
+		t.Errorf("relay() = %d, want 2", published)
+	}
+	if got := publisher.takePublished(); !slices.Equal(got, []string{first, other}) {
+		t.Errorf("published %v, want %v", got, []string{first, other})
+	}
+}
+
+func TestRelayNeverParksWithoutMaxAttempts(t *testing.T) {
+	outbox := memory.NewOutbox()
+	publisher := newStubPublisher()
+	relay := NewRelay(outbox, publisher, testConfig(0, false))
+
+	failing := enqueue(t, outbox, "m1")
+	publisher.failures[failing] = errors.New("broker unavailable for this partition")
+
+	for i := range 5 {
+		enqueue(t, outbox, fmt.Sprintf("other-%d", i))
+		if _, err := relay.relay(context.Background()); err != nil {
+			t.Fatalf("poll %d: relay() error = %v", i, err)
+		}
+	}
+
+	entries := waiting(t, outbox)
+	if len(entries) != 1 || entries[0].Event.ID != failing {
+		t.Fatalf("waiting events = %v, want only %s", entries, failing)
+	}
+	if entries[0].Attempts != 5 {
+		t.Errorf("attempts = %d, want 5", entries[0].Attempts)
+	}
+}
@@ -1,46 +1,144 @@
 package queue
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"medisupply-infrastructure/pkg/telemetry/tracing/kafkatrace"
+	"procurement-supply/pkg/events"
 	"suppliers/internal/core/domain"
 	"suppliers/pkg/logger"
+	"suppliers/pkg/metrics"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// KafkaEventPublisher implements the EventPublisher driven port
+// PublisherConfig tunes how the producer batches messages
+type PublisherConfig struct {
+	LingerMs     int            // how long the producer waits for more messages to fill a batch
+	BatchSize    int            // maximum size of a batch, in bytes
+	FlushTimeout time.Duration  // how long Close waits for the messages still queued
+	Encoder      events.Encoder // format of the published events, JSON when nil
+
+	// OnDeliveryFailure is called, from the delivery report goroutine, for every message Kafka
+	// failed to deliver, before the error reaches the publisher.
+	OnDeliveryFailure func(event *domain.Event[domain.Medicine], err error)
+}
+
+// DefaultPublisherConfig returns the batching settings used when none are configured
+func DefaultPublisherConfig() PublisherConfig {
+	return PublisherConfig{
+		LingerMs:     5,
+		BatchSize:    1000000,
+		FlushTimeout: 10 * time.Second,
+		Encoder:      events.NewJSONEncoder(),
+	}
+}
+
+// KafkaEventPublisher implements the EventPublisher driven port.
+//
+// Every message is delivered by the same idempotent producer, whose delivery reports are
+// handled by a single goroutine: it ends the producer span, counts the outcome and hands the
+// result back to the publisher, whether it waits for it (PublishMedicineEvent) or not
+// (PublishMedicineEventAsync).
 type KafkaEventPublisher struct {
 	producer *kafka.Producer
 	topic    string
+	config   PublisherConfig
+	encoder  events.Encoder
 	logger   *logger.Logger
+	done     chan struct{}
+}
+
+// delivery travels with a message as its opaque value, until Kafka reports its delivery
+type delivery struct {
+	event  *domain.Event[domain.Medicine]
+	span   trace.Span
+	result chan error
 }
 
 // NewKafkaEventPublisher creates a new Kafka event publisher
-func NewKafkaEventPublisher(bootstrapServers, topic string) (*KafkaEventPublisher, error) {
+func NewKafkaEventPublisher(bootstrapServers, topic string, config PublisherConfig) (*KafkaEventPublisher, error) {
 	producer, err := kafka.NewProducer(&kafka.ConfigMap{
 		"bootstrap.servers": bootstrapServers,
+		// Retries keep the order of the messages and never write them twice
+		"enable.idempotence": true,
+		"acks":               "all",
+		"linger.ms":          config.LingerMs,
+		"batch.size":         config.BatchSize,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	return &KafkaEventPublisher{
+	k := &KafkaEventPublisher{
 		producer: producer,
 		topic:    topic,
+		config:   config,
+		encoder:  config.Encoder,
 		logger:   logger.New("KAFKA"),
-	}, nil
+		done:     make(chan struct{}),
+	}
+	if k.encoder == nil {
+		k.encoder = events.NewJSONEncoder()
+	}
+	go k.handleDeliveries()
+
+	return k, nil
+}
+
+// PublishMedicineEvent publishes a medicine event to Kafka and waits until it is acknowledged
+func (k *KafkaEventPublisher) PublishMedicineEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error {
+	result := k.PublishMedicineEventAsync(ctx, event)
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		k.logger.Error("Delivery not confirmed for medicine_id=%s: %v", event.Data.ID, ctx.Err())
+		return fmt.Errorf("delivery not confirmed: %w", ctx.Err())
+	}
 }
 
-// PublishMedicineEvent publishes a medicine event to Kafka
-func (k *KafkaEventPublisher) PublishMedicineEvent(event *domain.Event[domain.Medicine]) error {
+// PublishMedicineEventAsync queues a medicine event for publishing and returns without waiting for
+// Kafka, so that the producer batches messages instead of waiting one round trip per message.
+// The returned channel receives the outcome of the delivery, exactly once.
+func (k *KafkaEventPublisher) PublishMedicineEventAsync(ctx context.Context, event *domain.Event[domain.Medicine]) <-chan error {
+	// The delivery report must not block the delivery goroutine, whether or not it is awaited
+	result := make(chan error, 1)
+	if err := k.publish(ctx, event, result); err != nil {
+		result <- err
+	}
+	return result
+}
+
+// publish serializes a medicine event within a producer span, carrying the trace context in the
+// message headers, and queues it. The delivery report is sent to result.
+func (k *KafkaEventPublisher) publish(ctx context.Context, event *domain.Event[domain.Medicine], result chan error) error {
+	ctx, span := tracing.Tracer().Start(ctx, k.topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(k.topic),
+			semconv.MessagingKafkaMessageKey(event.Data.ID),
+		),
+	)
+
 	k.logger.Info("Publishing event: type=%s, medicine_id=%s", event.EventType, event.Data.ID)
 
-	// Serialize the event to JSON
-	eventBytes, err := json.Marshal(event)
+	// Serialize the event in the configured format
+	eventBytes, err := k.encoder.Encode(k.topic, event)
 	if err != nil {
-		k.logger.Error("Failed to marshal event: %v", err)
-		return fmt.Errorf("failed to marshal event: %w", err)
+		k.logger.Error("Failed to encode event: %v", err)
+		err = fmt.Errorf("failed to encode event: %w", err)
+		metrics.KafkaMessagePublished(k.topic, err)
+		tracing.EndSpan(span, err)
+		return err
 	}
 	k.logger.Debug("Event serialized successfully, size=%d bytes", len(eventBytes))
 
@@ -52,34 +150,91 @@ func (k *KafkaEventPublisher) PublishMedicineEvent(event *domain.Event[domain.Me
 		},
 		Value: eventBytes,
 		Key:   []byte(event.Data.ID), // Use medicine ID as the message key for partitioning
+		// Let consumers pick the decoder and deduplicate redelivered events without reading the payload
+		Headers: event.Headers(k.encoder.ContentType()),
+		Opaque: &delivery{
+			event:  event,
+			span:   span,
+			result: result,
+		},
 	}
+	kafkatrace.Inject(ctx, message)
 
-	// Produce the message
-	deliveryChan := make(chan kafka.Event)
-	err = k.producer.Produce(message, deliveryChan)
-	if err != nil {
+	// Queue the message, its delivery is reported on the producer events channel
+	if err := k.producer.Produce(message, nil); err != nil {
 		k.logger.Error("Failed to produce message: %v", err)
-		return fmt.Errorf("failed to produce message: %w", err)
+		err = fmt.Errorf("failed to produce message: %w", classify(err))
+		metrics.KafkaMessagePublished(k.topic, err)
+		tracing.EndSpan(span, err)
+		return err
+	}
+
+	return nil
+}
+
+// handleDeliveries processes the delivery reports and errors of the producer until it is closed
+func (k *KafkaEventPublisher) handleDeliveries() {
+	defer close(k.done)
+
+	for e := range k.producer.Events() {
+		switch ev := e.(type) {
+		case *kafka.Message:
+			k.delivered(ev)
+		case kafka.Error:
+			k.logger.Error("Kafka producer error: code=%s: %v", ev.Code(), ev)
+		}
 	}
+}
 
-	// Wait for delivery confirmation
-	k.logger.Debug("Waiting for delivery confirmation...")
-	e := <-deliveryChan
-	m := e.(*kafka.Message)
+// delivered completes the publish of a message once Kafka reported its delivery
+func (k *KafkaEventPublisher) delivered(m *kafka.Message) {
+	d, ok := m.Opaque.(*delivery)
+	if !ok {
+		return
+	}
 
+	var err error
 	if m.TopicPartition.Error != nil {
-		k.logger.Error("Delivery failed for medicine_id=%s: %v", event.Data.ID, m.TopicPartition.Error)
-		return fmt.Errorf("delivery failed: %w", m.TopicPartition.Error)
+		err = fmt.Errorf("delivery failed: %w", classify(m.TopicPartition.Error))
+		k.logger.Error("Delivery failed for medicine_id=%s: %v", d.event.Data.ID, m.TopicPartition.Error)
+	} else {
+		k.logger.Info("Event delivered successfully: topic=%s, partition=%d, offset=%d",
+			*m.TopicPartition.Topic, m.TopicPartition.Partition, m.TopicPartition.Offset)
 	}
 
-	k.logger.Info("Event delivered successfully: topic=%s, partition=%d, offset=%d",
-		*m.TopicPartition.Topic, m.TopicPartition.Partition, m.TopicPartition.Offset)
+	metrics.KafkaMessagePublished(k.topic, err)
+	tracing.EndSpan(d.span, err)
 
-	close(deliveryChan)
-	return nil
+	if err != nil && k.config.OnDeliveryFailure != nil {
+		k.config.OnDeliveryFailure(d.event, err)
+	}
+	d.result <- err
 }
 
-// Close closes the Kafka producer
+// rejectedCodes are the producer errors a message hits again on every attempt
+var rejectedCodes = map[kafka.ErrorCode]bool{
+	kafka.ErrMsgSizeTooLarge:    true,
+	kafka.ErrInvalidMsgSize:     true,
+	kafka.ErrInvalidMsg:         true,
+	kafka.ErrInvalidRecord:      true,
+	kafka.ErrRecordListTooLarge: true,
+}
+
+// classify marks the producer errors that retrying cannot fix with domain.ErrEventRejected
+func classify(err error) error {
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) && rejectedCodes[kafkaErr.Code()] {
+		return fmt.Errorf("%w: %w", domain.ErrEventRejected, err)
+	}
+	return err
+}
+
+// Close waits for the queued messages to be delivered, up to the flush timeout, and closes the Kafka producer
 func (k *KafkaEventPublisher) Close() {
+	if remaining := k.producer.Flush(int(k.config.FlushTimeout.Milliseconds())); remaining > 0 {
+		k.logger.Warn("Closing Kafka producer with %d undelivered messages", remaining)
+	}
+
 	k.producer.Close()
+	<-k.done
 }
@@ -0,0 +1,51 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"suppliers/internal/core/domain"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		rejected bool
+	}{
+		{
+			name:     "message too large",
+			err:      kafka.NewError(kafka.ErrMsgSizeTooLarge, "Broker: Message size too large", false),
+			rejected: true,
+		},
+		{
+			name:     "wrapped invalid record",
+			err:      fmt.Errorf("produce: %w", kafka.NewError(kafka.ErrInvalidRecord, "Broker: Broker failed to validate record", false)),
+			rejected: true,
+		},
+		{
+			name:     "message timed out",
+			err:      kafka.NewError(kafka.ErrMsgTimedOut, "Local: Message timed out", false),
+			rejected: false,
+		},
+		{
+			name:     "not a Kafka error",
+			err:      errors.New("connection refused"),
+			rejected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classify(tt.err)
+			if got := errors.Is(err, domain.ErrEventRejected); got != tt.rejected {
+				t.Errorf("classify() = %v, rejected %t, want %t", err, got, tt.rejected)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classify() = %v, no longer wraps %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package queue
+
+import _ "embed"
+
+// MedicineEventSchema is the Avro schema of the medicine events, used with the avro event format
+//
+//go:embed schemas/medicine_event.avsc
+var MedicineEventSchema string
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+	"sync"
+)
+
+// MedicineRepository is an in-memory implementation of the MedicineRepository port
+type MedicineRepository struct {
+	mu        sync.RWMutex
+	medicines map[string]domain.Medicine
+}
+
+// Ensure MedicineRepository implements the MedicineRepository interface
+var _ driven.MedicineRepository = (*MedicineRepository)(nil)
+
+// NewMedicineRepository creates a new in-memory medicine repository
+func NewMedicineRepository() *MedicineRepository {
+	return &MedicineRepository{
+		medicines: make(map[string]domain.Medicine),
+	}
+}
+
+// Create adds a new medicine to the repository
+func (r *MedicineRepository) Create(_ context.Context, medicine domain.Medicine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[medicine.ID]; exists {
+		return fmt.Errorf("medicine with id %s already exists", medicine.ID)
+	}
+
+	r.medicines[medicine.ID] = medicine
+	return nil
+}
+
+// FindByID retrieves a medicine by its ID
+func (r *MedicineRepository) FindByID(_ context.Context, id string) (*domain.Medicine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	medicine, exists := r.medicines[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	return &medicine, nil
+}
+
+// FindByIDForUpdate retrieves a medicine by its ID. The in-memory Transactor already runs units
+// of work one at a time, so there is nothing to lock.
+func (r *MedicineRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.Medicine, error) {
+	return r.FindByID(ctx, id)
+}
+
+// Find returns the page of medicines matching the query and the number of matches
+func (r *MedicineRepository) Find(_ context.Context, query domain.MedicineQuery) ([]domain.Medicine, int, error) {
+	switch query.SortBy {
+	case domain.SortByName, domain.SortByPrice, domain.SortByCategory, domain.SortByCreatedAt, domain.SortByUpdatedAt:
+	default:
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidQuery, query.SortBy)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name := strings.ToLower(query.Name)
+	matches := make([]domain.Medicine, 0)
+	for _, medicine := range r.medicines {
+		if query.Category != "" && medicine.Category != query.Category {
+			continue
+		}
+		if query.SupplierID != "" && medicine.SupplierID != query.SupplierID {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(medicine.Name), name) {
+			continue
+		}
+		matches = append(matches, medicine)
+	}
+
+	slices.SortFunc(matches, func(a, b domain.Medicine) int {
+		// Ties are broken by ID in the same direction, as the PostgreSQL adapter does
+		order := cmp.Or(compareBy(query.SortBy, a, b), cmp.Compare(a.ID, b.ID))
+		if query.Descending {
+			order = -order
+		}
+		return order
+	})
+
+	total := len(matches)
+	start := min(query.Offset, total)
+	end := min(start+query.Limit, total)
+
+	return matches[start:end], total, nil
+}
+
+// Update modifies an existing medicine
+func (r *MedicineRepository) Update(_ context.Context, medicine domain.Medicine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[medicine.ID]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, medicine.ID)
+	}
+
+	r.medicines[medicine.ID] = medicine
+	return nil
+}
+
+// Delete removes a medicine by its ID
+func (r *MedicineRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.medicines[id]; !exists {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	delete(r.medicines, id)
+	return nil
+}
+
+// compareBy compares two medicines on a sort field
+func compareBy(field string, a, b domain.Medicine) int {
+	switch field {
+	case domain.SortByPrice:
+		return cmp.Compare(a.Price, b.Price)
+	case domain.SortByCategory:
+		return cmp.Compare(a.Category, b.Category)
+	case domain.SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case domain.SortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return cmp.Compare(a.Name, b.Name)
+	}
+}
@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"suppliers/internal/adapter/storage/storagetest"
+	"testing"
+)
+
+func TestMedicineRepository(t *testing.T) {
+	storagetest.RunMedicineRepositoryTests(t, func(*testing.T) storagetest.Store {
+		return storagetest.Store{
+			Medicines:  NewMedicineRepository(),
+			Outbox:     NewOutbox(),
+			Transactor: NewTransactor(),
+		}
+	})
+}
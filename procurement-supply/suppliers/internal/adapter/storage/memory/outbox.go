@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+	"sync"
+	"time"
+)
+
+// Outbox is an in-memory implementation of the Outbox port. Pending events are lost on restart.
+type Outbox struct {
+	claiming sync.Mutex // held while a claim runs
+	mu       sync.Mutex
+	entries  []domain.OutboxEntry
+	parked   []domain.OutboxEntry
+}
+
+// Ensure Outbox implements the Outbox interface
+var _ driven.Outbox = (*Outbox)(nil)
+
+// NewOutbox creates a new in-memory outbox
+func NewOutbox() *Outbox {
+	return &Outbox{
+		entries: make([]domain.OutboxEntry, 0),
+		parked:  make([]domain.OutboxEntry, 0),
+	}
+}
+
+// Enqueue adds an event to the outbox, along with the trace context of ctx
+func (o *Outbox) Enqueue(ctx context.Context, event *domain.Event[domain.Medicine]) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.entries = append(o.entries, domain.OutboxEntry{
+		Event:        *event,
+		TraceContext: tracing.InjectMap(ctx),
+		CreatedAt:    time.Now().UTC(),
+	})
+	return nil
+}
+
+// Claim runs fn with up to limit events waiting to be published, oldest first. Claims run one at
+// a time, and only the oldest event of each medicine is claimed.
+func (o *Outbox) Claim(ctx context.Context, limit int, fn func(ctx context.Context, entries []domain.OutboxEntry) error) error {
+	o.claiming.Lock()
+	defer o.claiming.Unlock()
+
+	o.mu.Lock()
+	entries := make([]domain.OutboxEntry, 0, min(limit, len(o.entries)))
+	claimed := make(map[string]bool)
+	for _, entry := range o.entries {
+		if len(entries) == limit {
+			break
+		}
+		if claimed[entry.Event.Data.ID] {
+			continue
+		}
+		claimed[entry.Event.Data.ID] = true
+		entries = append(entries, entry)
+	}
+	o.mu.Unlock()
+
+	return fn(ctx, entries)
+}
+
+// MarkPublished removes a published event from the outbox
+func (o *Outbox) MarkPublished(_ context.Context, eventID string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].Event.ID == eventID {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt to publish an event
+func (o *Outbox) MarkFailed(_ context.Context, eventID string, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].Event.ID == eventID {
+			o.entries[i].Attempts++
+			o.entries[i].LastError = cause.Error()
+			break
+		}
+	}
+	return nil
+}
+
+// Park records a last failed attempt to publish an event and sets it aside
+func (o *Outbox) Park(_ context.Context, eventID string, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].Event.ID == eventID {
+			entry := o.entries[i]
+			entry.Attempts++
+			entry.LastError = cause.Error()
+
+			o.parked = append(o.parked, entry)
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
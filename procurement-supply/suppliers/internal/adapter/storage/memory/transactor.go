@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"context"
+	"suppliers/internal/core/port/driven"
+	"sync"
+)
+
+// Transactor is an in-memory implementation of the Transactor port. It runs units of work one
+// at a time, but cannot roll back: a unit of work must make its changes that may fail first.
+type Transactor struct {
+	mu sync.Mutex
+}
+
+// Ensure Transactor implements the Transactor interface
+var _ driven.Transactor = (*Transactor)(nil)
+
+// NewTransactor creates a new in-memory transactor
+func NewTransactor() *Transactor {
+	return &Transactor{}
+}
+
+// WithinTransaction runs fn while no other unit of work runs
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return fn(ctx)
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+)
+
+// medicineColumns lists the columns read into a domain.Medicine, in scan order
+const medicineColumns = `id, name, description, price, strength, category, supplier_id, created_at, updated_at`
+
+// sortColumns maps the sort fields of a query to their column
+var sortColumns = map[string]string{
+	domain.SortByName:      "name",
+	domain.SortByPrice:     "price",
+	domain.SortByCategory:  "category",
+	domain.SortByCreatedAt: "created_at",
+	domain.SortByUpdatedAt: "updated_at",
+}
+
+// MedicineRepository is a PostgreSQL implementation of the MedicineRepository port
+type MedicineRepository struct {
+	db *sql.DB
+}
+
+// Ensure MedicineRepository implements the MedicineRepository interface
+var _ driven.MedicineRepository = (*MedicineRepository)(nil)
+
+// NewMedicineRepository creates a new PostgreSQL medicine repository
+func NewMedicineRepository(db *sql.DB) *MedicineRepository {
+	return &MedicineRepository{
+		db: db,
+	}
+}
+
+// Create adds a new medicine to the repository
+func (r *MedicineRepository) Create(ctx context.Context, medicine domain.Medicine) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO medicines (`+medicineColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO NOTHING`,
+		medicine.ID, medicine.Name, medicine.Description, medicine.Price, medicine.Strength,
+		medicine.Category, medicine.SupplierID, medicine.CreatedAt, medicine.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("medicine with id %s already exists", medicine.ID)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a medicine by its ID
+func (r *MedicineRepository) FindByID(ctx context.Context, id string) (*domain.Medicine, error) {
+	return r.findByID(ctx, `SELECT `+medicineColumns+` FROM medicines WHERE id = $1`, id)
+}
+
+// FindByIDForUpdate retrieves a medicine by its ID and locks its row until the transaction in
+// ctx ends. Outside a unit of work the row is only locked for the query.
+func (r *MedicineRepository) FindByIDForUpdate(ctx context.Context, id string) (*domain.Medicine, error) {
+	return r.findByID(ctx, `SELECT `+medicineColumns+` FROM medicines WHERE id = $1 FOR UPDATE`, id)
+}
+
+// findByID runs a query selecting a medicine by its ID
+func (r *MedicineRepository) findByID(ctx context.Context, query, id string) (*domain.Medicine, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx, query, id)
+
+	medicine, err := scanMedicine(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return medicine, nil
+}
+
+// Find returns the page of medicines matching the query and the number of matches
+func (r *MedicineRepository) Find(ctx context.Context, query domain.MedicineQuery) ([]domain.Medicine, int, error) {
+	column, ok := sortColumns[query.SortBy]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q", domain.ErrInvalidQuery, query.SortBy)
+	}
+
+	conditions := make([]string, 0, 3)
+	args := make([]any, 0, 5)
+	if query.Category != "" {
+		args = append(args, query.Category)
+		conditions = append(conditions, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if query.SupplierID != "" {
+		args = append(args, query.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("supplier_id = $%d", len(args)))
+	}
+	if query.Name != "" {
+		args = append(args, "%"+escapeLike(query.Name)+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM medicines`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count medicines: %w", err)
+	}
+
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+
+	args = append(args, query.Limit, query.Offset)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, fmt.Sprintf(
+		`SELECT %s FROM medicines%s ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d`,
+		medicineColumns, where, column, direction, direction, len(args)-1, len(args),
+	), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query medicines: %w", err)
+	}
+	defer rows.Close()
+
+	medicines := make([]domain.Medicine, 0)
+	for rows.Next() {
+		medicine, err := scanMedicine(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		medicines = append(medicines, *medicine)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate medicines: %w", err)
+	}
+
+	return medicines, total, nil
+}
+
+// Update modifies an existing medicine
+func (r *MedicineRepository) Update(ctx context.Context, medicine domain.Medicine) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE medicines
+		SET name = $2, description = $3, price = $4, strength = $5, category = $6,
+			supplier_id = $7, created_at = $8, updated_at = $9
+		WHERE id = $1`,
+		medicine.ID, medicine.Name, medicine.Description, medicine.Price, medicine.Strength,
+		medicine.Category, medicine.SupplierID, medicine.CreatedAt, medicine.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, medicine.ID)
+	}
+
+	return nil
+}
+
+// Delete removes a medicine by its ID
+func (r *MedicineRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM medicines WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete medicine: %w", err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrMedicineNotFound, id)
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanMedicine reads a medicine row selected with medicineColumns
+func scanMedicine(row rowScanner) (*domain.Medicine, error) {
+	var medicine domain.Medicine
+
+	if err := row.Scan(
+		&medicine.ID,
+		&medicine.Name,
+		&medicine.Description,
+		&medicine.Price,
+		&medicine.Strength,
+		&medicine.Category,
+		&medicine.SupplierID,
+		&medicine.CreatedAt,
+		&medicine.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan medicine: %w", err)
+	}
+
+	return &medicine, nil
+}
+
+// escapeLike escapes the LIKE wildcards in a search term, so it matches literally
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"suppliers/internal/adapter/storage/storagetest"
+	"testing"
+)
+
+func TestMedicineRepository(t *testing.T) {
+	storagetest.RunMedicineRepositoryTests(t, func(t *testing.T) storagetest.Store {
+		db := emptyDB(t, "medicines", "outbox")
+		return storagetest.Store{
+			Medicines:  NewMedicineRepository(db),
+			Outbox:     NewOutbox(db),
+			Transactor: NewTransactor(db),
+		}
+	})
+}
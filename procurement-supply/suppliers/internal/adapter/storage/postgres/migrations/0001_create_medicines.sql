@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS medicines (
+    id          TEXT PRIMARY KEY,
+    name        TEXT             NOT NULL,
+    description TEXT             NOT NULL DEFAULT '',
+    price       DOUBLE PRECISION NOT NULL,
+    strength    TEXT             NOT NULL DEFAULT '',
+    category    TEXT             NOT NULL,
+    supplier_id TEXT             NOT NULL,
+    created_at  TIMESTAMPTZ      NOT NULL,
+    updated_at  TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_medicines_category ON medicines (category);
+CREATE INDEX IF NOT EXISTS idx_medicines_supplier_id ON medicines (supplier_id);
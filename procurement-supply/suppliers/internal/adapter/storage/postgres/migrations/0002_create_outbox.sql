@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    seq           BIGSERIAL PRIMARY KEY,
+    event_id      TEXT        NOT NULL UNIQUE,
+    event_type    TEXT        NOT NULL,
+    aggregate_id  TEXT        NOT NULL,
+    payload       JSONB       NOT NULL,
+    trace_context JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    attempts      INTEGER     NOT NULL DEFAULT 0,
+    last_error    TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL
+);
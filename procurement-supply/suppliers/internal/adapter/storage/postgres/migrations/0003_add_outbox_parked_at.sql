@@ -0,0 +1,3 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS parked_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_waiting ON outbox (aggregate_id, seq) WHERE parked_at IS NULL;
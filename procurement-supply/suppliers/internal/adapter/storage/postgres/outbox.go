@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"medisupply-infrastructure/pkg/telemetry/tracing"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+	"time"
+)
+
+// Outbox is a PostgreSQL implementation of the Outbox port. Events are kept in the outbox
+// table until published, in the order they were enqueued.
+type Outbox struct {
+	db         *sql.DB
+	transactor *Transactor
+}
+
+// Ensure Outbox implements the Outbox interface
+var _ driven.Outbox = (*Outbox)(nil)
+
+// NewOutbox creates a new PostgreSQL outbox
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{
+		db:         db,
+		transactor: NewTransactor(db),
+	}
+}
+
+// Enqueue adds an event to the outbox, along with the trace context of ctx
+func (o *Outbox) Enqueue(ctx context.Context, event *domain.Event[domain.Medicine]) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	traceContext, err := json.Marshal(tracing.InjectMap(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace context: %w", err)
+	}
+
+	if _, err := conn(ctx, o.db).ExecContext(ctx, `
+		INSERT INTO outbox (event_id, event_type, aggregate_id, payload, trace_context, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.ID, string(event.EventType), event.Data.ID, payload, traceContext, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// Claim runs fn with up to limit events waiting to be published, oldest first, within a
+// transaction holding their rows. Rows locked by another relay are skipped, as are the events
+// whose medicine has an older event still waiting, which is either locked or claimed first.
+func (o *Outbox) Claim(ctx context.Context, limit int, fn func(ctx context.Context, entries []domain.OutboxEntry) error) error {
+	var fnErr error
+	err := o.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		entries, err := o.claim(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		// The outcomes recorded by fn are committed even when it fails
+		fnErr = fn(ctx, entries)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return fnErr
+}
+
+// claim locks and returns the events to publish
+func (o *Outbox) claim(ctx context.Context, limit int) ([]domain.OutboxEntry, error) {
+	rows, err := conn(ctx, o.db).QueryContext(ctx, `
+		SELECT payload, trace_context, attempts, last_error, created_at
+		FROM outbox o
+		WHERE parked_at IS NULL
+		  AND NOT EXISTS (
+		      SELECT 1 FROM outbox earlier
+		      WHERE earlier.aggregate_id = o.aggregate_id
+		        AND earlier.seq < o.seq
+		        AND earlier.parked_at IS NULL
+		  )
+		ORDER BY seq
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.OutboxEntry, 0)
+	for rows.Next() {
+		var entry domain.OutboxEntry
+		var payload, traceContext []byte
+
+		if err := rows.Scan(&payload, &traceContext, &entry.Attempts, &entry.LastError, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		if err := json.Unmarshal(payload, &entry.Event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outbox event: %w", err)
+		}
+		if err := json.Unmarshal(traceContext, &entry.TraceContext); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal trace context: %w", err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate outbox: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkPublished removes a published event from the outbox
+func (o *Outbox) MarkPublished(ctx context.Context, eventID string) error {
+	if _, err := conn(ctx, o.db).ExecContext(ctx, `DELETE FROM outbox WHERE event_id = $1`, eventID); err != nil {
+		return fmt.Errorf("failed to remove outbox event: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt to publish an event
+func (o *Outbox) MarkFailed(ctx context.Context, eventID string, cause error) error {
+	if _, err := conn(ctx, o.db).ExecContext(ctx, `
+		UPDATE outbox SET attempts = attempts + 1, last_error = $2
+		WHERE event_id = $1`, eventID, cause.Error(),
+	); err != nil {
+		return fmt.Errorf("failed to update outbox event: %w", err)
+	}
+	return nil
+}
+
+// Park records a last failed attempt to publish an event and sets it aside
+func (o *Outbox) Park(ctx context.Context, eventID string, cause error) error {
+	if _, err := conn(ctx, o.db).ExecContext(ctx, `
+		UPDATE outbox SET attempts = attempts + 1, last_error = $2, parked_at = $3
+		WHERE event_id = $1`, eventID, cause.Error(), time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("failed to park outbox event: %w", err)
+	}
+	return nil
+}
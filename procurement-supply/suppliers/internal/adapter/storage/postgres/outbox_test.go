@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"slices"
+	"suppliers/internal/core/domain"
+	"testing"
+)
+
+// enqueue adds an update event of a medicine to the outbox and returns its ID
+func enqueue(t *testing.T, outbox *Outbox, medicineID string) string {
+	t.Helper()
+
+	event := domain.NewMedicineEvent(domain.Medicine{ID: medicineID, Name: "Medicine " + medicineID}, domain.MedicineUpdatedEvent)
+	if err := outbox.Enqueue(context.Background(), event); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	return event.ID
+}
+
+// eventIDs returns the IDs of the claimed events, in order
+func eventIDs(entries []domain.OutboxEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.Event.ID)
+	}
+	return ids
+}
+
+func TestOutboxClaimSkipsTheEventsOfAnotherRelay(t *testing.T) {
+	db := emptyDB(t, "outbox")
+	ctx := context.Background()
+	first, second := NewOutbox(db), NewOutbox(db)
+
+	m1First := enqueue(t, first, "m1")
+	m1Second := enqueue(t, first, "m1")
+	m2First := enqueue(t, first, "m2")
+
+	err := first.Claim(ctx, 1, func(ctx context.Context, entries []domain.OutboxEntry) error {
+		if got := eventIDs(entries); !slices.Equal(got, []string{m1First}) {
+			t.Fatalf("first relay claimed %v, want %v", got, []string{m1First})
+		}
+
+		// While the first relay holds the oldest event of m1, the second one skips it and the
+		// later event of m1 behind it, and claims the event of m2
+		err := second.Claim(ctx, 10, func(ctx context.Context, entries []domain.OutboxEntry) error {
+			if got := eventIDs(entries); !slices.Equal(got, []string{m2First}) {
+				t.Errorf("second relay claimed %v, want %v", got, []string{m2First})
+			}
+			return second.MarkPublished(ctx, m2First)
+		})
+		if err != nil {
+			t.Fatalf("second Claim() error = %v", err)
+		}
+
+		return first.MarkPublished(ctx, m1First)
+	})
+	if err != nil {
+		t.Fatalf("first Claim() error = %v", err)
+	}
+
+	// Once the first relay published its event, the next event of m1 is claimed
+	err = second.Claim(ctx, 10, func(_ context.Context, entries []domain.OutboxEntry) error {
+		if got := eventIDs(entries); !slices.Equal(got, []string{m1Second}) {
+			t.Errorf("claimed %v, want %v", got, []string{m1Second})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+}
+
+func TestOutboxClaimSkipsParkedEvents(t *testing.T) {
+	db := emptyDB(t, "outbox")
+	ctx := context.Background()
+	outbox := NewOutbox(db)
+
+	parked := enqueue(t, outbox, "m1")
+	next := enqueue(t, outbox, "m1")
+
+	err := outbox.Claim(ctx, 10, func(ctx context.Context, entries []domain.OutboxEntry) error {
+		return outbox.Park(ctx, parked, domain.ErrEventRejected)
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+
+	err = outbox.Claim(ctx, 10, func(_ context.Context, entries []domain.OutboxEntry) error {
+		if got := eventIDs(entries); !slices.Equal(got, []string{next}) {
+			t.Errorf("claimed %v, want %v", got, []string{next})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+}
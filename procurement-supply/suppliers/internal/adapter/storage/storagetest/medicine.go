@@ -0,0 +1,364 @@
+// Package storagetest holds the test suites every storage adapter of the service must pass
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"suppliers/internal/core/application"
+	"suppliers/internal/core/domain"
+	"suppliers/internal/core/port/driven"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Store is a set of adapters sharing the same storage, wired like the service wires them
+type Store struct {
+	Medicines  driven.MedicineRepository
+	Outbox     driven.Outbox
+	Transactor driven.Transactor
+}
+
+// NewMedicine returns a medicine with the given ID and every field set
+func NewMedicine(id, name, category, supplierID string, price float64) domain.Medicine {
+	return domain.Medicine{
+		ID:          id,
+		Name:        name,
+		Description: "Description of " + name,
+		Price:       price,
+		Strength:    "500mg",
+		Category:    category,
+		SupplierID:  supplierID,
+		CreatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// catalog is the medicines the query tests run against. Prices are unique so sorting by price
+// gives a single order: m4, m5, m1, m6, m2, m3.
+var catalog = []domain.Medicine{
+	NewMedicine("m1", "Aspirin", "analgesic", "supplier-1", 5),
+	NewMedicine("m2", "Ibuprofen", "analgesic", "supplier-2", 8),
+	NewMedicine("m3", "Amoxicillin", "antibiotic", "supplier-1", 12),
+	NewMedicine("m4", "Vitamin C 100%", "supplement", "supplier-2", 3),
+	NewMedicine("m5", "Zinc_1", "supplement", "supplier-2", 4),
+	NewMedicine("m6", "ZincA1", "supplement", "supplier-2", 6),
+}
+
+// RunMedicineRepositoryTests runs the medicine repository test suite. newStore must return
+// adapters over an empty storage on every call.
+func RunMedicineRepositoryTests(t *testing.T, newStore func(t *testing.T) Store) {
+	ctx := context.Background()
+
+	t.Run("create and find", func(t *testing.T) {
+		repo := newStore(t).Medicines
+		medicine := NewMedicine("m1", "Aspirin", "analgesic", "supplier-1", 5)
+
+		if err := repo.Create(ctx, medicine); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.Create(ctx, medicine); err == nil {
+			t.Error("Create() of an existing medicine succeeded")
+		}
+
+		found, err := repo.FindByID(ctx, "m1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertMedicine(t, *found, medicine)
+
+		locked, err := repo.FindByIDForUpdate(ctx, "m1")
+		if err != nil {
+			t.Fatalf("FindByIDForUpdate() error = %v", err)
+		}
+		assertMedicine(t, *locked, medicine)
+	})
+
+	t.Run("missing medicine", func(t *testing.T) {
+		repo := newStore(t).Medicines
+
+		if _, err := repo.FindByID(ctx, "missing"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("FindByID() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+		if _, err := repo.FindByIDForUpdate(ctx, "missing"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("FindByIDForUpdate() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+		if err := repo.Update(ctx, NewMedicine("missing", "Aspirin", "analgesic", "supplier-1", 5)); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("Update() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+		if err := repo.Delete(ctx, "missing"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("Delete() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		repo := newStore(t).Medicines
+		if err := repo.Create(ctx, NewMedicine("m1", "Aspirin", "analgesic", "supplier-1", 5)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		updated := NewMedicine("m1", "Aspirin Forte", "analgesic", "supplier-2", 7.5)
+		updated.Strength = "1g"
+		updated.UpdatedAt = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+		if err := repo.Update(ctx, updated); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		found, err := repo.FindByID(ctx, "m1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		assertMedicine(t, *found, updated)
+
+		if err := repo.Delete(ctx, "m1"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := repo.FindByID(ctx, "m1"); !errors.Is(err, domain.ErrMedicineNotFound) {
+			t.Errorf("FindByID() after Delete() error = %v, want %v", err, domain.ErrMedicineNotFound)
+		}
+	})
+
+	t.Run("find", func(t *testing.T) {
+		repo := newStore(t).Medicines
+		for _, medicine := range catalog {
+			if err := repo.Create(ctx, medicine); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		tests := []struct {
+			name  string
+			query domain.MedicineQuery
+			want  []string
+			total int
+		}{
+			{
+				name:  "everything",
+				query: domain.MedicineQuery{SortBy: domain.SortByPrice},
+				want:  []string{"m4", "m5", "m1", "m6", "m2", "m3"},
+				total: 6,
+			},
+			{
+				name:  "by category",
+				query: domain.MedicineQuery{Category: "analgesic", SortBy: domain.SortByPrice},
+				want:  []string{"m1", "m2"},
+				total: 2,
+			},
+			{
+				name:  "by supplier",
+				query: domain.MedicineQuery{SupplierID: "supplier-1", SortBy: domain.SortByPrice},
+				want:  []string{"m1", "m3"},
+				total: 2,
+			},
+			{
+				name:  "by name ignoring case",
+				query: domain.MedicineQuery{Name: "ASP", SortBy: domain.SortByPrice},
+				want:  []string{"m1"},
+				total: 1,
+			},
+			{
+				name:  "by name with a literal percent sign",
+				query: domain.MedicineQuery{Name: "%", SortBy: domain.SortByPrice},
+				want:  []string{"m4"},
+				total: 1,
+			},
+			{
+				name:  "by name with a literal underscore",
+				query: domain.MedicineQuery{Name: "c_1", SortBy: domain.SortByPrice},
+				want:  []string{"m5"},
+				total: 1,
+			},
+			{
+				name:  "by every filter",
+				query: domain.MedicineQuery{Category: "supplement", SupplierID: "supplier-2", Name: "zinc", SortBy: domain.SortByPrice},
+				want:  []string{"m5", "m6"},
+				total: 2,
+			},
+			{
+				name:  "no match",
+				query: domain.MedicineQuery{Category: "vaccine", SortBy: domain.SortByPrice},
+				want:  []string{},
+				total: 0,
+			},
+			{
+				name:  "descending",
+				query: domain.MedicineQuery{SortBy: domain.SortByPrice, Descending: true},
+				want:  []string{"m3", "m2", "m6", "m1", "m5", "m4"},
+				total: 6,
+			},
+			{
+				name:  "ties broken by id",
+				query: domain.MedicineQuery{SortBy: domain.SortByCategory},
+				want:  []string{"m1", "m2", "m3", "m4", "m5", "m6"},
+				total: 6,
+			},
+			{
+				name:  "ties broken by id descending",
+				query: domain.MedicineQuery{SortBy: domain.SortByCategory, Descending: true},
+				want:  []string{"m6", "m5", "m4", "m3", "m2", "m1"},
+				total: 6,
+			},
+			{
+				name:  "page",
+				query: domain.MedicineQuery{SortBy: domain.SortByPrice, Limit: 2, Offset: 2},
+				want:  []string{"m1", "m6"},
+				total: 6,
+			},
+			{
+				name:  "last page",
+				query: domain.MedicineQuery{SortBy: domain.SortByPrice, Limit: 4, Offset: 4},
+				want:  []string{"m2", "m3"},
+				total: 6,
+			},
+			{
+				name:  "page past the end",
+				query: domain.MedicineQuery{SortBy: domain.SortByPrice, Limit: 2, Offset: 10},
+				want:  []string{},
+				total: 6,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if tt.query.Limit == 0 {
+					tt.query.Limit = domain.MaxMedicineLimit
+				}
+
+				medicines, total, err := repo.Find(ctx, tt.query)
+				if err != nil {
+					t.Fatalf("Find() error = %v", err)
+				}
+				if total != tt.total {
+					t.Errorf("Find() total = %d, want %d", total, tt.total)
+				}
+				if ids := medicineIDs(medicines); !slices.Equal(ids, tt.want) {
+					t.Errorf("Find() = %v, want %v", ids, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("find with an unsupported sort", func(t *testing.T) {
+		repo := newStore(t).Medicines
+
+		for _, sortBy := range []string{"supplier_id", "name; DROP TABLE medicines"} {
+			query := domain.MedicineQuery{SortBy: sortBy, Limit: domain.DefaultMedicineLimit}
+			if _, _, err := repo.Find(ctx, query); !errors.Is(err, domain.ErrInvalidQuery) {
+				t.Errorf("Find() sorted by %q error = %v, want %v", sortBy, err, domain.ErrInvalidQuery)
+			}
+		}
+	})
+
+	t.Run("delete event carries the whole medicine", func(t *testing.T) {
+		store := newStore(t)
+		service := application.NewMedicineService(store.Medicines, store.Outbox, store.Transactor)
+		medicine := NewMedicine("m1", "Aspirin", "analgesic", "supplier-1", 5)
+		if err := store.Medicines.Create(ctx, medicine); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		if err := service.DeleteMedicine(ctx, "m1"); err != nil {
+			t.Fatalf("DeleteMedicine() error = %v", err)
+		}
+
+		events := drainOutbox(t, store.Outbox)
+		if len(events) != 1 {
+			t.Fatalf("outbox holds %d events, want 1", len(events))
+		}
+		if events[0].EventType != domain.MedicineDeletedEvent {
+			t.Errorf("event type = %s, want %s", events[0].EventType, domain.MedicineDeletedEvent)
+		}
+		assertMedicine(t, events[0].Data, medicine)
+	})
+
+	t.Run("concurrent updates are enqueued in order", func(t *testing.T) {
+		store := newStore(t)
+		service := application.NewMedicineService(store.Medicines, store.Outbox, store.Transactor)
+		if err := store.Medicines.Create(ctx, NewMedicine("m1", "Aspirin", "analgesic", "supplier-1", 5)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		const updates = 8
+		var wg sync.WaitGroup
+		for i := range updates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				update := NewMedicine("", fmt.Sprintf("Aspirin %d", i), "analgesic", "supplier-1", float64(i))
+				if _, err := service.UpdateMedicine(ctx, "m1", &update); err != nil {
+					t.Errorf("UpdateMedicine() error = %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		events := drainOutbox(t, store.Outbox)
+		if len(events) != updates {
+			t.Fatalf("outbox holds %d events, want %d", len(events), updates)
+		}
+		for i := 1; i < len(events); i++ {
+			if events[i].Data.UpdatedAt.Before(events[i-1].Data.UpdatedAt) {
+				t.Errorf("event %d updated at %v, before the previous one at %v", i, events[i].Data.UpdatedAt, events[i-1].Data.UpdatedAt)
+			}
+		}
+
+		stored, err := store.Medicines.FindByID(ctx, "m1")
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if last := events[len(events)-1].Data; stored.Name != last.Name {
+			t.Errorf("stored medicine = %q, want the last update %q", stored.Name, last.Name)
+		}
+	})
+}
+
+// drainOutbox claims and publishes every event in the outbox, returning them in claim order
+func drainOutbox(t *testing.T, outbox driven.Outbox) []domain.Event[domain.Medicine] {
+	t.Helper()
+
+	var events []domain.Event[domain.Medicine]
+	for {
+		var claimed int
+		err := outbox.Claim(context.Background(), 10, func(ctx context.Context, entries []domain.OutboxEntry) error {
+			claimed = len(entries)
+			for _, entry := range entries {
+				events = append(events, entry.Event)
+				if err := outbox.MarkPublished(ctx, entry.Event.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Claim() error = %v", err)
+		}
+		if claimed == 0 {
+			return events
+		}
+	}
+}
+
+// medicineIDs returns the IDs of the medicines, in order
+func medicineIDs(medicines []domain.Medicine) []string {
+	ids := make([]string, 0, len(medicines))
+	for _, medicine := range medicines {
+		ids = append(ids, medicine.ID)
+	}
+	return ids
+}
+
+// assertMedicine compares two medicines, timestamps by the instant they represent
+func assertMedicine(t *testing.T, got, want domain.Medicine) {
+	t.Helper()
+
+	if !got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("timestamps = %v, %v, want %v, %v", got.CreatedAt, got.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+	}
+
+	got.CreatedAt, got.UpdatedAt = want.CreatedAt, want.UpdatedAt
+	if got != want {
+		t.Errorf("medicine = %+v, want %+v", got, want)
+	}
+}
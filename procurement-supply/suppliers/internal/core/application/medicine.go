@@ -1,7 +1,9 @@
 package application
 
 import (
+	"context"
 	"fmt"
+	"procurement-supply/pkg/events"
 	"suppliers/internal/core/domain"
 	"suppliers/internal/core/port/driven"
 	"time"
@@ -9,73 +11,105 @@ import (
 	"github.com/google/uuid"
 )
 
+// MedicineService manages the medicine catalog. Every change is written together with the
+// event announcing it to the outbox, which is published separately, so an unavailable broker
+// never fails or undoes a change.
 type MedicineService struct {
-	publisher driven.EventPublisher
+	repository driven.MedicineRepository
+	outbox     driven.Outbox
+	transactor driven.Transactor
 }
 
-func NewMedicineService(publisher driven.EventPublisher) *MedicineService {
+func NewMedicineService(repository driven.MedicineRepository, outbox driven.Outbox, transactor driven.Transactor) *MedicineService {
 	return &MedicineService{
-		publisher: publisher,
+		repository: repository,
+		outbox:     outbox,
+		transactor: transactor,
 	}
 }
 
-func (s *MedicineService) RetrieveMedicines() ([]domain.Medicine, error) {
-	// TODO: Implement repository integration to fetch medicines from database
-	return []domain.Medicine{}, nil
+func (s *MedicineService) RetrieveMedicines(ctx context.Context, query domain.MedicineQuery) ([]domain.Medicine, int, error) {
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repository.Find(ctx, query)
 }
 
-func (s *MedicineService) RetrieveMedicine(id string) (*domain.Medicine, error) {
-	// TODO: Implement repository integration to fetch medicine by ID from database
-	return nil, fmt.Errorf("medicine not found")
+func (s *MedicineService) RetrieveMedicine(ctx context.Context, id string) (*domain.Medicine, error) {
+	return s.repository.FindByID(ctx, id)
 }
 
-func (s *MedicineService) CreateMedicine(medicine *domain.Medicine) (*domain.Medicine, error) {
+func (s *MedicineService) CreateMedicine(ctx context.Context, medicine *domain.Medicine) (*domain.Medicine, error) {
 	// Generate ID and timestamps
 	medicine.ID = uuid.New().String()
-	medicine.CreatedAt = time.Now()
-	medicine.UpdatedAt = time.Now()
-
-	// TODO: Implement repository integration to save medicine to database
-
-	// Create and publish the event
-	event := domain.NewMedicineEvent(*medicine, domain.MedicineCreatedEvent)
-
-	if err := s.publisher.PublishMedicineEvent(event); err != nil {
-		return nil, fmt.Errorf("failed to publish event: %w", err)
+	medicine.CreatedAt = time.Now().UTC()
+	medicine.UpdatedAt = medicine.CreatedAt
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repository.Create(ctx, *medicine); err != nil {
+			return fmt.Errorf("failed to save medicine: %w", err)
+		}
+
+		return s.enqueue(ctx, *medicine, domain.MedicineCreatedEvent)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return medicine, nil
 }
 
-func (s *MedicineService) UpdateMedicine(id string, medicine *domain.Medicine) (*domain.Medicine, error) {
-	// TODO: Implement repository integration to check if medicine exists and update it
-
-	// Update timestamp
-	medicine.ID = id
-	medicine.UpdatedAt = time.Now()
-
-	// Create and publish the event
-	event := domain.NewMedicineEvent(*medicine, domain.MedicineUpdatedEvent)
-
-	if err := s.publisher.PublishMedicineEvent(event); err != nil {
-		return nil, fmt.Errorf("failed to publish event: %w", err)
+func (s *MedicineService) UpdateMedicine(ctx context.Context, id string, medicine *domain.Medicine) (*domain.Medicine, error) {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the medicine first, so concurrent updates are stored, and their events enqueued, in
+		// the order of their update timestamps
+		existing, err := s.repository.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Keep the identity and creation date, refresh the update timestamp
+		medicine.ID = id
+		medicine.CreatedAt = existing.CreatedAt
+		medicine.UpdatedAt = time.Now().UTC()
+
+		if err := s.repository.Update(ctx, *medicine); err != nil {
+			return fmt.Errorf("failed to update medicine: %w", err)
+		}
+
+		return s.enqueue(ctx, *medicine, domain.MedicineUpdatedEvent)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return medicine, nil
 }
 
-func (s *MedicineService) DeleteMedicine(id string) error {
-	// TODO: Implement repository integration to fetch and delete medicine from database
+func (s *MedicineService) DeleteMedicine(ctx context.Context, id string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Load the medicine first, so the event carries everything consumers knew about it
+		medicine, err := s.repository.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	// For now, create a dummy medicine object for the event
-	medicine := domain.Medicine{ID: id}
+		if err := s.repository.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete medicine: %w", err)
+		}
 
-	// Create and publish the event
-	event := domain.NewMedicineEvent(medicine, domain.MedicineDeletedEvent)
+		return s.enqueue(ctx, *medicine, domain.MedicineDeletedEvent)
+	})
+}
 
-	if err := s.publisher.PublishMedicineEvent(event); err != nil {
-		return fmt.Errorf("failed to publish event: %w", err)
-	}
+// enqueue writes the event announcing a change to the outbox, within the transaction of the change
+func (s *MedicineService) enqueue(ctx context.Context, medicine domain.Medicine, eventType domain.EventType) error {
+	event := domain.NewMedicineEvent(medicine, eventType)
+	event.CorrelationID = events.CorrelationID(ctx)
 
+	if err := s.outbox.Enqueue(ctx, event); err != nil {
+		return fmt.Errorf("failed to enqueue event: %w", err)
+	}
 	return nil
 }
@@ -1,16 +1,21 @@
 package domain
 
 import (
-	"time"
+	"errors"
+	"procurement-supply/pkg/events"
 )
 
-type EventType string
+// ErrEventRejected is wrapped by the publish errors an event would hit again on every attempt,
+// such as exceeding the largest message the broker accepts
+var ErrEventRejected = errors.New("event rejected")
 
-type Event[T any] struct {
-	EventType `json:"event_type"`
-	Data      T         `json:"data"`
-	Timestamp time.Time `json:"timestamp"`
-}
+// EventSource names this service as the source of the events it raises
+const EventSource = "suppliers"
+
+type EventType = events.Type
+
+// Event is a domain event wrapped in the envelope shared with the consumers
+type Event[T any] = events.Envelope[T]
 
 const (
 	MedicineCreatedEvent EventType = "medicine.created"
@@ -20,9 +25,5 @@ const (
 
 // NewMedicineEvent creates a new medicine event with validation
 func NewMedicineEvent(data Medicine, event EventType) *Event[Medicine] {
-	return &Event[Medicine]{
-		EventType: event,
-		Data:      data,
-		Timestamp: time.Now(),
-	}
+	return events.New(event, EventSource, data)
 }
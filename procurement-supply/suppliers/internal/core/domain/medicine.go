@@ -1,6 +1,16 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrMedicineNotFound is returned when no medicine has the requested ID
+var ErrMedicineNotFound = errors.New("medicine not found")
+
+// ErrInvalidQuery is returned when a medicine query has an unsupported sort field or page bounds
+var ErrInvalidQuery = errors.New("invalid medicine query")
 
 type Medicine struct {
 	ID          string    `json:"id"`
@@ -13,3 +23,52 @@ type Medicine struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// Fields medicines can be sorted by
+const (
+	SortByName      = "name"
+	SortByPrice     = "price"
+	SortByCategory  = "category"
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+)
+
+// Page size bounds of a medicine query
+const (
+	DefaultMedicineLimit = 20
+	MaxMedicineLimit     = 100
+)
+
+// MedicineQuery filters, sorts and paginates the medicine catalog. Empty filters match every medicine.
+type MedicineQuery struct {
+	Category   string // exact category
+	SupplierID string // exact supplier
+	Name       string // case-insensitive substring of the name
+	SortBy     string // one of the SortBy fields, ties are broken by ID
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// Validate fills in the defaults of a query and rejects unsupported values
+func (q *MedicineQuery) Validate() error {
+	switch q.SortBy {
+	case "":
+		q.SortBy = SortByName
+	case SortByName, SortByPrice, SortByCategory, SortByCreatedAt, SortByUpdatedAt:
+	default:
+		return fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, q.SortBy)
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultMedicineLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxMedicineLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxMedicineLimit)
+	}
+	if q.Offset < 0 {
+		return fmt.Errorf("%w: offset cannot be negative", ErrInvalidQuery)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// OutboxEntry is a medicine event waiting in the outbox to be published
+type OutboxEntry struct {
+	Event        Event[Medicine]
+	TraceContext map[string]string // trace of the request that raised the event
+	Attempts     int               // failed publish attempts so far
+	LastError    string
+	CreatedAt    time.Time
+}
@@ -0,0 +1,35 @@
+package driven
+
+import (
+	"context"
+	"suppliers/internal/core/domain"
+)
+
+// Transactor runs a unit of work atomically. The repository and outbox calls made with the
+// context passed to fn all commit together, or not at all when fn returns an error.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Outbox stores the events raised by a change next to the change itself, until they are published
+type Outbox interface {
+	// Enqueue adds an event to the outbox, as part of the transaction in ctx if any
+	Enqueue(ctx context.Context, event *domain.Event[domain.Medicine]) error
+
+	// Claim runs fn with up to limit events waiting to be published, oldest first, and keeps other
+	// relays from claiming them until fn returns. Only the oldest event of each medicine that is not
+	// parked is claimed, so the events of a medicine are published in order even by several relays.
+	// The MarkPublished, MarkFailed and Park calls made with the context passed to fn are committed
+	// once it returns, even when it returns an error.
+	Claim(ctx context.Context, limit int, fn func(ctx context.Context, entries []domain.OutboxEntry) error) error
+
+	// MarkPublished removes a published event from the outbox
+	MarkPublished(ctx context.Context, eventID string) error
+
+	// MarkFailed records a failed attempt to publish an event, which stays in the outbox
+	MarkFailed(ctx context.Context, eventID string, cause error) error
+
+	// Park records a last failed attempt to publish an event and sets it aside: it stays in the
+	// outbox for inspection but is no longer claimed
+	Park(ctx context.Context, eventID string, cause error) error
+}
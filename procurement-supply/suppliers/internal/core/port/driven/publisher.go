@@ -1,9 +1,17 @@
 package driven
 
-import "suppliers/internal/core/domain"
+import (
+	"context"
+	"suppliers/internal/core/domain"
+)
 
-// EventPublisher defines the interface for publishing domain events
+// EventPublisher defines the interface for publishing domain events. A publish error wraps
+// domain.ErrEventRejected when publishing the same event again cannot succeed.
 type EventPublisher interface {
-	PublishMedicineEvent(event *domain.Event[domain.Medicine]) error
+	// PublishMedicineEvent publishes an event and waits until it is acknowledged
+	PublishMedicineEvent(ctx context.Context, event *domain.Event[domain.Medicine]) error
+
+	// PublishMedicineEventAsync queues an event and returns a channel receiving the outcome of its delivery
+	PublishMedicineEventAsync(ctx context.Context, event *domain.Event[domain.Medicine]) <-chan error
 	Close()
 }
@@ -0,0 +1,28 @@
+package driven
+
+import (
+	"context"
+	"suppliers/internal/core/domain"
+)
+
+// MedicineRepository defines the interface for persisting the medicine catalog
+type MedicineRepository interface {
+	// Create adds a new medicine
+	Create(ctx context.Context, medicine domain.Medicine) error
+
+	// FindByID retrieves a medicine by its ID, failing with domain.ErrMedicineNotFound
+	FindByID(ctx context.Context, id string) (*domain.Medicine, error)
+
+	// FindByIDForUpdate retrieves a medicine like FindByID and locks it until the transaction in
+	// ctx ends, so concurrent units of work changing the same medicine run one after the other
+	FindByIDForUpdate(ctx context.Context, id string) (*domain.Medicine, error)
+
+	// Find returns the page of medicines matching a validated query, and how many match in total
+	Find(ctx context.Context, query domain.MedicineQuery) ([]domain.Medicine, int, error)
+
+	// Update replaces an existing medicine, failing with domain.ErrMedicineNotFound
+	Update(ctx context.Context, medicine domain.Medicine) error
+
+	// Delete removes a medicine by its ID, failing with domain.ErrMedicineNotFound
+	Delete(ctx context.Context, id string) error
+}
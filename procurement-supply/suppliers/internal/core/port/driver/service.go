@@ -1,11 +1,15 @@
 package driver
 
-import "suppliers/internal/core/domain"
+import (
+	"context"
+	"suppliers/internal/core/domain"
+)
 
 type MedicineService interface {
-	RetrieveMedicines() ([]domain.Medicine, error)
-	RetrieveMedicine(id string) (*domain.Medicine, error)
-	CreateMedicine(medicine *domain.Medicine) (*domain.Medicine, error)
-	UpdateMedicine(id string, medicine *domain.Medicine) (*domain.Medicine, error)
-	DeleteMedicine(id string) error
+	// RetrieveMedicines returns the page of medicines matching the query, and how many match in total
+	RetrieveMedicines(ctx context.Context, query domain.MedicineQuery) ([]domain.Medicine, int, error)
+	RetrieveMedicine(ctx context.Context, id string) (*domain.Medicine, error)
+	CreateMedicine(ctx context.Context, medicine *domain.Medicine) (*domain.Medicine, error)
+	UpdateMedicine(ctx context.Context, id string, medicine *domain.Medicine) (*domain.Medicine, error)
+	DeleteMedicine(ctx context.Context, id string) error
 }
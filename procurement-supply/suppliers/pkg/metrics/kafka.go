@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var kafkaMessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_messages_published_total",
+	Help: "Kafka messages published, by topic and result (success or failure).",
+}, []string{"topic", "result"})
+
+// KafkaMessagePublished counts a published message, failed when err is not nil
+func KafkaMessagePublished(topic string, err error) {
+	kafkaMessagesPublished.WithLabelValues(topic, result(err)).Inc()
+}
+
+var kafkaDeliveryFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "kafka_delivery_failures_total",
+	Help: "Kafka messages the broker failed to deliver, by topic and event type.",
+}, []string{"topic", "event_type"})
+
+// KafkaDeliveryFailed counts a message whose delivery report came back failed
+func KafkaDeliveryFailed(topic, eventType string) {
+	kafkaDeliveryFailures.WithLabelValues(topic, eventType).Inc()
+}
+
+// result labels the outcome of an operation
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}